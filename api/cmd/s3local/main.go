@@ -18,11 +18,15 @@ import (
 	"golang.org/x/net/http2"
 	"golang.org/x/net/http2/h2c"
 
+	"github.com/tkasuz/s3local/internal/auth"
+	"github.com/tkasuz/s3local/internal/blob"
 	"github.com/tkasuz/s3local/internal/config"
 	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/admin"
 	"github.com/tkasuz/s3local/internal/handlers/bucket"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/object"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
 	"github.com/tkasuz/s3local/internal/worker"
 )
 
@@ -30,7 +34,9 @@ const (
 	defaultPort     = "8080"
 	defaultHost     = "0.0.0.0"
 	defaultDBPath   = "s3local.db"
+	defaultDataDir  = "data"
 	shutdownTimeout = 30 * time.Second
+	requestTimeout  = 60 * time.Second
 )
 
 // bucketPutHandler routes PUT /{bucket} requests based on query parameters
@@ -47,6 +53,10 @@ func bucketPutHandler(w http.ResponseWriter, r *http.Request) {
 		bucket.PutBucketNotificationConfiguration(w, r)
 		return
 	}
+	if r.URL.Query().Has("versioning") {
+		bucket.PutBucketVersioning(w, r)
+		return
+	}
 	bucket.CreateBucket(w, r)
 }
 
@@ -64,9 +74,30 @@ func bucketGetHandler(w http.ResponseWriter, r *http.Request) {
 		bucket.GetBucketNotificationConfiguration(w, r)
 		return
 	}
+	if r.URL.Query().Has("versioning") {
+		bucket.GetBucketVersioning(w, r)
+		return
+	}
+	if r.URL.Query().Has("uploads") {
+		object.ListMultipartUploads(w, r)
+		return
+	}
+	if r.URL.Query().Has("versions") {
+		object.ListObjectVersions(w, r)
+		return
+	}
 	object.ListObjectsV2(w, r)
 }
 
+// bucketPostHandler routes POST /{bucket} requests based on query parameters
+func bucketPostHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("delete") {
+		object.DeleteObjects(w, r)
+		return
+	}
+	s3error.NewInvalidArgumentError("Unsupported POST operation").WriteError(w)
+}
+
 // bucketDeleteHandler routes DELETE /{bucket} requests based on query parameters
 func bucketDeleteHandler(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Query().Has("tagging") {
@@ -86,15 +117,44 @@ func objectPutHandler(w http.ResponseWriter, r *http.Request) {
 		object.PutObjectTagging(w, r)
 		return
 	}
+	if r.URL.Query().Has("uploadId") {
+		if r.Header.Get("x-amz-copy-source") != "" {
+			object.UploadPartCopy(w, r)
+			return
+		}
+		object.UploadPart(w, r)
+		return
+	}
+	if r.Header.Get("x-amz-copy-source") != "" {
+		object.CopyObject(w, r)
+		return
+	}
 	object.PutObject(w, r)
 }
 
+// objectPostHandler routes POST /{bucket}/{key} requests based on query parameters
+func objectPostHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("uploads") {
+		object.CreateMultipartUpload(w, r)
+		return
+	}
+	if r.URL.Query().Has("uploadId") {
+		object.CompleteMultipartUpload(w, r)
+		return
+	}
+	s3error.NewInvalidArgumentError("Unsupported POST operation").WriteError(w)
+}
+
 // objectGetHandler routes GET /{bucket}/{key} requests based on query parameters
 func objectGetHandler(w http.ResponseWriter, r *http.Request) {
 	if r.URL.Query().Has("tagging") {
 		object.GetObjectTagging(w, r)
 		return
 	}
+	if r.URL.Query().Has("uploadId") {
+		object.ListParts(w, r)
+		return
+	}
 	object.GetObject(w, r)
 }
 
@@ -104,23 +164,82 @@ func objectDeleteHandler(w http.ResponseWriter, r *http.Request) {
 		object.DeleteObjectTagging(w, r)
 		return
 	}
+	if r.URL.Query().Has("uploadId") {
+		object.AbortMultipartUpload(w, r)
+		return
+	}
 	object.DeleteObject(w, r)
 }
 
+// newBlobStore creates the blob store selected by BLOB_STORE: "filesystem"
+// (default) writes payloads below DATA_DIR, "sqlite" keeps them in the database
+func newBlobStore(queries *db.Queries) (blob.Store, error) {
+	switch backend := os.Getenv("BLOB_STORE"); backend {
+	case "", "filesystem":
+		dataDir := os.Getenv("DATA_DIR")
+		if dataDir == "" {
+			dataDir = defaultDataDir
+		}
+		return blob.NewFilesystemStore(dataDir)
+	case "sqlite":
+		return db.NewSQLiteBlobStore(queries), nil
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q", backend)
+	}
+}
+
+// streamsObjectData reports whether r streams object data in its request or
+// response body, as PutObject, UploadPart and GetObject do
+func streamsObjectData(r *http.Request) bool {
+	query := r.URL.Query()
+	if query.Has("tagging") || r.Header.Get("x-amz-copy-source") != "" {
+		return false
+	}
+	switch r.Method {
+	case http.MethodPut:
+		return true
+	case http.MethodGet:
+		return !query.Has("uploadId")
+	}
+	return false
+}
+
+// objectTimeout applies requestTimeout to object requests, except those
+// streaming object data, which may take as long as the transfer needs. The
+// read and write deadlines of the server are cleared for them.
+func objectTimeout(next http.Handler) http.Handler {
+	timeout := middleware.Timeout(requestTimeout)(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !streamsObjectData(r) {
+			timeout.ServeHTTP(w, r)
+			return
+		}
+		rc := http.NewResponseController(w)
+		rc.SetReadDeadline(time.Time{})
+		rc.SetWriteDeadline(time.Time{})
+		next.ServeHTTP(w, r)
+	})
+}
+
 func registerRoutes(r chi.Router) {
-	r.Get("/", bucket.ListBuckets)
+	r.With(middleware.Timeout(requestTimeout)).Get("/", bucket.ListBuckets)
 	r.Route("/{bucket}", func(r chi.Router) {
 		r.Use(ctx.WithBucketName())
 
 		// Bucket operations with query parameter routing
-		r.Put("/", bucketPutHandler)
-		r.Get("/", bucketGetHandler)
-		r.Delete("/", bucketDeleteHandler)
-		r.Head("/", bucket.HeadBucket)
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+			r.Put("/", bucketPutHandler)
+			r.Get("/", bucketGetHandler)
+			r.Post("/", bucketPostHandler)
+			r.Delete("/", bucketDeleteHandler)
+			r.Head("/", bucket.HeadBucket)
+		})
 
 		// Use wildcard to match any object key path including nested paths and trailing slashes
-		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+		r.With(ctx.WithObjectKey(), objectTimeout).Group(func(r chi.Router) {
 			r.Put("/*", objectPutHandler)
+			r.Post("/*", objectPostHandler)
 			r.Get("/*", objectGetHandler)
 			r.Head("/*", object.HeadObject)
 			r.Delete("/*", objectDeleteHandler)
@@ -145,7 +264,7 @@ func main() {
 		dbPath = defaultDBPath
 	}
 
-	database, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	database, err := sql.Open("sqlite3", db.DataSourceName(dbPath))
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
@@ -156,12 +275,34 @@ func main() {
 	}
 
 	queries := db.New(database)
-	store := db.NewStore(database, queries)
+	blobs, err := newBlobStore(queries)
+	if err != nil {
+		log.Fatalf("Failed to create blob store: %v", err)
+	}
+	store := db.NewStore(database, queries, blobs)
+
+	// Payloads left in SQLite by earlier versions or the sqlite blob store are
+	// moved to the configured blob store
+	moved, err := store.MigrateBlobs(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to migrate blobs: %v", err)
+	}
+	if moved > 0 {
+		log.Printf("Moved %d blobs out of the database", moved)
+	}
 	cfg, err := config.LoadConfig()
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 
+	// Notification rules of config.yaml replace those of the previous start
+	if err := bucket.SyncConfigNotifications(context.Background(), store, cfg.Notifications); err != nil {
+		log.Fatalf("Failed to apply notification rules: %v", err)
+	}
+
+	// Create notification worker, which the admin API can wake
+	notificationWorker := worker.NewNotificationWorker(store, cfg.Delivery, cfg.Destinations)
+
 	// Create router
 	r := chi.NewRouter()
 
@@ -170,9 +311,9 @@ func main() {
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.Timeout(60 * time.Second))
 	r.Use(ctx.WithConfig(cfg))
 	r.Use(ctx.WithStore(store))
+	r.Use(ctx.WithWorker(notificationWorker))
 
 	// CORS middleware for S3 compatibility
 	r.Use(cors.Handler(cors.Options{
@@ -190,10 +331,37 @@ func main() {
 		w.Write([]byte("OK"))
 	})
 
-	registerRoutes(r)
+	// S3Local admin API, authenticated like the S3 API as it can purge and
+	// redeliver notifications
+	r.Route("/_s3local", func(r chi.Router) {
+		r.Use(auth.WithSigV4(cfg.Auth))
+
+		// The event stream stays open until the client disconnects, and
+		// awaiting an event takes as long as the client asks for
+		r.Get("/events/stream", admin.StreamEvents)
+		r.Get("/events/await", admin.AwaitEvent)
+
+		r.Group(func(r chi.Router) {
+			r.Use(middleware.Timeout(requestTimeout))
+			r.Get("/events", admin.ListEvents)
+			r.Get("/jobs", admin.ListJobs)
+			r.Delete("/jobs", admin.PurgeJobs)
+			r.Post("/jobs/requeue", admin.RequeueDeadLetterJobs)
+			r.Get("/jobs/{id}", admin.GetJob)
+			r.Delete("/jobs/{id}", admin.DeleteJob)
+			r.Post("/jobs/{id}/retry", admin.RetryJob)
+			r.Post("/jobs/{id}/cancel", admin.CancelJob)
+			r.Post("/worker/tick", admin.Tick)
+		})
+	})
+
+	// S3 API routes are authenticated with AWS Signature Version 4
+	r.Group(func(r chi.Router) {
+		r.Use(auth.WithSigV4(cfg.Auth))
+		registerRoutes(r)
+	})
 
-	// Create and start notification worker
-	notificationWorker := worker.NewNotificationWorker(store)
+	// Start the notification worker
 	workerCtx, workerCancel := context.WithCancel(context.Background())
 	defer workerCancel()
 
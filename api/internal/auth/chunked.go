@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+const (
+	// StreamingSignedPayload is sent when every chunk of an aws-chunked body is signed
+	StreamingSignedPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+
+	// StreamingSignedPayloadTrailer is sent when every chunk and the trailer are signed
+	StreamingSignedPayloadTrailer = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD-TRAILER"
+
+	// StreamingUnsignedPayloadTrailer is sent for unsigned chunks followed by a trailer
+	StreamingUnsignedPayloadTrailer = "STREAMING-UNSIGNED-PAYLOAD-TRAILER"
+
+	chunkAlgorithm   = "AWS4-HMAC-SHA256-PAYLOAD"
+	trailerAlgorithm = "AWS4-HMAC-SHA256-TRAILER"
+
+	// maxChunkHeaderLength bounds a single chunk header or trailer line
+	maxChunkHeaderLength = 4096
+)
+
+// isStreaming reports whether the request body uses the aws-chunked encoding
+func isStreaming(r *http.Request) bool {
+	switch r.Header.Get("x-amz-content-sha256") {
+	case StreamingSignedPayload, StreamingSignedPayloadTrailer, StreamingUnsignedPayloadTrailer:
+		return true
+	}
+	for _, encoding := range strings.Split(r.Header.Get("Content-Encoding"), ",") {
+		if strings.TrimSpace(encoding) == "aws-chunked" {
+			return true
+		}
+	}
+	return false
+}
+
+// chunkSigner verifies the signature chain of a signed aws-chunked body.
+// Each chunk is signed over the previous signature, starting with the seed
+// signature from the Authorization header.
+type chunkSigner struct {
+	key           []byte
+	amzDate       string
+	scope         string
+	prevSignature string
+	// signedTrailer requires x-amz-trailer-signature after the final chunk
+	signedTrailer bool
+}
+
+// verifyChunk checks the signature of a chunk and advances the chain
+func (s *chunkSigner) verifyChunk(data []byte, signature string) bool {
+	stringToSign := strings.Join([]string{
+		chunkAlgorithm,
+		s.amzDate,
+		s.scope,
+		s.prevSignature,
+		EmptyPayloadHash,
+		hashHex(data),
+	}, "\n")
+	return s.advance(stringToSign, signature)
+}
+
+// verifyTrailer checks the signature of the trailing headers
+func (s *chunkSigner) verifyTrailer(trailer []byte, signature string) bool {
+	stringToSign := strings.Join([]string{
+		trailerAlgorithm,
+		s.amzDate,
+		s.scope,
+		s.prevSignature,
+		hashHex(trailer),
+	}, "\n")
+	return s.advance(stringToSign, signature)
+}
+
+func (s *chunkSigner) advance(stringToSign, signature string) bool {
+	expected := hex.EncodeToString(hmacSHA256(s.key, stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return false
+	}
+	s.prevSignature = signature
+	return true
+}
+
+// chunkedReader decodes an aws-chunked body:
+//
+//	<hex-size>[;chunk-signature=<sig>]\r\n<data>\r\n ... 0[;chunk-signature=<sig>]\r\n
+//	[<trailer-name>:<value>\r\n ...][x-amz-trailer-signature:<sig>\r\n]\r\n
+//
+// Trailing headers are stored in trailer once the body has been read to EOF.
+// When signer is nil, chunk and trailer signatures are not verified.
+type chunkedReader struct {
+	body    io.ReadCloser
+	r       *bufio.Reader
+	signer  *chunkSigner
+	trailer http.Header
+
+	chunk []byte // remaining data of the current chunk
+	total int64
+	// expected is x-amz-decoded-content-length, or -1 when unknown
+	expected int64
+	err      error
+}
+
+func newChunkedReader(body io.ReadCloser, signer *chunkSigner, trailer http.Header, expected int64) *chunkedReader {
+	return &chunkedReader{
+		body:     body,
+		r:        bufio.NewReader(body),
+		signer:   signer,
+		trailer:  trailer,
+		expected: expected,
+	}
+}
+
+func (c *chunkedReader) Read(b []byte) (int, error) {
+	for len(c.chunk) == 0 {
+		if c.err != nil {
+			return 0, c.err
+		}
+		c.err = c.nextChunk()
+	}
+	n := copy(b, c.chunk)
+	c.chunk = c.chunk[n:]
+	return n, nil
+}
+
+func (c *chunkedReader) Close() error {
+	return c.body.Close()
+}
+
+// nextChunk reads the next chunk into c.chunk. It returns io.EOF after the
+// final chunk and its trailer have been consumed.
+func (c *chunkedReader) nextChunk() error {
+	line, err := c.readLine()
+	if err == io.EOF {
+		// The body ended before the final zero-length chunk
+		return s3error.NewIncompleteBodyError("")
+	}
+	if err != nil {
+		return err
+	}
+
+	sizeHex, extension, _ := strings.Cut(line, ";")
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeHex), 16, 64)
+	if err != nil || size < 0 {
+		return s3error.NewIncompleteBodyError("The chunk size is malformed.")
+	}
+	if c.expected >= 0 && c.total+size > c.expected {
+		return s3error.NewIncompleteBodyError("")
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(c.r, data); err != nil {
+		return s3error.NewIncompleteBodyError("")
+	}
+	if size > 0 {
+		// Data is terminated by CRLF
+		if line, err := c.readLine(); err != nil || line != "" {
+			return s3error.NewIncompleteBodyError("The chunk is not terminated by CRLF.")
+		}
+	}
+
+	if c.signer != nil {
+		signature := strings.TrimPrefix(strings.TrimSpace(extension), "chunk-signature=")
+		if !c.signer.verifyChunk(data, signature) {
+			return s3error.NewSignatureDoesNotMatchError()
+		}
+	}
+
+	c.total += size
+	c.chunk = data
+	if size > 0 {
+		return nil
+	}
+
+	if err := c.readTrailer(); err != nil {
+		return err
+	}
+	if c.expected >= 0 && c.total != c.expected {
+		return s3error.NewIncompleteBodyError("")
+	}
+	return io.EOF
+}
+
+// readTrailer reads the trailing headers that follow the final chunk
+func (c *chunkedReader) readTrailer() error {
+	var canonical strings.Builder
+	var signature string
+	for {
+		line, err := c.readLine()
+		if err == io.EOF {
+			// Some clients omit the final CRLF when there is no trailer
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return s3error.NewIncompleteBodyError("The trailing header is malformed.")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if name == "x-amz-trailer-signature" {
+			signature = value
+			continue
+		}
+		canonical.WriteString(name + ":" + value + "\n")
+		if c.trailer != nil {
+			c.trailer.Set(name, value)
+		}
+	}
+
+	if c.signer != nil && (c.signer.signedTrailer || signature != "") {
+		if !c.signer.verifyTrailer([]byte(canonical.String()), signature) {
+			return s3error.NewSignatureDoesNotMatchError()
+		}
+	}
+	return nil
+}
+
+// readLine reads a CRLF terminated line without the line ending
+func (c *chunkedReader) readLine() (string, error) {
+	var line []byte
+	for {
+		fragment, isPrefix, err := c.r.ReadLine()
+		if err == io.EOF && len(line) == 0 {
+			return "", io.EOF
+		}
+		if err != nil {
+			return "", s3error.NewIncompleteBodyError("")
+		}
+		line = append(line, fragment...)
+		if len(line) > maxChunkHeaderLength {
+			return "", s3error.NewIncompleteBodyError("The chunk header is too long.")
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// decodeStreamingBody replaces an aws-chunked request body with its decoded
+// content. Content-Length is set to x-amz-decoded-content-length and
+// aws-chunked is removed from Content-Encoding so handlers see the payload the
+// client actually uploaded.
+func decodeStreamingBody(r *http.Request, signer *chunkSigner) {
+	expected := int64(-1)
+	if decoded, err := strconv.ParseInt(r.Header.Get("x-amz-decoded-content-length"), 10, 64); err == nil {
+		expected = decoded
+	}
+
+	if r.Trailer == nil {
+		r.Trailer = make(http.Header)
+	}
+	r.Body = newChunkedReader(r.Body, signer, r.Trailer, expected)
+	r.ContentLength = expected
+
+	var encodings []string
+	for _, encoding := range strings.Split(r.Header.Get("Content-Encoding"), ",") {
+		encoding = strings.TrimSpace(encoding)
+		if encoding != "" && encoding != "aws-chunked" {
+			encodings = append(encodings, encoding)
+		}
+	}
+	if len(encodings) == 0 {
+		r.Header.Del("Content-Encoding")
+	} else {
+		r.Header.Set("Content-Encoding", strings.Join(encodings, ","))
+	}
+}
@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// Example from the AWS documentation "Signature Calculations for the
+// Authorization Header: Transferring Payload in Multiple Chunks"
+func newExampleSigner() *chunkSigner {
+	sr := &signedRequest{Date: "20130524", Region: "us-east-1", Service: "s3"}
+	return &chunkSigner{
+		key:           sr.signingKey("wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"),
+		amzDate:       "20130524T000000Z",
+		scope:         sr.scope(),
+		prevSignature: "4f232c4386841ef735655705268965c44a0e4690baa4adea153f7db9fa80a0a9",
+	}
+}
+
+func exampleBody(firstSignature string) string {
+	return fmt.Sprintf("10000;chunk-signature=%s\r\n%s\r\n", firstSignature, strings.Repeat("a", 65536)) +
+		fmt.Sprintf("400;chunk-signature=%s\r\n%s\r\n", "0055627c9e194cb4542bae2aa5492e3c1575bbb81b612b7d234b86a503ef5497", strings.Repeat("a", 1024)) +
+		"0;chunk-signature=b6c6ea8a5354eaf15b3cb7646744f4275b71ea724fed81ceb9323e279d449df9\r\n\r\n"
+}
+
+func TestChunkedReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Signed chunks", func(t *testing.T) {
+		body := exampleBody("ad80c730a21e5b8d04586a2213dd63b9a0e99e0e2307b0ade35a65485a288648")
+		reader := newChunkedReader(io.NopCloser(strings.NewReader(body)), newExampleSigner(), nil, 66560)
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, strings.Repeat("a", 66560), string(data))
+	})
+
+	t.Run("Invalid chunk signature", func(t *testing.T) {
+		body := exampleBody("0000000000000000000000000000000000000000000000000000000000000000")
+		reader := newChunkedReader(io.NopCloser(strings.NewReader(body)), newExampleSigner(), nil, 66560)
+
+		_, err := io.ReadAll(reader)
+		require.Error(t, err)
+		assert.Equal(t, string(s3error.ErrCodeSignatureDoesNotMatch), s3error.FromError(err).Code)
+	})
+
+	t.Run("Unsigned chunks with trailer", func(t *testing.T) {
+		body := "5\r\nhello\r\n6\r\n world\r\n0\r\nx-amz-checksum-crc32:DUoRhQ==\r\n\r\n"
+		trailer := make(http.Header)
+		reader := newChunkedReader(io.NopCloser(strings.NewReader(body)), nil, trailer, 11)
+
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "hello world", string(data))
+		assert.Equal(t, "DUoRhQ==", trailer.Get("x-amz-checksum-crc32"))
+	})
+
+	t.Run("Truncated body", func(t *testing.T) {
+		body := "5\r\nhello\r\n"
+		reader := newChunkedReader(io.NopCloser(strings.NewReader(body)), nil, nil, -1)
+
+		_, err := io.ReadAll(reader)
+		require.Error(t, err)
+		assert.Equal(t, string(s3error.ErrCodeIncompleteBody), s3error.FromError(err).Code)
+	})
+
+	t.Run("Decoded length mismatch", func(t *testing.T) {
+		body := "5\r\nhello\r\n0\r\n\r\n"
+		reader := newChunkedReader(io.NopCloser(strings.NewReader(body)), nil, nil, 10)
+
+		_, err := io.ReadAll(reader)
+		require.Error(t, err)
+		assert.Equal(t, string(s3error.ErrCodeIncompleteBody), s3error.FromError(err).Code)
+	})
+}
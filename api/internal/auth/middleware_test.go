@@ -0,0 +1,405 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/bucket"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/object"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func newS3Client(ts *httptest.Server, accessKey, secretKey string) *s3.Client {
+	cfg, _ := awsconfig.LoadDefaultConfig(
+		context.Background(),
+		awsconfig.WithRegion("us-east-1"),
+		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+	)
+	return s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(ts.URL)
+		o.UsePathStyle = true
+	})
+}
+
+func errorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
+
+func newAuthServer(t *testing.T, authCfg config.AuthConfig) (*httptest.Server, *db.Store) {
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Use(WithSigV4(authCfg))
+	r.Get("/", bucket.ListBuckets)
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Put("/", bucket.CreateBucket)
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Put("/*", object.PutObject)
+			r.Get("/*", object.GetObject)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+	return ts, store
+}
+
+func TestWithSigV4(t *testing.T) {
+	t.Parallel()
+	authCfg := config.AuthConfig{
+		Credentials: []config.Credential{
+			{AccessKeyID: "s3local", SecretAccessKey: "s3local"},
+		},
+	}
+	ts, store := newAuthServer(t, authCfg)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	t.Run("Valid signature", func(t *testing.T) {
+		client := newS3Client(ts, "s3local", "s3local")
+
+		_, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("dir/with space+plus=&.txt"),
+			Body:   bytes.NewReader([]byte("hello")),
+		})
+		require.NoError(t, err)
+
+		resp, err := client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("dir/with space+plus=&.txt"),
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "hello", string(body))
+
+		_, err = client.ListBuckets(context.Background(), &s3.ListBucketsInput{
+			Prefix: aws.String("test"),
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Key with an encoded slash", func(t *testing.T) {
+		// The path is signed as sent, without decoding %2F to a separator
+		req, _ := http.NewRequest(http.MethodPut, ts.URL+"/test-bucket/dir%2Fslash.txt", strings.NewReader("hello"))
+		req.Header.Set("x-amz-content-sha256", hashHex([]byte("hello")))
+		err := v4.NewSigner(func(o *v4.SignerOptions) {
+			o.DisableURIPathEscaping = true
+		}).SignHTTP(context.Background(),
+			aws.Credentials{AccessKeyID: "s3local", SecretAccessKey: "s3local"},
+			req, hashHex([]byte("hello")), "s3", "us-east-1", time.Now())
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Wrong secret", func(t *testing.T) {
+		client := newS3Client(ts, "s3local", "wrong-secret")
+
+		_, err := client.ListBuckets(context.Background(), &s3.ListBucketsInput{})
+		require.Error(t, err)
+		assert.Equal(t, "SignatureDoesNotMatch", errorCode(err))
+	})
+
+	t.Run("Unknown access key", func(t *testing.T) {
+		client := newS3Client(ts, "unknown", "s3local")
+
+		_, err := client.ListBuckets(context.Background(), &s3.ListBucketsInput{})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidAccessKeyId", errorCode(err))
+	})
+
+	t.Run("Anonymous request is denied", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(body), "<Code>AccessDenied</Code>")
+	})
+
+	t.Run("Request time too skewed", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/", nil)
+		err := v4.NewSigner().SignHTTP(context.Background(),
+			aws.Credentials{AccessKeyID: "s3local", SecretAccessKey: "s3local"},
+			req, EmptyPayloadHash, "s3", "us-east-1", time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		req.Header.Set("x-amz-content-sha256", EmptyPayloadHash)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(body), "<Code>RequestTimeTooSkewed</Code>")
+	})
+
+	t.Run("Payload hash mismatch", func(t *testing.T) {
+		claimed := []byte("claimed body")
+		req, _ := http.NewRequest(http.MethodPut, ts.URL+"/test-bucket/tampered", strings.NewReader("actual body"))
+		payloadHash := hashHex(claimed)
+		req.Header.Set("x-amz-content-sha256", payloadHash)
+		err := v4.NewSigner().SignHTTP(context.Background(),
+			aws.Credentials{AccessKeyID: "s3local", SecretAccessKey: "s3local"},
+			req, payloadHash, "s3", "us-east-1", time.Now())
+		require.NoError(t, err)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, string(body), "<Code>XAmzContentSHA256Mismatch</Code>")
+	})
+
+	t.Run("Streaming signed payload", func(t *testing.T) {
+		payload := strings.Repeat("streaming ", 1000)
+		chunks := []string{payload[:8192], payload[8192:], ""}
+
+		resp := putStreaming(t, ts.URL+"/test-bucket/streaming.txt", "s3local", chunks, nil, false)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		obj, err := store.Queries.GetObject(context.Background(), db.GetObjectParams{
+			BucketName: "test-bucket",
+			Key:        "streaming.txt",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, payload, string(testutil.ReadBlob(t, store, obj.BlobID)))
+		assert.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte(payload))), obj.ETag)
+		assert.False(t, obj.ContentEncoding.Valid)
+	})
+
+	t.Run("Streaming signed payload with trailer", func(t *testing.T) {
+		resp := putStreaming(t, ts.URL+"/test-bucket/trailer.txt", "s3local", []string{"hello", ""},
+			[]string{"x-amz-checksum-crc32:NhCmhg=="}, false)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		obj, err := store.Queries.GetObject(context.Background(), db.GetObjectParams{
+			BucketName: "test-bucket",
+			Key:        "trailer.txt",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(testutil.ReadBlob(t, store, obj.BlobID)))
+	})
+
+	t.Run("Streaming payload with tampered chunk", func(t *testing.T) {
+		resp := putStreaming(t, ts.URL+"/test-bucket/tampered-chunk.txt", "s3local", []string{"hello", ""}, nil, true)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(body), "<Code>SignatureDoesNotMatch</Code>")
+	})
+
+	t.Run("Presigned URL", func(t *testing.T) {
+		client := newS3Client(ts, "s3local", "s3local")
+		_, err := client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("presigned.txt"),
+			Body:   bytes.NewReader([]byte("presigned content")),
+		})
+		require.NoError(t, err)
+
+		presigned, err := s3.NewPresignClient(client).PresignGetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("presigned.txt"),
+		})
+		require.NoError(t, err)
+
+		resp, err := http.Get(presigned.URL)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "presigned content", string(body))
+
+		// Tampering with the signed query invalidates the signature
+		resp, err = http.Get(strings.Replace(presigned.URL, "presigned.txt", "other.txt", 1))
+		require.NoError(t, err)
+		body, _ = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(body), "<Code>SignatureDoesNotMatch</Code>")
+	})
+
+	t.Run("Expired presigned URL", func(t *testing.T) {
+		client := newS3Client(ts, "s3local", "s3local")
+		presigned, err := s3.NewPresignClient(client).PresignGetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("presigned.txt"),
+		}, func(o *s3.PresignOptions) {
+			o.Expires = time.Second
+		})
+		require.NoError(t, err)
+
+		time.Sleep(1100 * time.Millisecond)
+		resp, err := http.Get(presigned.URL)
+		require.NoError(t, err)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(body), "Request has expired")
+	})
+}
+
+// putStreaming sends a PUT with an aws-chunked body whose chunks (and trailer,
+// when given) are signed over the seed signature. When tamper is set the first
+// chunk is modified after signing.
+func putStreaming(t *testing.T, url, secret string, chunks, trailer []string, tamper bool) *http.Response {
+	t.Helper()
+
+	payloadHash := StreamingSignedPayload
+	if trailer != nil {
+		payloadHash = StreamingSignedPayloadTrailer
+	}
+	var decodedLength int
+	for _, chunk := range chunks {
+		decodedLength += len(chunk)
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Encoding", "aws-chunked")
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	req.Header.Set("x-amz-decoded-content-length", strconv.Itoa(decodedLength))
+	now := time.Now().UTC()
+	err = v4.NewSigner().SignHTTP(context.Background(),
+		aws.Credentials{AccessKeyID: "s3local", SecretAccessKey: secret},
+		req, payloadHash, "s3", "us-east-1", now)
+	require.NoError(t, err)
+
+	sr, s3Err := parseAuthorizationHeader(req)
+	require.Nil(t, s3Err)
+	signer := &chunkSigner{
+		key:           sr.signingKey(secret),
+		amzDate:       sr.AmzDate,
+		scope:         sr.scope(),
+		prevSignature: sr.Signature,
+	}
+
+	var body bytes.Buffer
+	for i, chunk := range chunks {
+		signature := hex.EncodeToString(hmacSHA256(signer.key, strings.Join([]string{
+			chunkAlgorithm, signer.amzDate, signer.scope, signer.prevSignature, EmptyPayloadHash, hashHex([]byte(chunk)),
+		}, "\n")))
+		signer.prevSignature = signature
+		if tamper && i == 0 {
+			chunk = strings.ToUpper(chunk)
+		}
+		fmt.Fprintf(&body, "%x;chunk-signature=%s\r\n", len(chunk), signature)
+		if chunk != "" {
+			body.WriteString(chunk + "\r\n")
+		}
+	}
+	if trailer != nil {
+		var canonical strings.Builder
+		for _, line := range trailer {
+			body.WriteString(line + "\r\n")
+			canonical.WriteString(line + "\n")
+		}
+		signature := hex.EncodeToString(hmacSHA256(signer.key, strings.Join([]string{
+			trailerAlgorithm, signer.amzDate, signer.scope, signer.prevSignature, hashHex([]byte(canonical.String())),
+		}, "\n")))
+		body.WriteString("x-amz-trailer-signature:" + signature + "\r\n")
+	}
+	body.WriteString("\r\n")
+
+	req.Body = io.NopCloser(&body)
+	req.ContentLength = int64(body.Len())
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestWithSigV4_AllowAnonymous(t *testing.T) {
+	t.Parallel()
+	ts, _ := newAuthServer(t, config.AuthConfig{
+		AllowAnonymous: true,
+		Credentials: []config.Credential{
+			{AccessKeyID: "s3local", SecretAccessKey: "s3local"},
+		},
+	})
+
+	resp, err := http.Get(ts.URL + "/")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Signed requests are still verified
+	client := newS3Client(ts, "s3local", "wrong-secret")
+	_, err = client.ListBuckets(context.Background(), &s3.ListBucketsInput{})
+	require.Error(t, err)
+	assert.Equal(t, "SignatureDoesNotMatch", errorCode(err))
+}
+
+func TestWithSigV4_Disabled(t *testing.T) {
+	t.Parallel()
+	ts, store := newAuthServer(t, config.AuthConfig{})
+
+	client := newS3Client(ts, "anything", "goes")
+	_, err := client.ListBuckets(context.Background(), &s3.ListBucketsInput{})
+	assert.NoError(t, err)
+
+	// aws-chunked bodies are decoded even without authentication
+	err = store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	body := "5\r\nhello\r\n6\r\n world\r\n0\r\nx-amz-checksum-crc32:DUoRhQ==\r\n\r\n"
+	req, _ := http.NewRequest(http.MethodPut, ts.URL+"/test-bucket/unsigned.txt", strings.NewReader(body))
+	req.Header.Set("Content-Encoding", "aws-chunked,gzip")
+	req.Header.Set("x-amz-content-sha256", StreamingUnsignedPayloadTrailer)
+	req.Header.Set("x-amz-decoded-content-length", "11")
+	req.Header.Set("x-amz-trailer", "x-amz-checksum-crc32")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	obj, err := store.Queries.GetObject(context.Background(), db.GetObjectParams{
+		BucketName: "test-bucket",
+		Key:        "unsigned.txt",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(testutil.ReadBlob(t, store, obj.BlobID)))
+	assert.Equal(t, "gzip", obj.ContentEncoding.String)
+}
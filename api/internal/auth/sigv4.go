@@ -0,0 +1,315 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+const (
+	algorithm     = "AWS4-HMAC-SHA256"
+	amzDateFormat = "20060102T150405Z"
+
+	// UnsignedPayload is sent by clients that do not hash the request body
+	UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// EmptyPayloadHash is the SHA256 of an empty body
+	EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// maxPresignExpires is the longest validity AWS accepts for a presigned URL (7 days)
+	maxPresignExpires = 7 * 24 * 60 * 60
+)
+
+// signedRequest holds the signature parameters extracted from either the
+// Authorization header or a presigned URL query string
+type signedRequest struct {
+	AccessKeyID   string
+	Date          string // yyyymmdd from the credential scope
+	Region        string
+	Service       string
+	SignedHeaders []string
+	Signature     string
+	AmzDate       string // x-amz-date / X-Amz-Date
+	PayloadHash   string
+	Presigned     bool
+	Expires       int64
+}
+
+// scope returns the credential scope, e.g. 20130524/us-east-1/s3/aws4_request
+func (s *signedRequest) scope() string {
+	return strings.Join([]string{s.Date, s.Region, s.Service, "aws4_request"}, "/")
+}
+
+// isSigned reports whether the request carries SigV4 information at all
+func isSigned(r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Authorization"), algorithm) {
+		return true
+	}
+	return r.URL.Query().Get("X-Amz-Algorithm") != ""
+}
+
+// parseRequest extracts the signature parameters from the request
+func parseRequest(r *http.Request) (*signedRequest, *s3error.Error) {
+	if r.URL.Query().Get("X-Amz-Algorithm") != "" {
+		return parsePresigned(r)
+	}
+	return parseAuthorizationHeader(r)
+}
+
+// parseAuthorizationHeader parses
+// AWS4-HMAC-SHA256 Credential=AKID/20130524/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-date, Signature=abc
+func parseAuthorizationHeader(r *http.Request) (*signedRequest, *s3error.Error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, algorithm+" ") {
+		return nil, s3error.NewAuthorizationHeaderMalformedError("Unsupported authorization type")
+	}
+
+	sr := &signedRequest{}
+	for _, part := range strings.Split(strings.TrimPrefix(header, algorithm+" "), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, s3error.NewAuthorizationHeaderMalformedError("The authorization header is malformed")
+		}
+		switch name {
+		case "Credential":
+			if err := sr.parseCredential(value); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sr.SignedHeaders = strings.Split(value, ";")
+		case "Signature":
+			sr.Signature = value
+		}
+	}
+	if sr.AccessKeyID == "" || len(sr.SignedHeaders) == 0 || sr.Signature == "" {
+		return nil, s3error.NewAuthorizationHeaderMalformedError("The authorization header is malformed")
+	}
+
+	sr.AmzDate = r.Header.Get("x-amz-date")
+	if sr.AmzDate == "" {
+		// Fall back to the Date header as allowed by SigV4
+		if t, err := http.ParseTime(r.Header.Get("Date")); err == nil {
+			sr.AmzDate = t.UTC().Format(amzDateFormat)
+		}
+	}
+	sr.PayloadHash = r.Header.Get("x-amz-content-sha256")
+	if sr.PayloadHash == "" {
+		sr.PayloadHash = EmptyPayloadHash
+	}
+	return sr, nil
+}
+
+// parsePresigned parses the X-Amz-* query parameters of a presigned URL
+func parsePresigned(r *http.Request) (*signedRequest, *s3error.Error) {
+	query := r.URL.Query()
+	if query.Get("X-Amz-Algorithm") != algorithm {
+		return nil, s3error.NewAuthorizationHeaderMalformedError("Unsupported X-Amz-Algorithm")
+	}
+
+	sr := &signedRequest{
+		Presigned:     true,
+		AmzDate:       query.Get("X-Amz-Date"),
+		Signature:     query.Get("X-Amz-Signature"),
+		SignedHeaders: strings.Split(query.Get("X-Amz-SignedHeaders"), ";"),
+		PayloadHash:   UnsignedPayload,
+	}
+	if err := sr.parseCredential(query.Get("X-Amz-Credential")); err != nil {
+		return nil, err
+	}
+
+	expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+	if err != nil || expires < 0 {
+		return nil, s3error.NewAuthorizationHeaderMalformedError("X-Amz-Expires should be a number")
+	}
+	if expires > maxPresignExpires {
+		return nil, s3error.NewAuthorizationHeaderMalformedError("X-Amz-Expires must be less than a week (in seconds) that is 604800")
+	}
+	sr.Expires = expires
+
+	if sr.Signature == "" {
+		return nil, s3error.NewAuthorizationHeaderMalformedError("X-Amz-Signature is missing")
+	}
+	return sr, nil
+}
+
+// parseCredential parses AKID/20130524/us-east-1/s3/aws4_request
+func (s *signedRequest) parseCredential(value string) *s3error.Error {
+	parts := strings.Split(value, "/")
+	if len(parts) != 5 || parts[4] != "aws4_request" {
+		return s3error.NewAuthorizationHeaderMalformedError("The credential is malformed; expecting \"<YOUR-AKID>/YYYYMMDD/REGION/SERVICE/aws4_request\"")
+	}
+	s.AccessKeyID = parts[0]
+	s.Date = parts[1]
+	s.Region = parts[2]
+	s.Service = parts[3]
+	return nil
+}
+
+// checkTime validates the request time against the server clock
+func (s *signedRequest) checkTime(now time.Time, maxSkew time.Duration) *s3error.Error {
+	t, err := time.Parse(amzDateFormat, s.AmzDate)
+	if err != nil {
+		return s3error.NewAccessDeniedError("AWS authentication requires a valid Date or x-amz-date header")
+	}
+	if !strings.HasPrefix(s.AmzDate, s.Date) {
+		return s3error.NewAuthorizationHeaderMalformedError("The authorization header is malformed; the credential date does not match the request date")
+	}
+
+	if s.Presigned {
+		if now.Before(t.Add(-maxSkew)) {
+			return s3error.NewAccessDeniedError("Request is not valid yet")
+		}
+		if now.After(t.Add(time.Duration(s.Expires) * time.Second)) {
+			return s3error.NewAccessDeniedError("Request has expired")
+		}
+		return nil
+	}
+
+	if d := now.Sub(t); d > maxSkew || d < -maxSkew {
+		return s3error.NewRequestTimeTooSkewedError()
+	}
+	return nil
+}
+
+// canonicalRequest builds the SigV4 canonical request
+func (s *signedRequest) canonicalRequest(r *http.Request) string {
+	return strings.Join([]string{
+		r.Method,
+		canonicalURI(r),
+		canonicalQuery(r, s.Presigned),
+		canonicalHeaders(r, s.SignedHeaders),
+		strings.Join(s.SignedHeaders, ";"),
+		s.PayloadHash,
+	}, "\n")
+}
+
+// stringToSign builds the SigV4 string to sign for the canonical request
+func (s *signedRequest) stringToSign(r *http.Request) string {
+	return strings.Join([]string{
+		algorithm,
+		s.AmzDate,
+		s.scope(),
+		hashHex([]byte(s.canonicalRequest(r))),
+	}, "\n")
+}
+
+// signingKey derives the SigV4 signing key for the credential scope
+func (s *signedRequest) signingKey(secret string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), s.Date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+// verify recomputes the signature with the given secret and compares it in constant time
+func (s *signedRequest) verify(r *http.Request, secret string) bool {
+	expected := hex.EncodeToString(hmacSHA256(s.signingKey(secret), s.stringToSign(r)))
+	return hmac.Equal([]byte(expected), []byte(s.Signature))
+}
+
+// canonicalURI returns the path as the client encoded it. S3 does not normalize
+// or double-encode paths, and re-encoding the decoded path would turn an
+// encoded slash (%2F) into a path separator.
+func canonicalURI(r *http.Request) string {
+	path := r.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// canonicalQuery returns the sorted, URI-encoded query string
+func canonicalQuery(r *http.Request, presigned bool) string {
+	query := r.URL.Query()
+	if presigned {
+		query.Del("X-Amz-Signature")
+	}
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(query))
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// canonicalHeaders returns the lowercase name:value lines of the signed headers
+func canonicalHeaders(r *http.Request, signedHeaders []string) string {
+	var b strings.Builder
+	for _, name := range signedHeaders {
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(headerValue(r, name))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// headerValue returns the trimmed header value, including the headers net/http
+// moves out of r.Header
+func headerValue(r *http.Request, name string) string {
+	switch name {
+	case "host":
+		return r.Host
+	case "content-length":
+		if r.ContentLength < 0 {
+			return ""
+		}
+		return strconv.FormatInt(r.ContentLength, 10)
+	case "transfer-encoding":
+		return strings.Join(r.TransferEncoding, ",")
+	}
+
+	values := r.Header.Values(name)
+	trimmed := make([]string, len(values))
+	for i, v := range values {
+		trimmed[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(trimmed, ",")
+}
+
+// uriEncode encodes s per the SigV4 rules: every byte except the unreserved
+// characters (A-Z, a-z, 0-9, '-', '.', '_', '~') is percent-encoded.
+func uriEncode(s string) string {
+	const hexDigits = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hexDigits[c>>4])
+		b.WriteByte(hexDigits[c&15])
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,34 @@
+// Package blob stores object and part payloads outside the metadata database.
+package blob
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when a blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// Store holds payloads addressed by blob ID.
+// Blobs are immutable: a new payload is always written under a new ID.
+type Store interface {
+	// Put writes the content of r under id and returns the number of bytes written.
+	// The blob only becomes visible once it is completely written.
+	Put(ctx context.Context, id string, r io.Reader) (int64, error)
+	// Open returns a reader for the blob, or ErrNotFound
+	Open(ctx context.Context, id string) (io.ReadSeekCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, id string) error
+}
+
+// NewID returns a random blob ID
+func NewID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
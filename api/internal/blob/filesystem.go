@@ -0,0 +1,98 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FilesystemStore keeps blobs as files below a data directory.
+// Blobs are written to a temporary file and renamed into place, so readers
+// never observe a partially written blob.
+type FilesystemStore struct {
+	dir string
+}
+
+// NewFilesystemStore creates a FilesystemStore rooted at dir, creating the
+// directory if needed
+func NewFilesystemStore(dir string) (*FilesystemStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return &FilesystemStore{dir: dir}, nil
+}
+
+// path returns the location of a blob. Blobs are spread over subdirectories
+// named after the first two characters of the ID.
+func (s *FilesystemStore) path(id string) (string, error) {
+	if len(id) < 3 || filepath.Base(id) != id {
+		return "", fmt.Errorf("invalid blob id %q", id)
+	}
+	return filepath.Join(s.dir, id[:2], id), nil
+}
+
+// Put implements Store
+func (s *FilesystemStore) Put(ctx context.Context, id string, r io.Reader) (int64, error) {
+	dst, err := s.path(id)
+	if err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), id+"-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Open implements Store
+func (s *FilesystemStore) Open(ctx context.Context, id string) (io.ReadSeekCloser, error) {
+	path, err := s.path(id)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete implements Store
+func (s *FilesystemStore) Delete(ctx context.Context, id string) error {
+	path, err := s.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package blob_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/blob"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestStores(t *testing.T) {
+	t.Parallel()
+
+	fsStore, err := blob.NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+	sqliteStore := db.NewSQLiteBlobStore(ctx.GetStore(testutil.SetupTestDB(t)).Queries)
+
+	for name, store := range map[string]blob.Store{
+		"filesystem": fsStore,
+		"sqlite":     sqliteStore,
+	} {
+		t.Run(name, func(t *testing.T) {
+			id, err := blob.NewID()
+			require.NoError(t, err)
+
+			n, err := store.Put(context.Background(), id, bytes.NewReader([]byte("blob content")))
+			require.NoError(t, err)
+			assert.Equal(t, int64(12), n)
+
+			rc, err := store.Open(context.Background(), id)
+			require.NoError(t, err)
+			_, err = rc.Seek(5, io.SeekStart)
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			rc.Close()
+			assert.Equal(t, "content", string(data))
+
+			require.NoError(t, store.Delete(context.Background(), id))
+			_, err = store.Open(context.Background(), id)
+			assert.ErrorIs(t, err, blob.ErrNotFound)
+
+			// Deleting twice is not an error
+			assert.NoError(t, store.Delete(context.Background(), id))
+		})
+
+		t.Run(name+" large blob", func(t *testing.T) {
+			id, err := blob.NewID()
+			require.NoError(t, err)
+
+			// Larger than a single SQLite chunk, with a distinct byte per offset
+			content := make([]byte, 5<<19+7)
+			for i := range content {
+				content[i] = byte(i % 251)
+			}
+			n, err := store.Put(context.Background(), id, bytes.NewReader(content))
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)), n)
+
+			rc, err := store.Open(context.Background(), id)
+			require.NoError(t, err)
+			defer rc.Close()
+
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, content, data)
+
+			// Read across a chunk boundary
+			offset := int64(1<<20 - 3)
+			_, err = rc.Seek(offset, io.SeekStart)
+			require.NoError(t, err)
+			buf := make([]byte, 10)
+			_, err = io.ReadFull(rc, buf)
+			require.NoError(t, err)
+			assert.Equal(t, content[offset:offset+10], buf)
+
+			pos, err := rc.Seek(-4, io.SeekEnd)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(content)-4), pos)
+			data, err = io.ReadAll(rc)
+			require.NoError(t, err)
+			assert.Equal(t, content[len(content)-4:], data)
+
+			require.NoError(t, store.Delete(context.Background(), id))
+		})
+	}
+}
+
+func TestMigrateBlobs(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	// Payloads written while the SQLite blob store was in use
+	sqliteStore := db.NewSQLiteBlobStore(store.Queries)
+	ids := make([]string, 3)
+	for i := range ids {
+		id, err := blob.NewID()
+		require.NoError(t, err)
+		_, err = sqliteStore.Put(context.Background(), id, bytes.NewReader([]byte(id)))
+		require.NoError(t, err)
+		ids[i] = id
+	}
+
+	moved, err := store.MigrateBlobs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, moved)
+
+	for _, id := range ids {
+		assert.Equal(t, id, string(testutil.ReadBlob(t, store, sql.NullString{String: id, Valid: true})))
+		_, err := sqliteStore.Open(context.Background(), id)
+		assert.ErrorIs(t, err, blob.ErrNotFound)
+	}
+
+	moved, err = store.MigrateBlobs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, moved)
+}
+
+func TestReleaseBlobs(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	blobID := testutil.PutBlob(t, store, []byte("content"))
+	_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
+		BucketName:   "test-bucket",
+		Key:          "test-key",
+		BlobID:       blobID,
+		Size:         7,
+		ETag:         "9a0364b9e99bb480dd25e1f0284c8555",
+		ContentType:  "text/plain",
+		StorageClass: "STANDARD",
+	})
+	require.NoError(t, err)
+
+	// Deleting the row releases its blob once the transaction commits
+	err = store.ExecTx(context.Background(), func(q *db.Queries) error {
+		return q.DeleteObject(context.Background(), db.DeleteObjectParams{
+			BucketName: "test-bucket",
+			Key:        "test-key",
+		})
+	})
+	require.NoError(t, err)
+
+	_, err = store.Blobs.Open(context.Background(), blobID.String)
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+
+	released, err := store.Queries.ListReleasedBlobs(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, released)
+}
@@ -0,0 +1,148 @@
+// Package checksum implements the S3 additional checksum algorithms
+// (CRC32, CRC32C, CRC64NVME, SHA1 and SHA256).
+package checksum
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Algorithm is an S3 checksum algorithm
+type Algorithm string
+
+const (
+	CRC32     Algorithm = "CRC32"
+	CRC32C    Algorithm = "CRC32C"
+	CRC64NVME Algorithm = "CRC64NVME"
+	SHA1      Algorithm = "SHA1"
+	SHA256    Algorithm = "SHA256"
+)
+
+// Algorithms lists the supported algorithms
+var Algorithms = []Algorithm{CRC32, CRC32C, CRC64NVME, SHA1, SHA256}
+
+// Type is the x-amz-checksum-type of an object
+type Type string
+
+const (
+	// TypeFullObject is a checksum over the whole object content
+	TypeFullObject Type = "FULL_OBJECT"
+	// TypeComposite is a checksum over the part checksums of a multipart upload
+	TypeComposite Type = "COMPOSITE"
+)
+
+// crc64NVMETable uses the reflected CRC-64/NVME polynomial
+var crc64NVMETable = crc64.MakeTable(0x9a6c9329ac4bc9b5)
+
+// ParseAlgorithm parses an algorithm name case-insensitively
+func ParseAlgorithm(name string) (Algorithm, bool) {
+	for _, alg := range Algorithms {
+		if strings.EqualFold(string(alg), name) {
+			return alg, true
+		}
+	}
+	return "", false
+}
+
+// New returns a hash computing the algorithm
+func New(alg Algorithm) hash.Hash {
+	switch alg {
+	case CRC32:
+		return crc32.NewIEEE()
+	case CRC32C:
+		return crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	case CRC64NVME:
+		return crc64.New(crc64NVMETable)
+	case SHA1:
+		return sha1.New()
+	case SHA256:
+		return sha256.New()
+	}
+	return nil
+}
+
+// Compute returns the base64 encoded checksum of data
+func Compute(alg Algorithm, data []byte) string {
+	h := New(alg)
+	h.Write(data)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// Composite returns the checksum of a multipart object, computed over the
+// concatenated binary part checksums and suffixed with the part count
+func Composite(alg Algorithm, partChecksums []string) (string, error) {
+	h := New(alg)
+	for _, value := range partChecksums {
+		raw, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", err
+		}
+		h.Write(raw)
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(partChecksums)), nil
+}
+
+// HeaderName returns the x-amz-checksum-* header carrying the algorithm's value
+func HeaderName(alg Algorithm) string {
+	return "x-amz-checksum-" + strings.ToLower(string(alg))
+}
+
+// DefaultType returns the checksum type used for multipart uploads when the
+// client does not send x-amz-checksum-type
+func DefaultType(alg Algorithm) Type {
+	if alg == CRC64NVME {
+		return TypeFullObject
+	}
+	return TypeComposite
+}
+
+// SupportsType reports whether the algorithm can be used with the checksum type
+func SupportsType(alg Algorithm, t Type) bool {
+	switch t {
+	case TypeFullObject:
+		return alg == CRC32 || alg == CRC32C || alg == CRC64NVME
+	case TypeComposite:
+		return alg != CRC64NVME
+	}
+	return false
+}
+
+// Checksum is a checksum value sent by the client
+type Checksum struct {
+	Algorithm Algorithm
+	Value     string
+}
+
+// FromRequest returns the checksum sent in an x-amz-checksum-* header or, for
+// streaming uploads, in the request trailer. Trailers are only available
+// after the body has been read. It returns nil when no checksum was sent and
+// ok=false when more than one checksum was sent.
+func FromRequest(r *http.Request) (checksum *Checksum, ok bool) {
+	for _, alg := range Algorithms {
+		name := HeaderName(alg)
+		value := r.Header.Get(name)
+		if value == "" && r.Trailer != nil {
+			value = r.Trailer.Get(name)
+		}
+		if value == "" {
+			continue
+		}
+		if checksum != nil {
+			return nil, false
+		}
+		checksum = &Checksum{Algorithm: alg, Value: value}
+	}
+	return checksum, true
+}
+
+// Verify reports whether data matches the checksum
+func (c *Checksum) Verify(data []byte) bool {
+	return Compute(c.Algorithm, data) == c.Value
+}
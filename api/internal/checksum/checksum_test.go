@@ -0,0 +1,93 @@
+package checksum
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	// Check values of the CRC catalogue for "123456789"
+	check := []byte("123456789")
+	crcs := map[Algorithm]uint64{
+		CRC32:     0xcbf43926,
+		CRC32C:    0xe3069283,
+		CRC64NVME: 0xae8b14860a799888,
+	}
+	for alg, expected := range crcs {
+		raw, err := base64.StdEncoding.DecodeString(Compute(alg, check))
+		require.NoError(t, err)
+		var got uint64
+		if len(raw) == 4 {
+			got = uint64(binary.BigEndian.Uint32(raw))
+		} else {
+			got = binary.BigEndian.Uint64(raw)
+		}
+		assert.Equal(t, expected, got, alg)
+	}
+
+	assert.Equal(t, "Kq5sNclPz7QV2+lfQIuc6R7oRu0=", Compute(SHA1, []byte("hello world")))
+	assert.Equal(t, "uU0nuZNNPgilLlLX2n2r+sSE7+N6U4DukIj3rOLvzek=", Compute(SHA256, []byte("hello world")))
+}
+
+func TestComposite(t *testing.T) {
+	t.Parallel()
+
+	part1 := Compute(CRC32, []byte("part one"))
+	part2 := Compute(CRC32, []byte("part two"))
+	raw1, _ := base64.StdEncoding.DecodeString(part1)
+	raw2, _ := base64.StdEncoding.DecodeString(part2)
+
+	got, err := Composite(CRC32, []string{part1, part2})
+	require.NoError(t, err)
+	assert.Equal(t, Compute(CRC32, append(raw1, raw2...))+"-2", got)
+
+	_, err = Composite(CRC32, []string{"not base64!"})
+	assert.Error(t, err)
+}
+
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Header", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPut, "/", nil)
+		r.Header.Set("x-amz-checksum-crc32c", "yZRlqg==")
+		c, ok := FromRequest(r)
+		require.True(t, ok)
+		require.NotNil(t, c)
+		assert.Equal(t, CRC32C, c.Algorithm)
+		assert.Equal(t, "yZRlqg==", c.Value)
+		assert.True(t, c.Verify([]byte("hello world")))
+	})
+
+	t.Run("Trailer", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPut, "/", nil)
+		r.Trailer = http.Header{}
+		r.Trailer.Set("x-amz-checksum-crc32", "DUoRhQ==")
+		c, ok := FromRequest(r)
+		require.True(t, ok)
+		require.NotNil(t, c)
+		assert.True(t, c.Verify([]byte("hello world")))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPut, "/", nil)
+		c, ok := FromRequest(r)
+		assert.True(t, ok)
+		assert.Nil(t, c)
+	})
+
+	t.Run("Multiple", func(t *testing.T) {
+		r, _ := http.NewRequest(http.MethodPut, "/", nil)
+		r.Header.Set("x-amz-checksum-crc32", "DUoRhQ==")
+		r.Header.Set("x-amz-checksum-sha1", "Kq5sNclPz7QV2+lfQIuc6R7oRu0=")
+		_, ok := FromRequest(r)
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,35 @@
+package config
+
+import "time"
+
+// DefaultMaxClockSkew is the maximum difference between the request time and
+// the server time that AWS S3 tolerates
+const DefaultMaxClockSkew = 15 * time.Minute
+
+type Credential struct {
+	AccessKeyID     string `json:"access_key_id" yaml:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key" yaml:"secret_access_key"`
+}
+
+// AuthConfig controls AWS Signature Version 4 verification.
+// When no credentials are configured every request is accepted anonymously.
+type AuthConfig struct {
+	AllowAnonymous bool          `json:"allow_anonymous" yaml:"allow_anonymous"`
+	MaxClockSkew   time.Duration `json:"max_clock_skew" yaml:"max_clock_skew"`
+	Credentials    []Credential  `json:"credentials" yaml:"credentials"`
+}
+
+// Enabled reports whether signatures should be verified
+func (a AuthConfig) Enabled() bool {
+	return len(a.Credentials) > 0
+}
+
+// SecretFor returns the secret access key for the given access key ID
+func (a AuthConfig) SecretFor(accessKeyID string) (string, bool) {
+	for _, c := range a.Credentials {
+		if c.AccessKeyID == accessKeyID {
+			return c.SecretAccessKey, true
+		}
+	}
+	return "", false
+}
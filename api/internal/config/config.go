@@ -1,25 +1,91 @@
 package config
 
 import (
+	"fmt"
 	"os"
 
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	Auth          AuthConfig         `json:"auth" yaml:"auth"`
 	Notifications []NotificationRule `json:"notifications" yaml:"notifications"`
+	Delivery      DeliveryConfig     `json:"delivery" yaml:"delivery"`
+	Destinations  Destinations       `json:"destinations" yaml:"destinations"`
 }
 
 func LoadConfig() (*Config, error) {
 	cfg := &Config{}
 
 	// 1. Load file if exists
-	if fileExists("config.yaml") {
-		if err := loadYAML("config.yaml", cfg); err != nil {
+	path := os.Getenv("CONFIG_PATH")
+	if path == "" {
+		path = "config.yaml"
+	}
+	if fileExists(path) {
+		if err := loadYAML(path, cfg); err != nil {
 			return nil, err
 		}
 	}
 
+	// 2. Environment variables override the file
+	if endpoint := os.Getenv("SQS_ENDPOINT"); endpoint != "" {
+		cfg.Delivery.SQS.Endpoint = endpoint
+	}
+	if protocol := os.Getenv("SQS_PROTOCOL"); protocol != "" {
+		cfg.Delivery.SQS.Protocol = protocol
+	}
+	if endpoint := os.Getenv("SNS_ENDPOINT"); endpoint != "" {
+		cfg.Delivery.SNS.Endpoint = endpoint
+	}
+	if endpoint := os.Getenv("LAMBDA_ENDPOINT"); endpoint != "" {
+		cfg.Delivery.Lambda.Endpoint = endpoint
+	}
+	if endpoint := os.Getenv("EVENTBRIDGE_ENDPOINT"); endpoint != "" {
+		cfg.Delivery.EventBridge.Endpoint = endpoint
+	}
+
+	if cfg.Auth.MaxClockSkew == 0 {
+		cfg.Auth.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if cfg.Delivery.Workers == 0 {
+		cfg.Delivery.Workers = DefaultWorkers
+	}
+	if cfg.Delivery.DestinationConcurrency == 0 {
+		cfg.Delivery.DestinationConcurrency = DefaultDestinationConcurrency
+	}
+	if cfg.Delivery.Timeout == 0 {
+		cfg.Delivery.Timeout = DefaultDeliveryTimeout
+	}
+	if cfg.Delivery.MaxAttempts == 0 {
+		cfg.Delivery.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Delivery.InitialBackoff == 0 {
+		cfg.Delivery.InitialBackoff = DefaultInitialBackoff
+	}
+	if cfg.Delivery.MaxBackoff == 0 {
+		cfg.Delivery.MaxBackoff = DefaultMaxBackoff
+	}
+	switch cfg.Delivery.SQS.Protocol {
+	case "":
+		cfg.Delivery.SQS.Protocol = SQSProtocolJSON
+	case SQSProtocolJSON, SQSProtocolQuery:
+	default:
+		return nil, fmt.Errorf("unknown SQS protocol %q", cfg.Delivery.SQS.Protocol)
+	}
+	if cfg.Delivery.EventBridge.Bus == "" {
+		cfg.Delivery.EventBridge.Bus = DefaultEventBus
+	}
+	if err := cfg.Delivery.EventBridge.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Destinations.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateNotificationRules(cfg.Notifications); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
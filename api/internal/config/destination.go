@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Destination drivers
+const (
+	DriverSQS         = "sqs"
+	DriverSNS         = "sns"
+	DriverLambda      = "lambda"
+	DriverEventBridge = "eventbridge"
+	DriverWebhook     = "webhook"
+)
+
+// Payload formats and CloudEvents HTTP content modes
+const (
+	FormatS3          = "s3"
+	FormatCloudEvents = "cloudevents"
+
+	CloudEventsBinary     = "binary"
+	CloudEventsStructured = "structured"
+)
+
+// Destination maps notification destination ARNs to a local endpoint, so
+// that configurations written for AWS can be applied to S3Local unchanged.
+//
+// ARN is an ARN or a path.Match pattern such as arn:aws:sqs:*:*:orders-*.
+// Driver is the protocol the destination is sent with and defaults to the
+// destination type of the notification. Endpoint is the service endpoint for
+// the sqs, sns, lambda and eventbridge drivers and the URL the event is POSTed
+// to for the webhook driver. Protocol selects the SQS protocol and defaults to
+// delivery.sqs.protocol. Format is the payload format, s3 (the default) or
+// cloudevents, and CloudEventsMode the HTTP content mode of CloudEvents POSTed
+// to the destination, binary (the default) or structured.
+//
+// Headers, BearerToken and Secret apply to events POSTed to the destination:
+// Headers are added to the request as they are, BearerToken is sent in the
+// Authorization header and Secret signs the payload with HMAC-SHA256. They,
+// and the binary CloudEvents mode, require the webhook driver: the other
+// drivers send the event with a service API, as a structured CloudEvent.
+type Destination struct {
+	ARN             string            `json:"arn" yaml:"arn"`
+	Driver          string            `json:"driver" yaml:"driver"`
+	Endpoint        string            `json:"endpoint" yaml:"endpoint"`
+	Protocol        string            `json:"protocol" yaml:"protocol"`
+	Format          string            `json:"format" yaml:"format"`
+	CloudEventsMode string            `json:"cloudevents_mode" yaml:"cloudevents_mode"`
+	Headers         map[string]string `json:"headers" yaml:"headers"`
+	BearerToken     string            `json:"bearer_token" yaml:"bearer_token"`
+	Secret          string            `json:"secret" yaml:"secret"`
+}
+
+// Destinations is the destination registry; the first matching entry wins
+type Destinations []Destination
+
+// Resolve returns the first destination whose ARN pattern matches arn
+func (d Destinations) Resolve(arn string) (Destination, bool) {
+	for _, destination := range d {
+		if matched, _ := path.Match(destination.ARN, arn); matched {
+			return destination, true
+		}
+	}
+	return Destination{}, false
+}
+
+// validate reports the first invalid entry of the registry
+func (d Destinations) validate() error {
+	for i, destination := range d {
+		if destination.ARN == "" {
+			return fmt.Errorf("destination %d: arn is required", i)
+		}
+		if _, err := path.Match(destination.ARN, ""); err != nil {
+			return fmt.Errorf("destination %q: invalid pattern: %w", destination.ARN, err)
+		}
+		switch destination.Driver {
+		case "", DriverSQS, DriverSNS, DriverLambda, DriverEventBridge:
+		case DriverWebhook:
+			if destination.Endpoint == "" {
+				return fmt.Errorf("destination %q: the webhook driver requires an endpoint", destination.ARN)
+			}
+		default:
+			return fmt.Errorf("destination %q: unknown driver %q", destination.ARN, destination.Driver)
+		}
+		switch destination.Protocol {
+		case "", SQSProtocolJSON, SQSProtocolQuery:
+		default:
+			return fmt.Errorf("destination %q: unknown SQS protocol %q", destination.ARN, destination.Protocol)
+		}
+		switch destination.Format {
+		case "", FormatS3, FormatCloudEvents:
+		default:
+			return fmt.Errorf("destination %q: unknown format %q", destination.ARN, destination.Format)
+		}
+		switch destination.CloudEventsMode {
+		case "", CloudEventsBinary, CloudEventsStructured:
+		default:
+			return fmt.Errorf("destination %q: unknown CloudEvents mode %q", destination.ARN, destination.CloudEventsMode)
+		}
+
+		// Only the webhook driver POSTs the event as it is; other drivers
+		// would ignore these settings
+		driver := destination.driver()
+		if driver != DriverWebhook {
+			if len(destination.Headers) > 0 || destination.BearerToken != "" || destination.Secret != "" {
+				return fmt.Errorf("destination %q: headers, bearer_token and secret require the webhook driver", destination.ARN)
+			}
+			if destination.CloudEventsMode == CloudEventsBinary {
+				return fmt.Errorf("destination %q: the binary CloudEvents mode requires the webhook driver", destination.ARN)
+			}
+		}
+		if driver == DriverEventBridge && destination.Format == FormatCloudEvents {
+			return fmt.Errorf("destination %q: the eventbridge driver sends EventBridge events, not CloudEvents", destination.ARN)
+		}
+	}
+	return nil
+}
+
+// driver returns the driver of the destination, or the driver of the service
+// of its ARN pattern when none is set. It is empty when the pattern does not
+// name a known service, e.g. for *.
+func (d Destination) driver() string {
+	if d.Driver != "" {
+		return d.Driver
+	}
+	parts := strings.SplitN(d.ARN, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return ""
+	}
+	switch parts[2] {
+	case "sqs":
+		return DriverSQS
+	case "sns":
+		return DriverSNS
+	case "lambda":
+		return DriverLambda
+	case "events":
+		return DriverEventBridge
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigDestinations(t *testing.T) {
+	t.Run("Webhook settings", func(t *testing.T) {
+		err := load(t, `
+destinations:
+  - arn: "arn:aws:lambda:*:*:function:thumbnails"
+    driver: webhook
+    endpoint: http://localhost:3000/events
+    format: cloudevents
+    cloudevents_mode: binary
+    headers: {X-Environment: local}
+    bearer_token: local-token
+    secret: local-secret
+`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Webhook settings of another driver", func(t *testing.T) {
+		for _, yaml := range []string{`
+destinations:
+  - arn: "arn:aws:sqs:*:*:orders-*"
+    endpoint: http://localhost:9324
+    headers: {X-Environment: local}
+`, `
+destinations:
+  - arn: "arn:aws:sqs:*:*:orders-*"
+    driver: lambda
+    bearer_token: local-token
+`, `
+destinations:
+  - arn: "*"
+    secret: local-secret
+`} {
+			assert.ErrorContains(t, load(t, yaml), "headers, bearer_token and secret require the webhook driver")
+		}
+	})
+
+	t.Run("Binary CloudEvents of another driver", func(t *testing.T) {
+		err := load(t, `
+destinations:
+  - arn: "arn:aws:sns:*:*:orders"
+    format: cloudevents
+    cloudevents_mode: binary
+`)
+		assert.ErrorContains(t, err, "the binary CloudEvents mode requires the webhook driver")
+	})
+
+	t.Run("CloudEvents of the eventbridge driver", func(t *testing.T) {
+		err := load(t, `
+destinations:
+  - arn: "arn:aws:events:*:*:event-bus/default"
+    format: cloudevents
+`)
+		assert.ErrorContains(t, err, "the eventbridge driver sends EventBridge events")
+	})
+}
@@ -1,10 +1,143 @@
 package config
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/eventbridge"
+)
+
+// Defaults for delivering notifications
+const (
+	DefaultMaxAttempts            = 5
+	DefaultInitialBackoff         = time.Second
+	DefaultMaxBackoff             = time.Minute
+	DefaultWorkers                = 8
+	DefaultDestinationConcurrency = 2
+	DefaultDeliveryTimeout        = 10 * time.Second
+)
+
+// DeliveryConfig controls how notification jobs are delivered.
+//
+// Up to Workers jobs are sent at once, and at most DestinationConcurrency of
+// them to the same destination. A job is leased for twice the Timeout so that
+// other processes sharing the database do not send it as well.
+//
+// The delay before a retry doubles with every attempt up to MaxBackoff, with
+// jitter; a job that failed MaxAttempts times is dead-lettered.
+type DeliveryConfig struct {
+	Workers                int               `json:"workers" yaml:"workers"`
+	DestinationConcurrency int               `json:"destination_concurrency" yaml:"destination_concurrency"`
+	Timeout                time.Duration     `json:"timeout" yaml:"timeout"`
+	MaxAttempts            int               `json:"max_attempts" yaml:"max_attempts"`
+	InitialBackoff         time.Duration     `json:"initial_backoff" yaml:"initial_backoff"`
+	MaxBackoff             time.Duration     `json:"max_backoff" yaml:"max_backoff"`
+	SQS                    SQSConfig         `json:"sqs" yaml:"sqs"`
+	SNS                    SNSConfig         `json:"sns" yaml:"sns"`
+	Lambda                 LambdaConfig      `json:"lambda" yaml:"lambda"`
+	EventBridge            EventBridgeConfig `json:"eventbridge" yaml:"eventbridge"`
+}
+
+// Lease returns how long a worker claims a job for
+func (d DeliveryConfig) Lease() time.Duration {
+	return 2 * d.Timeout
+}
+
+// SQS protocols for sending messages
+const (
+	SQSProtocolJSON  = "json"
+	SQSProtocolQuery = "query"
+)
+
+// SQSConfig controls delivery to QueueConfiguration destinations.
+//
+// A queue ARN arn:aws:sqs:{region}:{account}:{name} is sent to the queue URL
+// {Endpoint}/{account}/{name}; destinations that already are queue URLs are
+// used as they are. Protocol selects the AWS JSON protocol (the default) or
+// the query protocol of older SQS implementations.
+type SQSConfig struct {
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	Protocol string `json:"protocol" yaml:"protocol"`
+}
+
+// SNSConfig controls delivery to TopicConfiguration destinations. Topic ARNs
+// are published to Endpoint with the SNS Publish API.
+type SNSConfig struct {
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+}
+
+// LambdaConfig controls delivery to LambdaFunctionConfiguration destinations.
+// A function ARN arn:aws:lambda:{region}:{account}:function:{name} is invoked
+// asynchronously at {Endpoint}/2015-03-31/functions/{name}/invocations; a URL
+// given instead of an ARN is the invocation URL itself.
+type LambdaConfig struct {
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+}
+
+// DefaultEventBus is the event bus S3 sends EventBridge events to
+const DefaultEventBus = "default"
+
+// EventBridgeConfig controls delivery for buckets with an
+// EventBridgeConfiguration. Events are sent with the PutEvents API to Bus at
+// Endpoint, and to the target of every rule whose event pattern they match.
+type EventBridgeConfig struct {
+	Endpoint string            `json:"endpoint" yaml:"endpoint"`
+	Bus      string            `json:"bus" yaml:"bus"`
+	Rules    []EventBridgeRule `json:"rules" yaml:"rules"`
+}
+
+// EventBridgeRule routes the events matching EventPattern to Target: a queue,
+// topic or function ARN resolved like notification destinations, an event bus
+// ARN the event is put on with PutEvents, or a URL the event is POSTed to
+type EventBridgeRule struct {
+	Name         string         `json:"name" yaml:"name"`
+	EventPattern map[string]any `json:"event_pattern" yaml:"event_pattern"`
+	Target       string         `json:"target" yaml:"target"`
+}
+
+// Pattern parses the event pattern of the rule
+func (r EventBridgeRule) Pattern() (*eventbridge.Pattern, error) {
+	data, err := json.Marshal(r.EventPattern)
+	if err != nil {
+		return nil, err
+	}
+	return eventbridge.ParsePattern(data)
+}
+
+// validate reports the first rule without a target or with an invalid pattern
+func (e EventBridgeConfig) validate() error {
+	for i, rule := range e.Rules {
+		if rule.Target == "" {
+			return fmt.Errorf("eventbridge rule %d: target is required", i)
+		}
+		if _, err := rule.Pattern(); err != nil {
+			return fmt.Errorf("eventbridge rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// NotificationDestination is where a rule of config.yaml sends its events.
+// Type is sqs, sns or lambda, and the destination is given as an ARN, or as
+// an http(s) URL used in place of one.
 type NotificationDestination struct {
 	Type string `json:"type" yaml:"type"`
+	ARN  string `json:"arn" yaml:"arn"`
 	URL  string `json:"url" yaml:"url"`
 }
 
+// Target returns the destination ARN, or the URL given instead of one
+func (d NotificationDestination) Target() string {
+	if d.ARN != "" {
+		return d.ARN
+	}
+	return d.URL
+}
+
+// NotificationRule is a notification rule declared in config.yaml. Rules are
+// applied to their bucket at startup, or once the bucket is created, and are
+// kept when the notification configuration is replaced through the API.
 type NotificationRule struct {
 	Bucket      string                  `json:"bucket" yaml:"bucket"`
 	Events      []string                `json:"events" yaml:"events"`
@@ -12,3 +145,26 @@ type NotificationRule struct {
 	Suffix      string                  `json:"suffix" yaml:"suffix"`
 	Destination NotificationDestination `json:"destination" yaml:"destination"`
 }
+
+// validateNotificationRules reports the first rule missing its bucket, events
+// or destination. Event types and overlapping rules are checked like a
+// PutBucketNotificationConfiguration request when the rules are synced.
+func validateNotificationRules(rules []NotificationRule) error {
+	for i, rule := range rules {
+		if rule.Bucket == "" {
+			return fmt.Errorf("notification rule %d: bucket is required", i)
+		}
+		if len(rule.Events) == 0 {
+			return fmt.Errorf("notification rule %d: events are required", i)
+		}
+		switch rule.Destination.Type {
+		case DriverSQS, DriverSNS, DriverLambda:
+		default:
+			return fmt.Errorf("notification rule %d: unknown destination type %q", i, rule.Destination.Type)
+		}
+		if (rule.Destination.ARN == "") == (rule.Destination.URL == "") {
+			return fmt.Errorf("notification rule %d: the destination needs either an arn or a url", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+)
+
+// load loads config.yaml with the given content
+func load(t *testing.T, yaml string) error {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0o644))
+	t.Setenv("CONFIG_PATH", path)
+	_, err := config.LoadConfig()
+	return err
+}
+
+func TestLoadConfigNotificationRules(t *testing.T) {
+	t.Run("Valid rules", func(t *testing.T) {
+		err := load(t, `
+notifications:
+  - bucket: photos
+    events: ["s3:ObjectCreated:*"]
+    prefix: images/
+    destination: {type: sqs, arn: "arn:aws:sqs:us-east-1:000000000000:images"}
+  - bucket: photos
+    events: ["s3:ObjectCreated:*"]
+    prefix: videos/
+    destination: {type: sqs, arn: "arn:aws:sqs:us-east-1:000000000000:videos"}
+  - bucket: documents
+    events: ["s3:ObjectCreated:*"]
+    destination: {type: sqs, arn: "arn:aws:sqs:us-east-1:000000000000:documents"}
+`)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unknown destination type", func(t *testing.T) {
+		err := load(t, `
+notifications:
+  - bucket: photos
+    events: ["s3:ObjectCreated:*"]
+    destination: {type: sns-fifo, arn: "arn:aws:sns:us-east-1:000000000000:images"}
+`)
+		assert.ErrorContains(t, err, `unknown destination type "sns-fifo"`)
+	})
+}
@@ -0,0 +1,170 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: blob.sql
+
+package db
+
+import (
+	"context"
+)
+
+const CreateBlob = `-- name: CreateBlob :exec
+INSERT INTO blobs (id, size)
+VALUES (?, ?)
+`
+
+type CreateBlobParams struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+func (q *Queries) CreateBlob(ctx context.Context, arg CreateBlobParams) error {
+	_, err := q.exec(ctx, q.createBlobStmt, CreateBlob, arg.ID, arg.Size)
+	return err
+}
+
+const CreateBlobChunk = `-- name: CreateBlobChunk :exec
+INSERT INTO blob_chunks (blob_id, position, data)
+VALUES (?, ?, ?)
+`
+
+type CreateBlobChunkParams struct {
+	BlobID   string `json:"blob_id"`
+	Position int64  `json:"position"`
+	Data     []byte `json:"data"`
+}
+
+func (q *Queries) CreateBlobChunk(ctx context.Context, arg CreateBlobChunkParams) error {
+	_, err := q.exec(ctx, q.createBlobChunkStmt, CreateBlobChunk, arg.BlobID, arg.Position, arg.Data)
+	return err
+}
+
+const DeleteBlob = `-- name: DeleteBlob :exec
+DELETE FROM blobs
+WHERE id = ?
+`
+
+func (q *Queries) DeleteBlob(ctx context.Context, id string) error {
+	_, err := q.exec(ctx, q.deleteBlobStmt, DeleteBlob, id)
+	return err
+}
+
+const DeleteBlobChunks = `-- name: DeleteBlobChunks :exec
+DELETE FROM blob_chunks
+WHERE blob_id = ?
+`
+
+func (q *Queries) DeleteBlobChunks(ctx context.Context, blobID string) error {
+	_, err := q.exec(ctx, q.deleteBlobChunksStmt, DeleteBlobChunks, blobID)
+	return err
+}
+
+const DeleteReleasedBlob = `-- name: DeleteReleasedBlob :exec
+DELETE FROM released_blobs
+WHERE blob_id = ?
+`
+
+func (q *Queries) DeleteReleasedBlob(ctx context.Context, blobID string) error {
+	_, err := q.exec(ctx, q.deleteReleasedBlobStmt, DeleteReleasedBlob, blobID)
+	return err
+}
+
+const GetBlobChunk = `-- name: GetBlobChunk :one
+SELECT position, data
+FROM blob_chunks
+WHERE blob_id = ?1 AND position <= ?2
+ORDER BY position DESC
+LIMIT 1
+`
+
+type GetBlobChunkParams struct {
+	BlobID   string `json:"blob_id"`
+	Position int64  `json:"position"`
+}
+
+type GetBlobChunkRow struct {
+	Position int64  `json:"position"`
+	Data     []byte `json:"data"`
+}
+
+// Returns the chunk containing the byte at position
+func (q *Queries) GetBlobChunk(ctx context.Context, arg GetBlobChunkParams) (GetBlobChunkRow, error) {
+	row := q.queryRow(ctx, q.getBlobChunkStmt, GetBlobChunk, arg.BlobID, arg.Position)
+	var i GetBlobChunkRow
+	err := row.Scan(&i.Position, &i.Data)
+	return i, err
+}
+
+const GetBlobSize = `-- name: GetBlobSize :one
+SELECT size
+FROM blobs
+WHERE id = ?
+`
+
+func (q *Queries) GetBlobSize(ctx context.Context, id string) (int64, error) {
+	row := q.queryRow(ctx, q.getBlobSizeStmt, GetBlobSize, id)
+	var size int64
+	err := row.Scan(&size)
+	return size, err
+}
+
+const ListBlobIDs = `-- name: ListBlobIDs :many
+SELECT id
+FROM blobs
+ORDER BY id ASC
+LIMIT ?
+`
+
+func (q *Queries) ListBlobIDs(ctx context.Context, limit int64) ([]string, error) {
+	rows, err := q.query(ctx, q.listBlobIDsStmt, ListBlobIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListReleasedBlobs = `-- name: ListReleasedBlobs :many
+SELECT blob_id
+FROM released_blobs
+ORDER BY released_at ASC
+LIMIT ?
+`
+
+func (q *Queries) ListReleasedBlobs(ctx context.Context, limit int64) ([]string, error) {
+	rows, err := q.query(ctx, q.listReleasedBlobsStmt, ListReleasedBlobs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var blob_id string
+		if err := rows.Scan(&blob_id); err != nil {
+			return nil, err
+		}
+		items = append(items, blob_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+
+	"github.com/tkasuz/s3local/internal/blob"
+)
+
+// blobBatchSize is the number of blobs handled per query when releasing or
+// migrating blobs
+const blobBatchSize = 100
+
+// blobChunkSize is the size of the chunks the SQLite blob store writes
+const blobChunkSize = 1 << 20
+
+// SQLiteBlobStore keeps blobs in the metadata database, split into chunks of
+// blobChunkSize so payloads are never held in memory as a whole
+type SQLiteBlobStore struct {
+	queries *Queries
+}
+
+// NewSQLiteBlobStore creates a SQLiteBlobStore
+func NewSQLiteBlobStore(queries *Queries) *SQLiteBlobStore {
+	return &SQLiteBlobStore{queries: queries}
+}
+
+// Put implements blob.Store. The chunks are written first and the blobs row
+// last, so the blob only becomes visible once it is complete.
+func (s *SQLiteBlobStore) Put(ctx context.Context, id string, r io.Reader) (int64, error) {
+	buf := make([]byte, blobChunkSize)
+	var size int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if err := s.queries.CreateBlobChunk(ctx, CreateBlobChunkParams{
+				BlobID:   id,
+				Position: size,
+				Data:     buf[:n],
+			}); err != nil {
+				s.queries.DeleteBlobChunks(ctx, id)
+				return 0, err
+			}
+			size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			s.queries.DeleteBlobChunks(ctx, id)
+			return 0, err
+		}
+	}
+
+	if err := s.queries.CreateBlob(ctx, CreateBlobParams{ID: id, Size: size}); err != nil {
+		s.queries.DeleteBlobChunks(ctx, id)
+		return 0, err
+	}
+	return size, nil
+}
+
+// Open implements blob.Store
+func (s *SQLiteBlobStore) Open(ctx context.Context, id string) (io.ReadSeekCloser, error) {
+	size, err := s.queries.GetBlobSize(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, blob.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteBlobReader{ctx: ctx, queries: s.queries, id: id, size: size}, nil
+}
+
+// Delete implements blob.Store
+func (s *SQLiteBlobStore) Delete(ctx context.Context, id string) error {
+	if err := s.queries.DeleteBlob(ctx, id); err != nil {
+		return err
+	}
+	return s.queries.DeleteBlobChunks(ctx, id)
+}
+
+// sqliteBlobReader reads a blob one chunk at a time
+type sqliteBlobReader struct {
+	ctx     context.Context
+	queries *Queries
+	id      string
+	size    int64
+	pos     int64
+
+	// chunk is the last chunk read, starting at chunkPos
+	chunk    []byte
+	chunkPos int64
+}
+
+func (r *sqliteBlobReader) Read(b []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	if r.pos < r.chunkPos || r.pos >= r.chunkPos+int64(len(r.chunk)) {
+		chunk, err := r.queries.GetBlobChunk(r.ctx, GetBlobChunkParams{
+			BlobID:   r.id,
+			Position: r.pos,
+		})
+		if err != nil {
+			return 0, err
+		}
+		r.chunk, r.chunkPos = chunk.Data, chunk.Position
+	}
+	n := copy(b, r.chunk[r.pos-r.chunkPos:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+func (r *sqliteBlobReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+func (r *sqliteBlobReader) Close() error {
+	r.chunk = nil
+	return nil
+}
+
+// ReleaseBlobs deletes blobs that are no longer referenced by any object or
+// part from the blob store. Blobs that cannot be deleted stay released and are
+// retried on the next call.
+func (s *Store) ReleaseBlobs(ctx context.Context) error {
+	for {
+		ids, err := s.Queries.ListReleasedBlobs(ctx, blobBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if err := s.Blobs.Delete(ctx, id); err != nil {
+				return err
+			}
+			if err := s.Queries.DeleteReleasedBlob(ctx, id); err != nil {
+				return err
+			}
+		}
+		if len(ids) < blobBatchSize {
+			return nil
+		}
+	}
+}
+
+// MigrateBlobs moves blobs kept in the blobs table to the store's blob store
+// and returns the number of blobs moved. It does nothing when the store keeps
+// blobs in SQLite.
+func (s *Store) MigrateBlobs(ctx context.Context) (int, error) {
+	if _, ok := s.Blobs.(*SQLiteBlobStore); ok {
+		return 0, nil
+	}
+
+	src := NewSQLiteBlobStore(s.Queries)
+	moved := 0
+	for {
+		ids, err := s.Queries.ListBlobIDs(ctx, blobBatchSize)
+		if err != nil {
+			return moved, err
+		}
+		for _, id := range ids {
+			if err := s.moveBlob(ctx, src, id); err != nil {
+				return moved, err
+			}
+			moved++
+		}
+		if len(ids) < blobBatchSize {
+			return moved, nil
+		}
+	}
+}
+
+// moveBlob copies a blob from src to the store's blob store and deletes it from src
+func (s *Store) moveBlob(ctx context.Context, src *SQLiteBlobStore, id string) error {
+	rc, err := src.Open(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if _, err := s.Blobs.Put(ctx, id, rc); err != nil {
+		return err
+	}
+	return src.Delete(ctx, id)
+}
@@ -1,12 +1,13 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 // source: bucket.sql
 
 package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
@@ -132,9 +133,15 @@ FROM buckets
 WHERE name = ?
 `
 
-func (q *Queries) GetBucket(ctx context.Context, name string) (Bucket, error) {
+type GetBucketRow struct {
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) GetBucket(ctx context.Context, name string) (GetBucketRow, error) {
 	row := q.queryRow(ctx, q.getBucketStmt, GetBucket, name)
-	var i Bucket
+	var i GetBucketRow
 	err := row.Scan(&i.Name, &i.Region, &i.CreatedAt)
 	return i, err
 }
@@ -193,21 +200,40 @@ func (q *Queries) GetBucketTags(ctx context.Context, bucketName string) ([]GetBu
 	return items, nil
 }
 
+const GetBucketVersioning = `-- name: GetBucketVersioning :one
+SELECT versioning_status
+FROM buckets
+WHERE name = ?
+`
+
+func (q *Queries) GetBucketVersioning(ctx context.Context, name string) (sql.NullString, error) {
+	row := q.queryRow(ctx, q.getBucketVersioningStmt, GetBucketVersioning, name)
+	var versioning_status sql.NullString
+	err := row.Scan(&versioning_status)
+	return versioning_status, err
+}
+
 const ListBuckets = `-- name: ListBuckets :many
 SELECT name, region, created_at
 FROM buckets
 ORDER BY created_at ASC
 `
 
-func (q *Queries) ListBuckets(ctx context.Context) ([]Bucket, error) {
+type ListBucketsRow struct {
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListBuckets(ctx context.Context) ([]ListBucketsRow, error) {
 	rows, err := q.query(ctx, q.listBucketsStmt, ListBuckets)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Bucket{}
+	items := []ListBucketsRow{}
 	for rows.Next() {
-		var i Bucket
+		var i ListBucketsRow
 		if err := rows.Scan(&i.Name, &i.Region, &i.CreatedAt); err != nil {
 			return nil, err
 		}
@@ -237,15 +263,21 @@ type ListBucketsFilteredParams struct {
 	Limit  int64       `json:"limit"`
 }
 
-func (q *Queries) ListBucketsFiltered(ctx context.Context, arg ListBucketsFilteredParams) ([]Bucket, error) {
+type ListBucketsFilteredRow struct {
+	Name      string    `json:"name"`
+	Region    string    `json:"region"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListBucketsFiltered(ctx context.Context, arg ListBucketsFilteredParams) ([]ListBucketsFilteredRow, error) {
 	rows, err := q.query(ctx, q.listBucketsFilteredStmt, ListBucketsFiltered, arg.Region, arg.Prefix, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Bucket{}
+	items := []ListBucketsFilteredRow{}
 	for rows.Next() {
-		var i Bucket
+		var i ListBucketsFilteredRow
 		if err := rows.Scan(&i.Name, &i.Region, &i.CreatedAt); err != nil {
 			return nil, err
 		}
@@ -277,3 +309,19 @@ func (q *Queries) PutBucketPolicy(ctx context.Context, arg PutBucketPolicyParams
 	_, err := q.exec(ctx, q.putBucketPolicyStmt, PutBucketPolicy, arg.BucketName, arg.Policy)
 	return err
 }
+
+const UpdateBucketVersioning = `-- name: UpdateBucketVersioning :exec
+UPDATE buckets
+SET versioning_status = ?
+WHERE name = ?
+`
+
+type UpdateBucketVersioningParams struct {
+	VersioningStatus sql.NullString `json:"versioning_status"`
+	Name             string         `json:"name"`
+}
+
+func (q *Queries) UpdateBucketVersioning(ctx context.Context, arg UpdateBucketVersioningParams) error {
+	_, err := q.exec(ctx, q.updateBucketVersioningStmt, UpdateBucketVersioning, arg.VersioningStatus, arg.Name)
+	return err
+}
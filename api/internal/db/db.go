@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 
 package db
 
@@ -24,42 +24,75 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addNotificationJobDeliveredTargetStmt, err = db.PrepareContext(ctx, AddNotificationJobDeliveredTarget); err != nil {
+		return nil, fmt.Errorf("error preparing query AddNotificationJobDeliveredTarget: %w", err)
+	}
 	if q.bucketExistsStmt, err = db.PrepareContext(ctx, BucketExists); err != nil {
 		return nil, fmt.Errorf("error preparing query BucketExists: %w", err)
 	}
 	if q.bucketPolicyExistsStmt, err = db.PrepareContext(ctx, BucketPolicyExists); err != nil {
 		return nil, fmt.Errorf("error preparing query BucketPolicyExists: %w", err)
 	}
-	if q.copyObjectStmt, err = db.PrepareContext(ctx, CopyObject); err != nil {
-		return nil, fmt.Errorf("error preparing query CopyObject: %w", err)
+	if q.cancelNotificationJobStmt, err = db.PrepareContext(ctx, CancelNotificationJob); err != nil {
+		return nil, fmt.Errorf("error preparing query CancelNotificationJob: %w", err)
+	}
+	if q.claimNotificationJobsStmt, err = db.PrepareContext(ctx, ClaimNotificationJobs); err != nil {
+		return nil, fmt.Errorf("error preparing query ClaimNotificationJobs: %w", err)
 	}
 	if q.countObjectsInBucketStmt, err = db.PrepareContext(ctx, CountObjectsInBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query CountObjectsInBucket: %w", err)
 	}
+	if q.createBlobStmt, err = db.PrepareContext(ctx, CreateBlob); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBlob: %w", err)
+	}
+	if q.createBlobChunkStmt, err = db.PrepareContext(ctx, CreateBlobChunk); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateBlobChunk: %w", err)
+	}
 	if q.createBucketStmt, err = db.PrepareContext(ctx, CreateBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateBucket: %w", err)
 	}
 	if q.createBucketTagStmt, err = db.PrepareContext(ctx, CreateBucketTag); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateBucketTag: %w", err)
 	}
+	if q.createConfigNotificationStmt, err = db.PrepareContext(ctx, CreateConfigNotification); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateConfigNotification: %w", err)
+	}
+	if q.createDeleteMarkerStmt, err = db.PrepareContext(ctx, CreateDeleteMarker); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeleteMarker: %w", err)
+	}
 	if q.createEventStmt, err = db.PrepareContext(ctx, CreateEvent); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateEvent: %w", err)
 	}
+	if q.createMultipartUploadStmt, err = db.PrepareContext(ctx, CreateMultipartUpload); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateMultipartUpload: %w", err)
+	}
 	if q.createNotificationStmt, err = db.PrepareContext(ctx, CreateNotification); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateNotification: %w", err)
 	}
+	if q.createNotificationJobStmt, err = db.PrepareContext(ctx, CreateNotificationJob); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateNotificationJob: %w", err)
+	}
 	if q.createObjectStmt, err = db.PrepareContext(ctx, CreateObject); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateObject: %w", err)
 	}
 	if q.createObjectMetadataStmt, err = db.PrepareContext(ctx, CreateObjectMetadata); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateObjectMetadata: %w", err)
 	}
+	if q.createObjectPartStmt, err = db.PrepareContext(ctx, CreateObjectPart); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateObjectPart: %w", err)
+	}
 	if q.createObjectTagStmt, err = db.PrepareContext(ctx, CreateObjectTag); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateObjectTag: %w", err)
 	}
 	if q.deleteAllObjectTagsStmt, err = db.PrepareContext(ctx, DeleteAllObjectTags); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAllObjectTags: %w", err)
 	}
+	if q.deleteBlobStmt, err = db.PrepareContext(ctx, DeleteBlob); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBlob: %w", err)
+	}
+	if q.deleteBlobChunksStmt, err = db.PrepareContext(ctx, DeleteBlobChunks); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteBlobChunks: %w", err)
+	}
 	if q.deleteBucketStmt, err = db.PrepareContext(ctx, DeleteBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteBucket: %w", err)
 	}
@@ -72,18 +105,42 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.deleteBucketTagsStmt, err = db.PrepareContext(ctx, DeleteBucketTags); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteBucketTags: %w", err)
 	}
+	if q.deleteMultipartUploadStmt, err = db.PrepareContext(ctx, DeleteMultipartUpload); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteMultipartUpload: %w", err)
+	}
 	if q.deleteNotificationStmt, err = db.PrepareContext(ctx, DeleteNotification); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteNotification: %w", err)
 	}
+	if q.deleteNotificationJobStmt, err = db.PrepareContext(ctx, DeleteNotificationJob); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteNotificationJob: %w", err)
+	}
 	if q.deleteObjectStmt, err = db.PrepareContext(ctx, DeleteObject); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteObject: %w", err)
 	}
 	if q.deleteObjectMetadataStmt, err = db.PrepareContext(ctx, DeleteObjectMetadata); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteObjectMetadata: %w", err)
 	}
+	if q.deleteObjectPartsStmt, err = db.PrepareContext(ctx, DeleteObjectParts); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteObjectParts: %w", err)
+	}
 	if q.deleteObjectTagsStmt, err = db.PrepareContext(ctx, DeleteObjectTags); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteObjectTags: %w", err)
 	}
+	if q.deleteObjectVersionByIDStmt, err = db.PrepareContext(ctx, DeleteObjectVersionByID); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteObjectVersionByID: %w", err)
+	}
+	if q.deleteReleasedBlobStmt, err = db.PrepareContext(ctx, DeleteReleasedBlob); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteReleasedBlob: %w", err)
+	}
+	if q.demoteLatestObjectVersionStmt, err = db.PrepareContext(ctx, DemoteLatestObjectVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query DemoteLatestObjectVersion: %w", err)
+	}
+	if q.getBlobChunkStmt, err = db.PrepareContext(ctx, GetBlobChunk); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBlobChunk: %w", err)
+	}
+	if q.getBlobSizeStmt, err = db.PrepareContext(ctx, GetBlobSize); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBlobSize: %w", err)
+	}
 	if q.getBucketStmt, err = db.PrepareContext(ctx, GetBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query GetBucket: %w", err)
 	}
@@ -93,9 +150,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getBucketTagsStmt, err = db.PrepareContext(ctx, GetBucketTags); err != nil {
 		return nil, fmt.Errorf("error preparing query GetBucketTags: %w", err)
 	}
+	if q.getBucketVersioningStmt, err = db.PrepareContext(ctx, GetBucketVersioning); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBucketVersioning: %w", err)
+	}
+	if q.getLatestEventIDStmt, err = db.PrepareContext(ctx, GetLatestEventID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestEventID: %w", err)
+	}
+	if q.getLatestObjectVersionStmt, err = db.PrepareContext(ctx, GetLatestObjectVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLatestObjectVersion: %w", err)
+	}
+	if q.getMultipartUploadStmt, err = db.PrepareContext(ctx, GetMultipartUpload); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMultipartUpload: %w", err)
+	}
+	if q.getMultipartUploadPartsWithBlobsStmt, err = db.PrepareContext(ctx, GetMultipartUploadPartsWithBlobs); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMultipartUploadPartsWithBlobs: %w", err)
+	}
 	if q.getNotificationStmt, err = db.PrepareContext(ctx, GetNotification); err != nil {
 		return nil, fmt.Errorf("error preparing query GetNotification: %w", err)
 	}
+	if q.getNotificationJobStmt, err = db.PrepareContext(ctx, GetNotificationJob); err != nil {
+		return nil, fmt.Errorf("error preparing query GetNotificationJob: %w", err)
+	}
 	if q.getObjectStmt, err = db.PrepareContext(ctx, GetObject); err != nil {
 		return nil, fmt.Errorf("error preparing query GetObject: %w", err)
 	}
@@ -114,6 +189,12 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getObjectTagsStmt, err = db.PrepareContext(ctx, GetObjectTags); err != nil {
 		return nil, fmt.Errorf("error preparing query GetObjectTags: %w", err)
 	}
+	if q.getObjectVersionStmt, err = db.PrepareContext(ctx, GetObjectVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query GetObjectVersion: %w", err)
+	}
+	if q.listBlobIDsStmt, err = db.PrepareContext(ctx, ListBlobIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListBlobIDs: %w", err)
+	}
 	if q.listBucketsStmt, err = db.PrepareContext(ctx, ListBuckets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListBuckets: %w", err)
 	}
@@ -123,30 +204,81 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listEnabledNotificationsByBucketStmt, err = db.PrepareContext(ctx, ListEnabledNotificationsByBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query ListEnabledNotificationsByBucket: %w", err)
 	}
+	if q.listEventsStmt, err = db.PrepareContext(ctx, ListEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEvents: %w", err)
+	}
+	if q.listEventsAfterStmt, err = db.PrepareContext(ctx, ListEventsAfter); err != nil {
+		return nil, fmt.Errorf("error preparing query ListEventsAfter: %w", err)
+	}
 	if q.listEventsByBucketStmt, err = db.PrepareContext(ctx, ListEventsByBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query ListEventsByBucket: %w", err)
 	}
+	if q.listMultipartUploadPartsStmt, err = db.PrepareContext(ctx, ListMultipartUploadParts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMultipartUploadParts: %w", err)
+	}
+	if q.listMultipartUploadsStmt, err = db.PrepareContext(ctx, ListMultipartUploads); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMultipartUploads: %w", err)
+	}
+	if q.listNotificationJobsStmt, err = db.PrepareContext(ctx, ListNotificationJobs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNotificationJobs: %w", err)
+	}
+	if q.listNotificationJobsByEventStmt, err = db.PrepareContext(ctx, ListNotificationJobsByEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNotificationJobsByEvent: %w", err)
+	}
+	if q.listNotificationJobsUpdatedSinceStmt, err = db.PrepareContext(ctx, ListNotificationJobsUpdatedSince); err != nil {
+		return nil, fmt.Errorf("error preparing query ListNotificationJobsUpdatedSince: %w", err)
+	}
 	if q.listNotificationsByBucketStmt, err = db.PrepareContext(ctx, ListNotificationsByBucket); err != nil {
 		return nil, fmt.Errorf("error preparing query ListNotificationsByBucket: %w", err)
 	}
 	if q.listNotificationsByEventTypeStmt, err = db.PrepareContext(ctx, ListNotificationsByEventType); err != nil {
 		return nil, fmt.Errorf("error preparing query ListNotificationsByEventType: %w", err)
 	}
+	if q.listObjectPartsStmt, err = db.PrepareContext(ctx, ListObjectParts); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectParts: %w", err)
+	}
+	if q.listObjectVersionsStmt, err = db.PrepareContext(ctx, ListObjectVersions); err != nil {
+		return nil, fmt.Errorf("error preparing query ListObjectVersions: %w", err)
+	}
 	if q.listObjectsStmt, err = db.PrepareContext(ctx, ListObjects); err != nil {
 		return nil, fmt.Errorf("error preparing query ListObjects: %w", err)
 	}
 	if q.listObjectsWithDelimiterStmt, err = db.PrepareContext(ctx, ListObjectsWithDelimiter); err != nil {
 		return nil, fmt.Errorf("error preparing query ListObjectsWithDelimiter: %w", err)
 	}
-	if q.listPendingNotificationJobsStmt, err = db.PrepareContext(ctx, ListPendingNotificationJobs); err != nil {
-		return nil, fmt.Errorf("error preparing query ListPendingNotificationJobs: %w", err)
+	if q.listReleasedBlobsStmt, err = db.PrepareContext(ctx, ListReleasedBlobs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListReleasedBlobs: %w", err)
 	}
 	if q.objectExistsStmt, err = db.PrepareContext(ctx, ObjectExists); err != nil {
 		return nil, fmt.Errorf("error preparing query ObjectExists: %w", err)
 	}
+	if q.promoteLatestObjectVersionStmt, err = db.PrepareContext(ctx, PromoteLatestObjectVersion); err != nil {
+		return nil, fmt.Errorf("error preparing query PromoteLatestObjectVersion: %w", err)
+	}
+	if q.purgeNotificationJobsStmt, err = db.PrepareContext(ctx, PurgeNotificationJobs); err != nil {
+		return nil, fmt.Errorf("error preparing query PurgeNotificationJobs: %w", err)
+	}
 	if q.putBucketPolicyStmt, err = db.PrepareContext(ctx, PutBucketPolicy); err != nil {
 		return nil, fmt.Errorf("error preparing query PutBucketPolicy: %w", err)
 	}
+	if q.putMultipartUploadPartStmt, err = db.PrepareContext(ctx, PutMultipartUploadPart); err != nil {
+		return nil, fmt.Errorf("error preparing query PutMultipartUploadPart: %w", err)
+	}
+	if q.releaseNotificationJobStmt, err = db.PrepareContext(ctx, ReleaseNotificationJob); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleaseNotificationJob: %w", err)
+	}
+	if q.requeueDeadLetterNotificationJobsStmt, err = db.PrepareContext(ctx, RequeueDeadLetterNotificationJobs); err != nil {
+		return nil, fmt.Errorf("error preparing query RequeueDeadLetterNotificationJobs: %w", err)
+	}
+	if q.retryNotificationJobStmt, err = db.PrepareContext(ctx, RetryNotificationJob); err != nil {
+		return nil, fmt.Errorf("error preparing query RetryNotificationJob: %w", err)
+	}
+	if q.scheduleNotificationJobRetryStmt, err = db.PrepareContext(ctx, ScheduleNotificationJobRetry); err != nil {
+		return nil, fmt.Errorf("error preparing query ScheduleNotificationJobRetry: %w", err)
+	}
+	if q.updateBucketVersioningStmt, err = db.PrepareContext(ctx, UpdateBucketVersioning); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateBucketVersioning: %w", err)
+	}
 	if q.updateNotificationStmt, err = db.PrepareContext(ctx, UpdateNotification); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateNotification: %w", err)
 	}
@@ -159,11 +291,19 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateObjectStmt, err = db.PrepareContext(ctx, UpdateObject); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateObject: %w", err)
 	}
+	if q.upsertObjectStmt, err = db.PrepareContext(ctx, UpsertObject); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertObject: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addNotificationJobDeliveredTargetStmt != nil {
+		if cerr := q.addNotificationJobDeliveredTargetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addNotificationJobDeliveredTargetStmt: %w", cerr)
+		}
+	}
 	if q.bucketExistsStmt != nil {
 		if cerr := q.bucketExistsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing bucketExistsStmt: %w", cerr)
@@ -174,9 +314,14 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing bucketPolicyExistsStmt: %w", cerr)
 		}
 	}
-	if q.copyObjectStmt != nil {
-		if cerr := q.copyObjectStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing copyObjectStmt: %w", cerr)
+	if q.cancelNotificationJobStmt != nil {
+		if cerr := q.cancelNotificationJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cancelNotificationJobStmt: %w", cerr)
+		}
+	}
+	if q.claimNotificationJobsStmt != nil {
+		if cerr := q.claimNotificationJobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing claimNotificationJobsStmt: %w", cerr)
 		}
 	}
 	if q.countObjectsInBucketStmt != nil {
@@ -184,6 +329,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing countObjectsInBucketStmt: %w", cerr)
 		}
 	}
+	if q.createBlobStmt != nil {
+		if cerr := q.createBlobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBlobStmt: %w", cerr)
+		}
+	}
+	if q.createBlobChunkStmt != nil {
+		if cerr := q.createBlobChunkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createBlobChunkStmt: %w", cerr)
+		}
+	}
 	if q.createBucketStmt != nil {
 		if cerr := q.createBucketStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createBucketStmt: %w", cerr)
@@ -194,16 +349,36 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createBucketTagStmt: %w", cerr)
 		}
 	}
+	if q.createConfigNotificationStmt != nil {
+		if cerr := q.createConfigNotificationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createConfigNotificationStmt: %w", cerr)
+		}
+	}
+	if q.createDeleteMarkerStmt != nil {
+		if cerr := q.createDeleteMarkerStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDeleteMarkerStmt: %w", cerr)
+		}
+	}
 	if q.createEventStmt != nil {
 		if cerr := q.createEventStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createEventStmt: %w", cerr)
 		}
 	}
+	if q.createMultipartUploadStmt != nil {
+		if cerr := q.createMultipartUploadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createMultipartUploadStmt: %w", cerr)
+		}
+	}
 	if q.createNotificationStmt != nil {
 		if cerr := q.createNotificationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createNotificationStmt: %w", cerr)
 		}
 	}
+	if q.createNotificationJobStmt != nil {
+		if cerr := q.createNotificationJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createNotificationJobStmt: %w", cerr)
+		}
+	}
 	if q.createObjectStmt != nil {
 		if cerr := q.createObjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createObjectStmt: %w", cerr)
@@ -214,6 +389,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createObjectMetadataStmt: %w", cerr)
 		}
 	}
+	if q.createObjectPartStmt != nil {
+		if cerr := q.createObjectPartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createObjectPartStmt: %w", cerr)
+		}
+	}
 	if q.createObjectTagStmt != nil {
 		if cerr := q.createObjectTagStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createObjectTagStmt: %w", cerr)
@@ -224,6 +404,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteAllObjectTagsStmt: %w", cerr)
 		}
 	}
+	if q.deleteBlobStmt != nil {
+		if cerr := q.deleteBlobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBlobStmt: %w", cerr)
+		}
+	}
+	if q.deleteBlobChunksStmt != nil {
+		if cerr := q.deleteBlobChunksStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteBlobChunksStmt: %w", cerr)
+		}
+	}
 	if q.deleteBucketStmt != nil {
 		if cerr := q.deleteBucketStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteBucketStmt: %w", cerr)
@@ -244,11 +434,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteBucketTagsStmt: %w", cerr)
 		}
 	}
+	if q.deleteMultipartUploadStmt != nil {
+		if cerr := q.deleteMultipartUploadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteMultipartUploadStmt: %w", cerr)
+		}
+	}
 	if q.deleteNotificationStmt != nil {
 		if cerr := q.deleteNotificationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteNotificationStmt: %w", cerr)
 		}
 	}
+	if q.deleteNotificationJobStmt != nil {
+		if cerr := q.deleteNotificationJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteNotificationJobStmt: %w", cerr)
+		}
+	}
 	if q.deleteObjectStmt != nil {
 		if cerr := q.deleteObjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteObjectStmt: %w", cerr)
@@ -259,11 +459,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteObjectMetadataStmt: %w", cerr)
 		}
 	}
+	if q.deleteObjectPartsStmt != nil {
+		if cerr := q.deleteObjectPartsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteObjectPartsStmt: %w", cerr)
+		}
+	}
 	if q.deleteObjectTagsStmt != nil {
 		if cerr := q.deleteObjectTagsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteObjectTagsStmt: %w", cerr)
 		}
 	}
+	if q.deleteObjectVersionByIDStmt != nil {
+		if cerr := q.deleteObjectVersionByIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteObjectVersionByIDStmt: %w", cerr)
+		}
+	}
+	if q.deleteReleasedBlobStmt != nil {
+		if cerr := q.deleteReleasedBlobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteReleasedBlobStmt: %w", cerr)
+		}
+	}
+	if q.demoteLatestObjectVersionStmt != nil {
+		if cerr := q.demoteLatestObjectVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing demoteLatestObjectVersionStmt: %w", cerr)
+		}
+	}
+	if q.getBlobChunkStmt != nil {
+		if cerr := q.getBlobChunkStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBlobChunkStmt: %w", cerr)
+		}
+	}
+	if q.getBlobSizeStmt != nil {
+		if cerr := q.getBlobSizeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBlobSizeStmt: %w", cerr)
+		}
+	}
 	if q.getBucketStmt != nil {
 		if cerr := q.getBucketStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getBucketStmt: %w", cerr)
@@ -279,11 +509,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getBucketTagsStmt: %w", cerr)
 		}
 	}
+	if q.getBucketVersioningStmt != nil {
+		if cerr := q.getBucketVersioningStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBucketVersioningStmt: %w", cerr)
+		}
+	}
+	if q.getLatestEventIDStmt != nil {
+		if cerr := q.getLatestEventIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestEventIDStmt: %w", cerr)
+		}
+	}
+	if q.getLatestObjectVersionStmt != nil {
+		if cerr := q.getLatestObjectVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLatestObjectVersionStmt: %w", cerr)
+		}
+	}
+	if q.getMultipartUploadStmt != nil {
+		if cerr := q.getMultipartUploadStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMultipartUploadStmt: %w", cerr)
+		}
+	}
+	if q.getMultipartUploadPartsWithBlobsStmt != nil {
+		if cerr := q.getMultipartUploadPartsWithBlobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMultipartUploadPartsWithBlobsStmt: %w", cerr)
+		}
+	}
 	if q.getNotificationStmt != nil {
 		if cerr := q.getNotificationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getNotificationStmt: %w", cerr)
 		}
 	}
+	if q.getNotificationJobStmt != nil {
+		if cerr := q.getNotificationJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getNotificationJobStmt: %w", cerr)
+		}
+	}
 	if q.getObjectStmt != nil {
 		if cerr := q.getObjectStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getObjectStmt: %w", cerr)
@@ -314,6 +574,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getObjectTagsStmt: %w", cerr)
 		}
 	}
+	if q.getObjectVersionStmt != nil {
+		if cerr := q.getObjectVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getObjectVersionStmt: %w", cerr)
+		}
+	}
+	if q.listBlobIDsStmt != nil {
+		if cerr := q.listBlobIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listBlobIDsStmt: %w", cerr)
+		}
+	}
 	if q.listBucketsStmt != nil {
 		if cerr := q.listBucketsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listBucketsStmt: %w", cerr)
@@ -329,11 +599,46 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listEnabledNotificationsByBucketStmt: %w", cerr)
 		}
 	}
+	if q.listEventsStmt != nil {
+		if cerr := q.listEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEventsStmt: %w", cerr)
+		}
+	}
+	if q.listEventsAfterStmt != nil {
+		if cerr := q.listEventsAfterStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listEventsAfterStmt: %w", cerr)
+		}
+	}
 	if q.listEventsByBucketStmt != nil {
 		if cerr := q.listEventsByBucketStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listEventsByBucketStmt: %w", cerr)
 		}
 	}
+	if q.listMultipartUploadPartsStmt != nil {
+		if cerr := q.listMultipartUploadPartsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMultipartUploadPartsStmt: %w", cerr)
+		}
+	}
+	if q.listMultipartUploadsStmt != nil {
+		if cerr := q.listMultipartUploadsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMultipartUploadsStmt: %w", cerr)
+		}
+	}
+	if q.listNotificationJobsStmt != nil {
+		if cerr := q.listNotificationJobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNotificationJobsStmt: %w", cerr)
+		}
+	}
+	if q.listNotificationJobsByEventStmt != nil {
+		if cerr := q.listNotificationJobsByEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNotificationJobsByEventStmt: %w", cerr)
+		}
+	}
+	if q.listNotificationJobsUpdatedSinceStmt != nil {
+		if cerr := q.listNotificationJobsUpdatedSinceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listNotificationJobsUpdatedSinceStmt: %w", cerr)
+		}
+	}
 	if q.listNotificationsByBucketStmt != nil {
 		if cerr := q.listNotificationsByBucketStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listNotificationsByBucketStmt: %w", cerr)
@@ -344,6 +649,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listNotificationsByEventTypeStmt: %w", cerr)
 		}
 	}
+	if q.listObjectPartsStmt != nil {
+		if cerr := q.listObjectPartsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectPartsStmt: %w", cerr)
+		}
+	}
+	if q.listObjectVersionsStmt != nil {
+		if cerr := q.listObjectVersionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listObjectVersionsStmt: %w", cerr)
+		}
+	}
 	if q.listObjectsStmt != nil {
 		if cerr := q.listObjectsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listObjectsStmt: %w", cerr)
@@ -354,9 +669,9 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listObjectsWithDelimiterStmt: %w", cerr)
 		}
 	}
-	if q.listPendingNotificationJobsStmt != nil {
-		if cerr := q.listPendingNotificationJobsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing listPendingNotificationJobsStmt: %w", cerr)
+	if q.listReleasedBlobsStmt != nil {
+		if cerr := q.listReleasedBlobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listReleasedBlobsStmt: %w", cerr)
 		}
 	}
 	if q.objectExistsStmt != nil {
@@ -364,11 +679,51 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing objectExistsStmt: %w", cerr)
 		}
 	}
+	if q.promoteLatestObjectVersionStmt != nil {
+		if cerr := q.promoteLatestObjectVersionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing promoteLatestObjectVersionStmt: %w", cerr)
+		}
+	}
+	if q.purgeNotificationJobsStmt != nil {
+		if cerr := q.purgeNotificationJobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing purgeNotificationJobsStmt: %w", cerr)
+		}
+	}
 	if q.putBucketPolicyStmt != nil {
 		if cerr := q.putBucketPolicyStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing putBucketPolicyStmt: %w", cerr)
 		}
 	}
+	if q.putMultipartUploadPartStmt != nil {
+		if cerr := q.putMultipartUploadPartStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing putMultipartUploadPartStmt: %w", cerr)
+		}
+	}
+	if q.releaseNotificationJobStmt != nil {
+		if cerr := q.releaseNotificationJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releaseNotificationJobStmt: %w", cerr)
+		}
+	}
+	if q.requeueDeadLetterNotificationJobsStmt != nil {
+		if cerr := q.requeueDeadLetterNotificationJobsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing requeueDeadLetterNotificationJobsStmt: %w", cerr)
+		}
+	}
+	if q.retryNotificationJobStmt != nil {
+		if cerr := q.retryNotificationJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing retryNotificationJobStmt: %w", cerr)
+		}
+	}
+	if q.scheduleNotificationJobRetryStmt != nil {
+		if cerr := q.scheduleNotificationJobRetryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing scheduleNotificationJobRetryStmt: %w", cerr)
+		}
+	}
+	if q.updateBucketVersioningStmt != nil {
+		if cerr := q.updateBucketVersioningStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateBucketVersioningStmt: %w", cerr)
+		}
+	}
 	if q.updateNotificationStmt != nil {
 		if cerr := q.updateNotificationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateNotificationStmt: %w", cerr)
@@ -389,6 +744,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateObjectStmt: %w", cerr)
 		}
 	}
+	if q.upsertObjectStmt != nil {
+		if cerr := q.upsertObjectStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertObjectStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -426,103 +786,193 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 }
 
 type Queries struct {
-	db                                   DBTX
-	tx                                   *sql.Tx
-	bucketExistsStmt                     *sql.Stmt
-	bucketPolicyExistsStmt               *sql.Stmt
-	copyObjectStmt                       *sql.Stmt
-	countObjectsInBucketStmt             *sql.Stmt
-	createBucketStmt                     *sql.Stmt
-	createBucketTagStmt                  *sql.Stmt
-	createEventStmt                      *sql.Stmt
-	createNotificationStmt               *sql.Stmt
-	createObjectStmt                     *sql.Stmt
-	createObjectMetadataStmt             *sql.Stmt
-	createObjectTagStmt                  *sql.Stmt
-	deleteAllObjectTagsStmt              *sql.Stmt
-	deleteBucketStmt                     *sql.Stmt
-	deleteBucketPolicyStmt               *sql.Stmt
-	deleteBucketTagStmt                  *sql.Stmt
-	deleteBucketTagsStmt                 *sql.Stmt
-	deleteNotificationStmt               *sql.Stmt
-	deleteObjectStmt                     *sql.Stmt
-	deleteObjectMetadataStmt             *sql.Stmt
-	deleteObjectTagsStmt                 *sql.Stmt
-	getBucketStmt                        *sql.Stmt
-	getBucketPolicyStmt                  *sql.Stmt
-	getBucketTagsStmt                    *sql.Stmt
-	getNotificationStmt                  *sql.Stmt
-	getObjectStmt                        *sql.Stmt
-	getObjectByIDStmt                    *sql.Stmt
-	getObjectIDStmt                      *sql.Stmt
-	getObjectMetadataStmt                *sql.Stmt
-	getObjectMetadataByObjectIDStmt      *sql.Stmt
-	getObjectTagsStmt                    *sql.Stmt
-	listBucketsStmt                      *sql.Stmt
-	listBucketsFilteredStmt              *sql.Stmt
-	listEnabledNotificationsByBucketStmt *sql.Stmt
-	listEventsByBucketStmt               *sql.Stmt
-	listNotificationsByBucketStmt        *sql.Stmt
-	listNotificationsByEventTypeStmt     *sql.Stmt
-	listObjectsStmt                      *sql.Stmt
-	listObjectsWithDelimiterStmt         *sql.Stmt
-	listPendingNotificationJobsStmt      *sql.Stmt
-	objectExistsStmt                     *sql.Stmt
-	putBucketPolicyStmt                  *sql.Stmt
-	updateNotificationStmt               *sql.Stmt
-	updateNotificationEnabledStmt        *sql.Stmt
-	updateNotificationJobStatusStmt      *sql.Stmt
-	updateObjectStmt                     *sql.Stmt
+	db                                    DBTX
+	tx                                    *sql.Tx
+	addNotificationJobDeliveredTargetStmt *sql.Stmt
+	bucketExistsStmt                      *sql.Stmt
+	bucketPolicyExistsStmt                *sql.Stmt
+	cancelNotificationJobStmt             *sql.Stmt
+	claimNotificationJobsStmt             *sql.Stmt
+	countObjectsInBucketStmt              *sql.Stmt
+	createBlobStmt                        *sql.Stmt
+	createBlobChunkStmt                   *sql.Stmt
+	createBucketStmt                      *sql.Stmt
+	createBucketTagStmt                   *sql.Stmt
+	createConfigNotificationStmt          *sql.Stmt
+	createDeleteMarkerStmt                *sql.Stmt
+	createEventStmt                       *sql.Stmt
+	createMultipartUploadStmt             *sql.Stmt
+	createNotificationStmt                *sql.Stmt
+	createNotificationJobStmt             *sql.Stmt
+	createObjectStmt                      *sql.Stmt
+	createObjectMetadataStmt              *sql.Stmt
+	createObjectPartStmt                  *sql.Stmt
+	createObjectTagStmt                   *sql.Stmt
+	deleteAllObjectTagsStmt               *sql.Stmt
+	deleteBlobStmt                        *sql.Stmt
+	deleteBlobChunksStmt                  *sql.Stmt
+	deleteBucketStmt                      *sql.Stmt
+	deleteBucketPolicyStmt                *sql.Stmt
+	deleteBucketTagStmt                   *sql.Stmt
+	deleteBucketTagsStmt                  *sql.Stmt
+	deleteMultipartUploadStmt             *sql.Stmt
+	deleteNotificationStmt                *sql.Stmt
+	deleteNotificationJobStmt             *sql.Stmt
+	deleteObjectStmt                      *sql.Stmt
+	deleteObjectMetadataStmt              *sql.Stmt
+	deleteObjectPartsStmt                 *sql.Stmt
+	deleteObjectTagsStmt                  *sql.Stmt
+	deleteObjectVersionByIDStmt           *sql.Stmt
+	deleteReleasedBlobStmt                *sql.Stmt
+	demoteLatestObjectVersionStmt         *sql.Stmt
+	getBlobChunkStmt                      *sql.Stmt
+	getBlobSizeStmt                       *sql.Stmt
+	getBucketStmt                         *sql.Stmt
+	getBucketPolicyStmt                   *sql.Stmt
+	getBucketTagsStmt                     *sql.Stmt
+	getBucketVersioningStmt               *sql.Stmt
+	getLatestEventIDStmt                  *sql.Stmt
+	getLatestObjectVersionStmt            *sql.Stmt
+	getMultipartUploadStmt                *sql.Stmt
+	getMultipartUploadPartsWithBlobsStmt  *sql.Stmt
+	getNotificationStmt                   *sql.Stmt
+	getNotificationJobStmt                *sql.Stmt
+	getObjectStmt                         *sql.Stmt
+	getObjectByIDStmt                     *sql.Stmt
+	getObjectIDStmt                       *sql.Stmt
+	getObjectMetadataStmt                 *sql.Stmt
+	getObjectMetadataByObjectIDStmt       *sql.Stmt
+	getObjectTagsStmt                     *sql.Stmt
+	getObjectVersionStmt                  *sql.Stmt
+	listBlobIDsStmt                       *sql.Stmt
+	listBucketsStmt                       *sql.Stmt
+	listBucketsFilteredStmt               *sql.Stmt
+	listEnabledNotificationsByBucketStmt  *sql.Stmt
+	listEventsStmt                        *sql.Stmt
+	listEventsAfterStmt                   *sql.Stmt
+	listEventsByBucketStmt                *sql.Stmt
+	listMultipartUploadPartsStmt          *sql.Stmt
+	listMultipartUploadsStmt              *sql.Stmt
+	listNotificationJobsStmt              *sql.Stmt
+	listNotificationJobsByEventStmt       *sql.Stmt
+	listNotificationJobsUpdatedSinceStmt  *sql.Stmt
+	listNotificationsByBucketStmt         *sql.Stmt
+	listNotificationsByEventTypeStmt      *sql.Stmt
+	listObjectPartsStmt                   *sql.Stmt
+	listObjectVersionsStmt                *sql.Stmt
+	listObjectsStmt                       *sql.Stmt
+	listObjectsWithDelimiterStmt          *sql.Stmt
+	listReleasedBlobsStmt                 *sql.Stmt
+	objectExistsStmt                      *sql.Stmt
+	promoteLatestObjectVersionStmt        *sql.Stmt
+	purgeNotificationJobsStmt             *sql.Stmt
+	putBucketPolicyStmt                   *sql.Stmt
+	putMultipartUploadPartStmt            *sql.Stmt
+	releaseNotificationJobStmt            *sql.Stmt
+	requeueDeadLetterNotificationJobsStmt *sql.Stmt
+	retryNotificationJobStmt              *sql.Stmt
+	scheduleNotificationJobRetryStmt      *sql.Stmt
+	updateBucketVersioningStmt            *sql.Stmt
+	updateNotificationStmt                *sql.Stmt
+	updateNotificationEnabledStmt         *sql.Stmt
+	updateNotificationJobStatusStmt       *sql.Stmt
+	updateObjectStmt                      *sql.Stmt
+	upsertObjectStmt                      *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                                   tx,
-		tx:                                   tx,
-		bucketExistsStmt:                     q.bucketExistsStmt,
-		bucketPolicyExistsStmt:               q.bucketPolicyExistsStmt,
-		copyObjectStmt:                       q.copyObjectStmt,
-		countObjectsInBucketStmt:             q.countObjectsInBucketStmt,
-		createBucketStmt:                     q.createBucketStmt,
-		createBucketTagStmt:                  q.createBucketTagStmt,
-		createEventStmt:                      q.createEventStmt,
-		createNotificationStmt:               q.createNotificationStmt,
-		createObjectStmt:                     q.createObjectStmt,
-		createObjectMetadataStmt:             q.createObjectMetadataStmt,
-		createObjectTagStmt:                  q.createObjectTagStmt,
-		deleteAllObjectTagsStmt:              q.deleteAllObjectTagsStmt,
-		deleteBucketStmt:                     q.deleteBucketStmt,
-		deleteBucketPolicyStmt:               q.deleteBucketPolicyStmt,
-		deleteBucketTagStmt:                  q.deleteBucketTagStmt,
-		deleteBucketTagsStmt:                 q.deleteBucketTagsStmt,
-		deleteNotificationStmt:               q.deleteNotificationStmt,
-		deleteObjectStmt:                     q.deleteObjectStmt,
-		deleteObjectMetadataStmt:             q.deleteObjectMetadataStmt,
-		deleteObjectTagsStmt:                 q.deleteObjectTagsStmt,
-		getBucketStmt:                        q.getBucketStmt,
-		getBucketPolicyStmt:                  q.getBucketPolicyStmt,
-		getBucketTagsStmt:                    q.getBucketTagsStmt,
-		getNotificationStmt:                  q.getNotificationStmt,
-		getObjectStmt:                        q.getObjectStmt,
-		getObjectByIDStmt:                    q.getObjectByIDStmt,
-		getObjectIDStmt:                      q.getObjectIDStmt,
-		getObjectMetadataStmt:                q.getObjectMetadataStmt,
-		getObjectMetadataByObjectIDStmt:      q.getObjectMetadataByObjectIDStmt,
-		getObjectTagsStmt:                    q.getObjectTagsStmt,
-		listBucketsStmt:                      q.listBucketsStmt,
-		listBucketsFilteredStmt:              q.listBucketsFilteredStmt,
-		listEnabledNotificationsByBucketStmt: q.listEnabledNotificationsByBucketStmt,
-		listEventsByBucketStmt:               q.listEventsByBucketStmt,
-		listNotificationsByBucketStmt:        q.listNotificationsByBucketStmt,
-		listNotificationsByEventTypeStmt:     q.listNotificationsByEventTypeStmt,
-		listObjectsStmt:                      q.listObjectsStmt,
-		listObjectsWithDelimiterStmt:         q.listObjectsWithDelimiterStmt,
-		listPendingNotificationJobsStmt:      q.listPendingNotificationJobsStmt,
-		objectExistsStmt:                     q.objectExistsStmt,
-		putBucketPolicyStmt:                  q.putBucketPolicyStmt,
-		updateNotificationStmt:               q.updateNotificationStmt,
-		updateNotificationEnabledStmt:        q.updateNotificationEnabledStmt,
-		updateNotificationJobStatusStmt:      q.updateNotificationJobStatusStmt,
-		updateObjectStmt:                     q.updateObjectStmt,
+		db:                                    tx,
+		tx:                                    tx,
+		addNotificationJobDeliveredTargetStmt: q.addNotificationJobDeliveredTargetStmt,
+		bucketExistsStmt:                      q.bucketExistsStmt,
+		bucketPolicyExistsStmt:                q.bucketPolicyExistsStmt,
+		cancelNotificationJobStmt:             q.cancelNotificationJobStmt,
+		claimNotificationJobsStmt:             q.claimNotificationJobsStmt,
+		countObjectsInBucketStmt:              q.countObjectsInBucketStmt,
+		createBlobStmt:                        q.createBlobStmt,
+		createBlobChunkStmt:                   q.createBlobChunkStmt,
+		createBucketStmt:                      q.createBucketStmt,
+		createBucketTagStmt:                   q.createBucketTagStmt,
+		createConfigNotificationStmt:          q.createConfigNotificationStmt,
+		createDeleteMarkerStmt:                q.createDeleteMarkerStmt,
+		createEventStmt:                       q.createEventStmt,
+		createMultipartUploadStmt:             q.createMultipartUploadStmt,
+		createNotificationStmt:                q.createNotificationStmt,
+		createNotificationJobStmt:             q.createNotificationJobStmt,
+		createObjectStmt:                      q.createObjectStmt,
+		createObjectMetadataStmt:              q.createObjectMetadataStmt,
+		createObjectPartStmt:                  q.createObjectPartStmt,
+		createObjectTagStmt:                   q.createObjectTagStmt,
+		deleteAllObjectTagsStmt:               q.deleteAllObjectTagsStmt,
+		deleteBlobStmt:                        q.deleteBlobStmt,
+		deleteBlobChunksStmt:                  q.deleteBlobChunksStmt,
+		deleteBucketStmt:                      q.deleteBucketStmt,
+		deleteBucketPolicyStmt:                q.deleteBucketPolicyStmt,
+		deleteBucketTagStmt:                   q.deleteBucketTagStmt,
+		deleteBucketTagsStmt:                  q.deleteBucketTagsStmt,
+		deleteMultipartUploadStmt:             q.deleteMultipartUploadStmt,
+		deleteNotificationStmt:                q.deleteNotificationStmt,
+		deleteNotificationJobStmt:             q.deleteNotificationJobStmt,
+		deleteObjectStmt:                      q.deleteObjectStmt,
+		deleteObjectMetadataStmt:              q.deleteObjectMetadataStmt,
+		deleteObjectPartsStmt:                 q.deleteObjectPartsStmt,
+		deleteObjectTagsStmt:                  q.deleteObjectTagsStmt,
+		deleteObjectVersionByIDStmt:           q.deleteObjectVersionByIDStmt,
+		deleteReleasedBlobStmt:                q.deleteReleasedBlobStmt,
+		demoteLatestObjectVersionStmt:         q.demoteLatestObjectVersionStmt,
+		getBlobChunkStmt:                      q.getBlobChunkStmt,
+		getBlobSizeStmt:                       q.getBlobSizeStmt,
+		getBucketStmt:                         q.getBucketStmt,
+		getBucketPolicyStmt:                   q.getBucketPolicyStmt,
+		getBucketTagsStmt:                     q.getBucketTagsStmt,
+		getBucketVersioningStmt:               q.getBucketVersioningStmt,
+		getLatestEventIDStmt:                  q.getLatestEventIDStmt,
+		getLatestObjectVersionStmt:            q.getLatestObjectVersionStmt,
+		getMultipartUploadStmt:                q.getMultipartUploadStmt,
+		getMultipartUploadPartsWithBlobsStmt:  q.getMultipartUploadPartsWithBlobsStmt,
+		getNotificationStmt:                   q.getNotificationStmt,
+		getNotificationJobStmt:                q.getNotificationJobStmt,
+		getObjectStmt:                         q.getObjectStmt,
+		getObjectByIDStmt:                     q.getObjectByIDStmt,
+		getObjectIDStmt:                       q.getObjectIDStmt,
+		getObjectMetadataStmt:                 q.getObjectMetadataStmt,
+		getObjectMetadataByObjectIDStmt:       q.getObjectMetadataByObjectIDStmt,
+		getObjectTagsStmt:                     q.getObjectTagsStmt,
+		getObjectVersionStmt:                  q.getObjectVersionStmt,
+		listBlobIDsStmt:                       q.listBlobIDsStmt,
+		listBucketsStmt:                       q.listBucketsStmt,
+		listBucketsFilteredStmt:               q.listBucketsFilteredStmt,
+		listEnabledNotificationsByBucketStmt:  q.listEnabledNotificationsByBucketStmt,
+		listEventsStmt:                        q.listEventsStmt,
+		listEventsAfterStmt:                   q.listEventsAfterStmt,
+		listEventsByBucketStmt:                q.listEventsByBucketStmt,
+		listMultipartUploadPartsStmt:          q.listMultipartUploadPartsStmt,
+		listMultipartUploadsStmt:              q.listMultipartUploadsStmt,
+		listNotificationJobsStmt:              q.listNotificationJobsStmt,
+		listNotificationJobsByEventStmt:       q.listNotificationJobsByEventStmt,
+		listNotificationJobsUpdatedSinceStmt:  q.listNotificationJobsUpdatedSinceStmt,
+		listNotificationsByBucketStmt:         q.listNotificationsByBucketStmt,
+		listNotificationsByEventTypeStmt:      q.listNotificationsByEventTypeStmt,
+		listObjectPartsStmt:                   q.listObjectPartsStmt,
+		listObjectVersionsStmt:                q.listObjectVersionsStmt,
+		listObjectsStmt:                       q.listObjectsStmt,
+		listObjectsWithDelimiterStmt:          q.listObjectsWithDelimiterStmt,
+		listReleasedBlobsStmt:                 q.listReleasedBlobsStmt,
+		objectExistsStmt:                      q.objectExistsStmt,
+		promoteLatestObjectVersionStmt:        q.promoteLatestObjectVersionStmt,
+		purgeNotificationJobsStmt:             q.purgeNotificationJobsStmt,
+		putBucketPolicyStmt:                   q.putBucketPolicyStmt,
+		putMultipartUploadPartStmt:            q.putMultipartUploadPartStmt,
+		releaseNotificationJobStmt:            q.releaseNotificationJobStmt,
+		requeueDeadLetterNotificationJobsStmt: q.requeueDeadLetterNotificationJobsStmt,
+		retryNotificationJobStmt:              q.retryNotificationJobStmt,
+		scheduleNotificationJobRetryStmt:      q.scheduleNotificationJobRetryStmt,
+		updateBucketVersioningStmt:            q.updateBucketVersioningStmt,
+		updateNotificationStmt:                q.updateNotificationStmt,
+		updateNotificationEnabledStmt:         q.updateNotificationEnabledStmt,
+		updateNotificationJobStatusStmt:       q.updateNotificationJobStatusStmt,
+		updateObjectStmt:                      q.updateObjectStmt,
+		upsertObjectStmt:                      q.upsertObjectStmt,
 	}
 }
@@ -1,28 +1,41 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 // source: event.sql
 
 package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const CreateEvent = `-- name: CreateEvent :one
-INSERT INTO events (bucket_name, object_id, event_type)
-VALUES (?, ?, ?)
-RETURNING id, bucket_name, object_id, event_type, event_time
+INSERT INTO events (bucket_name, object_id, event_type, object_key, object_size, object_etag, object_version_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
 `
 
 type CreateEventParams struct {
-	BucketName string `json:"bucket_name"`
-	ObjectID   int64  `json:"object_id"`
-	EventType  string `json:"event_type"`
+	BucketName      string         `json:"bucket_name"`
+	ObjectID        sql.NullInt64  `json:"object_id"`
+	EventType       string         `json:"event_type"`
+	ObjectKey       string         `json:"object_key"`
+	ObjectSize      int64          `json:"object_size"`
+	ObjectETag      string         `json:"object_etag"`
+	ObjectVersionID sql.NullString `json:"object_version_id"`
 }
 
 func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error) {
-	row := q.queryRow(ctx, q.createEventStmt, CreateEvent, arg.BucketName, arg.ObjectID, arg.EventType)
+	row := q.queryRow(ctx, q.createEventStmt, CreateEvent,
+		arg.BucketName,
+		arg.ObjectID,
+		arg.EventType,
+		arg.ObjectKey,
+		arg.ObjectSize,
+		arg.ObjectETag,
+		arg.ObjectVersionID,
+	)
 	var i Event
 	err := row.Scan(
 		&i.ID,
@@ -30,12 +43,141 @@ func (q *Queries) CreateEvent(ctx context.Context, arg CreateEventParams) (Event
 		&i.ObjectID,
 		&i.EventType,
 		&i.EventTime,
+		&i.ObjectKey,
+		&i.ObjectSize,
+		&i.ObjectETag,
+		&i.ObjectVersionID,
 	)
 	return i, err
 }
 
+const GetLatestEventID = `-- name: GetLatestEventID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS id
+FROM events
+`
+
+func (q *Queries) GetLatestEventID(ctx context.Context) (int64, error) {
+	row := q.queryRow(ctx, q.getLatestEventIDStmt, GetLatestEventID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const ListEvents = `-- name: ListEvents :many
+SELECT id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
+FROM events
+WHERE (CAST(?1 AS TEXT) IS NULL OR bucket_name = CAST(?1 AS TEXT))
+  AND (CAST(?2 AS TEXT) IS NULL
+       OR EXISTS (SELECT 1 FROM notification_jobs WHERE event_id = events.id AND status = CAST(?2 AS TEXT)))
+  AND (CAST(?3 AS TEXT) IS NULL OR event_time >= CAST(?3 AS TEXT))
+  AND (CAST(?4 AS TEXT) IS NULL OR event_time < CAST(?4 AS TEXT))
+ORDER BY id DESC
+LIMIT ?6 OFFSET ?5
+`
+
+type ListEventsParams struct {
+	BucketName sql.NullString `json:"bucket_name"`
+	Status     sql.NullString `json:"status"`
+	Since      sql.NullString `json:"since"`
+	Until      sql.NullString `json:"until"`
+	SkipEvents int64          `json:"skip_events"`
+	MaxEvents  int64          `json:"max_events"`
+}
+
+// Lists events, newest first, optionally only those of a bucket, with a job
+// in the given status or that occurred in [since, until)
+func (q *Queries) ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error) {
+	rows, err := q.query(ctx, q.listEventsStmt, ListEvents,
+		arg.BucketName,
+		arg.Status,
+		arg.Since,
+		arg.Until,
+		arg.SkipEvents,
+		arg.MaxEvents,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Event{}
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.BucketName,
+			&i.ObjectID,
+			&i.EventType,
+			&i.EventTime,
+			&i.ObjectKey,
+			&i.ObjectSize,
+			&i.ObjectETag,
+			&i.ObjectVersionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListEventsAfter = `-- name: ListEventsAfter :many
+SELECT id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
+FROM events
+WHERE id > ?1
+  AND (CAST(?2 AS TEXT) IS NULL OR bucket_name = CAST(?2 AS TEXT))
+ORDER BY id
+LIMIT ?3
+`
+
+type ListEventsAfterParams struct {
+	AfterID    int64          `json:"after_id"`
+	BucketName sql.NullString `json:"bucket_name"`
+	MaxEvents  int64          `json:"max_events"`
+}
+
+// Lists the events recorded after the event with the given ID, oldest first,
+// optionally only those of a bucket
+func (q *Queries) ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]Event, error) {
+	rows, err := q.query(ctx, q.listEventsAfterStmt, ListEventsAfter, arg.AfterID, arg.BucketName, arg.MaxEvents)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Event{}
+	for rows.Next() {
+		var i Event
+		if err := rows.Scan(
+			&i.ID,
+			&i.BucketName,
+			&i.ObjectID,
+			&i.EventType,
+			&i.EventTime,
+			&i.ObjectKey,
+			&i.ObjectSize,
+			&i.ObjectETag,
+			&i.ObjectVersionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const ListEventsByBucket = `-- name: ListEventsByBucket :many
-SELECT id, bucket_name, object_id, event_type, event_time
+SELECT id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
 FROM events
 WHERE bucket_name = ?
 ORDER BY event_time DESC
@@ -63,6 +205,10 @@ func (q *Queries) ListEventsByBucket(ctx context.Context, arg ListEventsByBucket
 			&i.ObjectID,
 			&i.EventType,
 			&i.EventTime,
+			&i.ObjectKey,
+			&i.ObjectSize,
+			&i.ObjectETag,
+			&i.ObjectVersionID,
 		); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS object_parts;
+DROP TABLE IF EXISTS multipart_upload_parts;
+DROP TABLE IF EXISTS multipart_uploads;
@@ -0,0 +1,44 @@
+-- Multipart uploads table (uploads in progress)
+CREATE TABLE IF NOT EXISTS multipart_uploads (
+    upload_id TEXT PRIMARY KEY NOT NULL,
+    bucket_name TEXT NOT NULL,
+    key TEXT NOT NULL,
+    content_type TEXT NOT NULL DEFAULT 'application/octet-stream',
+    content_encoding TEXT,
+    content_disposition TEXT,
+    cache_control TEXT,
+    storage_class TEXT NOT NULL DEFAULT 'STANDARD',
+    metadata TEXT NOT NULL DEFAULT '{}', -- JSON encoded user metadata
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_multipart_uploads_bucket_key ON multipart_uploads(bucket_name, key);
+
+-- Multipart upload parts table
+CREATE TABLE IF NOT EXISTS multipart_upload_parts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    upload_id TEXT NOT NULL,
+    part_number INTEGER NOT NULL,
+    data BLOB,
+    size INTEGER NOT NULL,
+    etag TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (upload_id) REFERENCES multipart_uploads(upload_id) ON DELETE CASCADE,
+    UNIQUE(upload_id, part_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_multipart_upload_parts_upload_id ON multipart_upload_parts(upload_id);
+
+-- Object parts table (part layout of objects created by CompleteMultipartUpload)
+CREATE TABLE IF NOT EXISTS object_parts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    object_id INTEGER NOT NULL,
+    part_number INTEGER NOT NULL,
+    size INTEGER NOT NULL,
+    etag TEXT NOT NULL,
+    FOREIGN KEY (object_id) REFERENCES objects(id) ON DELETE CASCADE,
+    UNIQUE(object_id, part_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_object_parts_object_id ON object_parts(object_id);
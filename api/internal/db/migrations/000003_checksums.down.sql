@@ -0,0 +1,10 @@
+ALTER TABLE object_parts DROP COLUMN checksum_value;
+
+ALTER TABLE multipart_upload_parts DROP COLUMN checksum_value;
+
+ALTER TABLE multipart_uploads DROP COLUMN checksum_type;
+ALTER TABLE multipart_uploads DROP COLUMN checksum_algorithm;
+
+ALTER TABLE objects DROP COLUMN checksum_type;
+ALTER TABLE objects DROP COLUMN checksum_value;
+ALTER TABLE objects DROP COLUMN checksum_algorithm;
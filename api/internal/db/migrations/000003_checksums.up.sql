@@ -0,0 +1,11 @@
+-- Additional checksums (x-amz-checksum-*)
+ALTER TABLE objects ADD COLUMN checksum_algorithm TEXT;
+ALTER TABLE objects ADD COLUMN checksum_value TEXT;
+ALTER TABLE objects ADD COLUMN checksum_type TEXT;
+
+ALTER TABLE multipart_uploads ADD COLUMN checksum_algorithm TEXT;
+ALTER TABLE multipart_uploads ADD COLUMN checksum_type TEXT;
+
+ALTER TABLE multipart_upload_parts ADD COLUMN checksum_value TEXT;
+
+ALTER TABLE object_parts ADD COLUMN checksum_value TEXT;
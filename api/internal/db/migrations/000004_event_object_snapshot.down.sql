@@ -0,0 +1,43 @@
+-- Events of deleted objects cannot reference an object row and are dropped
+CREATE TABLE events_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bucket_name TEXT NOT NULL,
+    object_id INTEGER NOT NULL,
+    event_type TEXT NOT NULL,
+    event_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE,
+    FOREIGN KEY (object_id) REFERENCES objects(id) ON DELETE CASCADE
+);
+
+INSERT INTO events_old (id, bucket_name, object_id, event_type, event_time)
+SELECT id, bucket_name, object_id, event_type, event_time
+FROM events
+WHERE object_id IS NOT NULL;
+
+CREATE TEMP TABLE notification_jobs_backup AS SELECT * FROM notification_jobs;
+
+DROP TRIGGER IF EXISTS create_notification_jobs_on_event_insert;
+DROP TABLE events;
+ALTER TABLE events_old RENAME TO events;
+
+INSERT INTO notification_jobs SELECT * FROM notification_jobs_backup
+WHERE event_id IN (SELECT id FROM events);
+DROP TABLE notification_jobs_backup;
+
+CREATE INDEX IF NOT EXISTS idx_events_bucket_name ON events(bucket_name);
+CREATE INDEX IF NOT EXISTS idx_events_object_id ON events(object_id);
+CREATE INDEX IF NOT EXISTS idx_events_event_time ON events(event_time);
+
+CREATE TRIGGER IF NOT EXISTS create_notification_jobs_on_event_insert
+AFTER INSERT ON events
+FOR EACH ROW
+BEGIN
+    INSERT INTO notification_jobs (event_id, notification_id)
+    SELECT
+        NEW.id,
+        n.id
+    FROM notifications n
+    WHERE n.bucket_name = NEW.bucket_name
+      AND n.event_type = NEW.event_type
+      AND n.enabled = 1;
+END;
@@ -0,0 +1,50 @@
+-- Events keep a snapshot of the object so that ObjectRemoved events outlive
+-- the object row. SQLite cannot change a foreign key in place, so the table is
+-- rebuilt. Dropping events cascades to notification_jobs, which are restored
+-- afterwards.
+CREATE TABLE events_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bucket_name TEXT NOT NULL,
+    object_id INTEGER,
+    event_type TEXT NOT NULL,
+    event_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    object_key TEXT NOT NULL DEFAULT '',
+    object_size INTEGER NOT NULL DEFAULT 0,
+    object_etag TEXT NOT NULL DEFAULT '',
+    object_version_id TEXT,
+    FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE,
+    FOREIGN KEY (object_id) REFERENCES objects(id) ON DELETE SET NULL
+);
+
+INSERT INTO events_new (id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id)
+SELECT e.id, e.bucket_name, e.object_id, e.event_type, e.event_time, o.key, o.size, o.etag, o.version_id
+FROM events e
+JOIN objects o ON o.id = e.object_id;
+
+CREATE TEMP TABLE notification_jobs_backup AS SELECT * FROM notification_jobs;
+
+DROP TRIGGER IF EXISTS create_notification_jobs_on_event_insert;
+DROP TABLE events;
+ALTER TABLE events_new RENAME TO events;
+
+INSERT INTO notification_jobs SELECT * FROM notification_jobs_backup
+WHERE event_id IN (SELECT id FROM events);
+DROP TABLE notification_jobs_backup;
+
+CREATE INDEX IF NOT EXISTS idx_events_bucket_name ON events(bucket_name);
+CREATE INDEX IF NOT EXISTS idx_events_object_id ON events(object_id);
+CREATE INDEX IF NOT EXISTS idx_events_event_time ON events(event_time);
+
+CREATE TRIGGER IF NOT EXISTS create_notification_jobs_on_event_insert
+AFTER INSERT ON events
+FOR EACH ROW
+BEGIN
+    INSERT INTO notification_jobs (event_id, notification_id)
+    SELECT
+        NEW.id,
+        n.id
+    FROM notifications n
+    WHERE n.bucket_name = NEW.bucket_name
+      AND n.event_type = NEW.event_type
+      AND n.enabled = 1;
+END;
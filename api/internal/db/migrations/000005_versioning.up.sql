@@ -0,0 +1,73 @@
+-- Bucket versioning state: NULL (never enabled), 'Enabled' or 'Suspended'
+ALTER TABLE buckets ADD COLUMN versioning_status TEXT;
+
+-- Objects may have several versions per key. The UNIQUE(bucket_name, key)
+-- constraint cannot be dropped in place, so the table is rebuilt. Dropping
+-- objects cascades to the tables that reference it, which are restored
+-- afterwards.
+CREATE TABLE objects_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    bucket_name TEXT NOT NULL,
+    key TEXT NOT NULL,
+    data BLOB,
+    size INTEGER NOT NULL,
+    etag TEXT NOT NULL,
+    content_type TEXT NOT NULL DEFAULT 'application/octet-stream',
+    content_encoding TEXT,
+    content_disposition TEXT,
+    cache_control TEXT,
+    expires DATETIME,
+    storage_class TEXT NOT NULL DEFAULT 'STANDARD',
+    server_side_encryption TEXT,
+    version_id TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checksum_algorithm TEXT,
+    checksum_value TEXT,
+    checksum_type TEXT,
+    is_latest BOOLEAN NOT NULL DEFAULT 1,
+    is_delete_marker BOOLEAN NOT NULL DEFAULT 0,
+    FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE
+);
+
+INSERT INTO objects_new (
+    id, bucket_name, key, data, size, etag, content_type, content_encoding,
+    content_disposition, cache_control, expires, storage_class,
+    server_side_encryption, version_id, created_at, updated_at,
+    checksum_algorithm, checksum_value, checksum_type
+)
+SELECT
+    id, bucket_name, key, data, size, etag, content_type, content_encoding,
+    content_disposition, cache_control, expires, storage_class,
+    server_side_encryption, version_id, created_at, updated_at,
+    checksum_algorithm, checksum_value, checksum_type
+FROM objects;
+
+CREATE TEMP TABLE object_metadata_backup AS SELECT * FROM object_metadata;
+CREATE TEMP TABLE object_tags_backup AS SELECT * FROM object_tags;
+CREATE TEMP TABLE object_parts_backup AS SELECT * FROM object_parts;
+CREATE TEMP TABLE event_objects_backup AS SELECT id, object_id FROM events WHERE object_id IS NOT NULL;
+
+DROP TABLE objects;
+ALTER TABLE objects_new RENAME TO objects;
+
+INSERT INTO object_metadata SELECT * FROM object_metadata_backup;
+INSERT INTO object_tags SELECT * FROM object_tags_backup;
+INSERT INTO object_parts SELECT * FROM object_parts_backup;
+UPDATE events
+SET object_id = (SELECT b.object_id FROM event_objects_backup b WHERE b.id = events.id)
+WHERE id IN (SELECT id FROM event_objects_backup);
+
+DROP TABLE object_metadata_backup;
+DROP TABLE object_tags_backup;
+DROP TABLE object_parts_backup;
+DROP TABLE event_objects_backup;
+
+CREATE INDEX IF NOT EXISTS idx_objects_bucket_name ON objects(bucket_name);
+CREATE INDEX IF NOT EXISTS idx_objects_key ON objects(key);
+CREATE INDEX IF NOT EXISTS idx_objects_bucket_key ON objects(bucket_name, key);
+CREATE INDEX IF NOT EXISTS idx_objects_updated_at ON objects(updated_at);
+
+-- A key has at most one current version and one null version
+CREATE UNIQUE INDEX IF NOT EXISTS idx_objects_latest ON objects(bucket_name, key) WHERE is_latest = 1;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_objects_version_id ON objects(bucket_name, key, COALESCE(version_id, 'null'));
@@ -0,0 +1,17 @@
+-- Payloads are restored from the blobs table only. Run the server with the
+-- SQLite blob store first if payloads were moved to disk.
+DROP TRIGGER IF EXISTS release_part_blob_on_update;
+DROP TRIGGER IF EXISTS release_part_blob_on_delete;
+DROP TRIGGER IF EXISTS release_object_blob_on_update;
+DROP TRIGGER IF EXISTS release_object_blob_on_delete;
+
+ALTER TABLE multipart_upload_parts ADD COLUMN data BLOB;
+UPDATE multipart_upload_parts SET data = (SELECT b.data FROM blobs b WHERE b.id = multipart_upload_parts.blob_id);
+ALTER TABLE multipart_upload_parts DROP COLUMN blob_id;
+
+ALTER TABLE objects ADD COLUMN data BLOB;
+UPDATE objects SET data = (SELECT b.data FROM blobs b WHERE b.id = objects.blob_id);
+ALTER TABLE objects DROP COLUMN blob_id;
+
+DROP TABLE IF EXISTS released_blobs;
+DROP TABLE IF EXISTS blobs;
@@ -0,0 +1,57 @@
+-- Object and part payloads move out of their rows into a blob store.
+-- Rows reference their payload by blob_id. The SQLite blob store keeps
+-- payloads in the blobs table; the filesystem blob store moves them to disk
+-- on startup.
+CREATE TABLE blobs (
+    id TEXT PRIMARY KEY NOT NULL,
+    data BLOB NOT NULL
+);
+
+-- Blobs no longer referenced by any row, removed from the blob store after
+-- the transaction that released them commits
+CREATE TABLE released_blobs (
+    blob_id TEXT PRIMARY KEY NOT NULL,
+    released_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE objects ADD COLUMN blob_id TEXT;
+UPDATE objects SET blob_id = lower(hex(randomblob(16))) WHERE is_delete_marker = 0;
+INSERT INTO blobs (id, data)
+SELECT blob_id, COALESCE(data, x'') FROM objects WHERE blob_id IS NOT NULL;
+ALTER TABLE objects DROP COLUMN data;
+
+ALTER TABLE multipart_upload_parts ADD COLUMN blob_id TEXT;
+UPDATE multipart_upload_parts SET blob_id = lower(hex(randomblob(16)));
+INSERT INTO blobs (id, data)
+SELECT blob_id, COALESCE(data, x'') FROM multipart_upload_parts;
+ALTER TABLE multipart_upload_parts DROP COLUMN data;
+
+-- Release blobs when their row is deleted, including through cascades, or
+-- when the row is pointed at a new blob
+CREATE TRIGGER release_object_blob_on_delete
+AFTER DELETE ON objects
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
+CREATE TRIGGER release_object_blob_on_update
+AFTER UPDATE OF blob_id ON objects
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL AND OLD.blob_id IS NOT NEW.blob_id
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
+CREATE TRIGGER release_part_blob_on_delete
+AFTER DELETE ON multipart_upload_parts
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
+CREATE TRIGGER release_part_blob_on_update
+AFTER UPDATE OF blob_id ON multipart_upload_parts
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL AND OLD.blob_id IS NOT NEW.blob_id
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
@@ -0,0 +1,8 @@
+ALTER TABLE blobs ADD COLUMN data BLOB NOT NULL DEFAULT x'';
+UPDATE blobs SET data = COALESCE((
+    SELECT CAST(group_concat(c.data, '') AS BLOB)
+    FROM (SELECT data FROM blob_chunks WHERE blob_id = blobs.id ORDER BY position) c
+), x'');
+ALTER TABLE blobs DROP COLUMN size;
+
+DROP TABLE IF EXISTS blob_chunks;
@@ -0,0 +1,15 @@
+-- The SQLite blob store writes and reads payloads in chunks so neither side
+-- needs a whole payload in memory. blobs keeps one row per complete blob.
+CREATE TABLE blob_chunks (
+    blob_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    data BLOB NOT NULL,
+    PRIMARY KEY (blob_id, position)
+);
+
+INSERT INTO blob_chunks (blob_id, position, data)
+SELECT id, 0, data FROM blobs WHERE length(data) > 0;
+
+ALTER TABLE blobs ADD COLUMN size INTEGER NOT NULL DEFAULT 0;
+UPDATE blobs SET size = length(data);
+ALTER TABLE blobs DROP COLUMN data;
@@ -0,0 +1,13 @@
+CREATE TRIGGER IF NOT EXISTS create_notification_jobs_on_event_insert
+AFTER INSERT ON events
+FOR EACH ROW
+BEGIN
+    INSERT INTO notification_jobs (event_id, notification_id)
+    SELECT
+        NEW.id,
+        n.id
+    FROM notifications n
+    WHERE n.bucket_name = NEW.bucket_name
+      AND n.event_type = NEW.event_type
+      AND n.enabled = 1;
+END;
@@ -0,0 +1,3 @@
+-- Notification jobs are created by the server, which applies wildcard event
+-- types and prefix/suffix key filters that the trigger could not
+DROP TRIGGER IF EXISTS create_notification_jobs_on_event_insert;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_notification_jobs_next_attempt_at;
+
+UPDATE notification_jobs SET status = 'failed' WHERE status = 'dead_letter';
+
+ALTER TABLE notification_jobs DROP COLUMN next_attempt_at;
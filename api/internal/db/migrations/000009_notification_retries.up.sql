@@ -0,0 +1,8 @@
+-- Failed deliveries are retried at next_attempt_at until the maximum number of
+-- attempts is reached, after which the job is dead-lettered. Jobs that failed
+-- before retries existed can be requeued from the dead letter state.
+ALTER TABLE notification_jobs ADD COLUMN next_attempt_at DATETIME;
+
+UPDATE notification_jobs SET status = 'dead_letter' WHERE status = 'failed';
+
+CREATE INDEX IF NOT EXISTS idx_notification_jobs_next_attempt_at ON notification_jobs(status, next_attempt_at);
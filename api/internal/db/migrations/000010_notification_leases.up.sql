@@ -0,0 +1,3 @@
+-- A worker claims a job by setting claimed_until; other workers and processes
+-- skip it until the lease expires
+ALTER TABLE notification_jobs ADD COLUMN claimed_until DATETIME;
@@ -0,0 +1,3 @@
+DELETE FROM notifications WHERE managed_by = 'config';
+
+ALTER TABLE notifications DROP COLUMN managed_by;
@@ -0,0 +1,4 @@
+-- Rules declared in config.yaml are stored with managed_by = 'config'. They are
+-- synced at startup and kept when the notification configuration of their
+-- bucket is replaced through the API.
+ALTER TABLE notifications ADD COLUMN managed_by TEXT NOT NULL DEFAULT 'api';
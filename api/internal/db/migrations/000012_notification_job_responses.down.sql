@@ -0,0 +1,4 @@
+UPDATE notification_jobs SET status = 'dead_letter' WHERE status = 'cancelled';
+
+ALTER TABLE notification_jobs DROP COLUMN response_body;
+ALTER TABLE notification_jobs DROP COLUMN response_status;
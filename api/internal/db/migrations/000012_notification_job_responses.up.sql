@@ -0,0 +1,5 @@
+-- Jobs keep the status and the beginning of the body of the last response they
+-- got, shown by the admin API along with error_message. Jobs cancelled through
+-- the admin API get the status 'cancelled'.
+ALTER TABLE notification_jobs ADD COLUMN response_status INTEGER;
+ALTER TABLE notification_jobs ADD COLUMN response_body TEXT;
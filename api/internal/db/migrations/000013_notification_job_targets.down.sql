@@ -0,0 +1 @@
+ALTER TABLE notification_jobs DROP COLUMN delivered_targets;
@@ -0,0 +1,4 @@
+-- Jobs sent to several targets, like EventBridge events sent to the event bus
+-- and to the targets of matching rules, keep the targets they were delivered
+-- to as a JSON array, so that a retry skips them.
+ALTER TABLE notification_jobs ADD COLUMN delivered_targets TEXT;
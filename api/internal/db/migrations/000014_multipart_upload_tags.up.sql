@@ -0,0 +1,3 @@
+-- Tags given with x-amz-tagging at CreateMultipartUpload, as a JSON object,
+-- applied to the object when the upload completes
+ALTER TABLE multipart_uploads ADD COLUMN tags TEXT NOT NULL DEFAULT '{}';
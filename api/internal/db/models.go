@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 
 package db
 
@@ -9,10 +9,22 @@ import (
 	"time"
 )
 
+type Blob struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+type BlobChunk struct {
+	BlobID   string `json:"blob_id"`
+	Position int64  `json:"position"`
+	Data     []byte `json:"data"`
+}
+
 type Bucket struct {
-	Name      string    `json:"name"`
-	Region    string    `json:"region"`
-	CreatedAt time.Time `json:"created_at"`
+	Name             string         `json:"name"`
+	Region           string         `json:"region"`
+	CreatedAt        time.Time      `json:"created_at"`
+	VersioningStatus sql.NullString `json:"versioning_status"`
 }
 
 type BucketPolicy struct {
@@ -30,11 +42,42 @@ type BucketTag struct {
 }
 
 type Event struct {
-	ID         int64     `json:"id"`
-	BucketName string    `json:"bucket_name"`
-	ObjectID   int64     `json:"object_id"`
-	EventType  string    `json:"event_type"`
-	EventTime  time.Time `json:"event_time"`
+	ID              int64          `json:"id"`
+	BucketName      string         `json:"bucket_name"`
+	ObjectID        sql.NullInt64  `json:"object_id"`
+	EventType       string         `json:"event_type"`
+	EventTime       time.Time      `json:"event_time"`
+	ObjectKey       string         `json:"object_key"`
+	ObjectSize      int64          `json:"object_size"`
+	ObjectETag      string         `json:"object_etag"`
+	ObjectVersionID sql.NullString `json:"object_version_id"`
+}
+
+type MultipartUpload struct {
+	UploadID           string         `json:"upload_id"`
+	BucketName         string         `json:"bucket_name"`
+	Key                string         `json:"key"`
+	ContentType        string         `json:"content_type"`
+	ContentEncoding    sql.NullString `json:"content_encoding"`
+	ContentDisposition sql.NullString `json:"content_disposition"`
+	CacheControl       sql.NullString `json:"cache_control"`
+	StorageClass       string         `json:"storage_class"`
+	Metadata           string         `json:"metadata"`
+	CreatedAt          time.Time      `json:"created_at"`
+	ChecksumAlgorithm  sql.NullString `json:"checksum_algorithm"`
+	ChecksumType       sql.NullString `json:"checksum_type"`
+	Tags               string         `json:"tags"`
+}
+
+type MultipartUploadPart struct {
+	ID            int64          `json:"id"`
+	UploadID      string         `json:"upload_id"`
+	PartNumber    int64          `json:"part_number"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	CreatedAt     time.Time      `json:"created_at"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+	BlobID        sql.NullString `json:"blob_id"`
 }
 
 type Notification struct {
@@ -48,24 +91,29 @@ type Notification struct {
 	Enabled         bool           `json:"enabled"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+	ManagedBy       string         `json:"managed_by"`
 }
 
 type NotificationJob struct {
-	ID             int64          `json:"id"`
-	EventID        int64          `json:"event_id"`
-	NotificationID int64          `json:"notification_id"`
-	Status         string         `json:"status"`
-	Attempts       int64          `json:"attempts"`
-	ErrorMessage   sql.NullString `json:"error_message"`
-	CreatedAt      time.Time      `json:"created_at"`
-	UpdatedAt      time.Time      `json:"updated_at"`
+	ID               int64          `json:"id"`
+	EventID          int64          `json:"event_id"`
+	NotificationID   int64          `json:"notification_id"`
+	Status           string         `json:"status"`
+	Attempts         int64          `json:"attempts"`
+	ErrorMessage     sql.NullString `json:"error_message"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	NextAttemptAt    sql.NullTime   `json:"next_attempt_at"`
+	ClaimedUntil     sql.NullTime   `json:"claimed_until"`
+	ResponseStatus   sql.NullInt64  `json:"response_status"`
+	ResponseBody     sql.NullString `json:"response_body"`
+	DeliveredTargets sql.NullString `json:"delivered_targets"`
 }
 
 type Object struct {
 	ID                   int64          `json:"id"`
 	BucketName           string         `json:"bucket_name"`
 	Key                  string         `json:"key"`
-	Data                 []byte         `json:"data"`
 	Size                 int64          `json:"size"`
 	ETag                 string         `json:"etag"`
 	ContentType          string         `json:"content_type"`
@@ -78,6 +126,12 @@ type Object struct {
 	VersionID            sql.NullString `json:"version_id"`
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
+	ChecksumAlgorithm    sql.NullString `json:"checksum_algorithm"`
+	ChecksumValue        sql.NullString `json:"checksum_value"`
+	ChecksumType         sql.NullString `json:"checksum_type"`
+	IsLatest             bool           `json:"is_latest"`
+	IsDeleteMarker       bool           `json:"is_delete_marker"`
+	BlobID               sql.NullString `json:"blob_id"`
 }
 
 type ObjectMetadatum struct {
@@ -87,9 +141,23 @@ type ObjectMetadatum struct {
 	Value    string `json:"value"`
 }
 
+type ObjectPart struct {
+	ID            int64          `json:"id"`
+	ObjectID      int64          `json:"object_id"`
+	PartNumber    int64          `json:"part_number"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+}
+
 type ObjectTag struct {
 	ID       int64  `json:"id"`
 	ObjectID int64  `json:"object_id"`
 	Key      string `json:"key"`
 	Value    string `json:"value"`
 }
+
+type ReleasedBlob struct {
+	BlobID     string    `json:"blob_id"`
+	ReleasedAt time.Time `json:"released_at"`
+}
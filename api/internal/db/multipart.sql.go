@@ -0,0 +1,378 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: multipart.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const CreateMultipartUpload = `-- name: CreateMultipartUpload :exec
+INSERT INTO multipart_uploads (
+    upload_id,
+    bucket_name,
+    key,
+    content_type,
+    content_encoding,
+    content_disposition,
+    cache_control,
+    storage_class,
+    metadata,
+    checksum_algorithm,
+    checksum_type,
+    tags
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type CreateMultipartUploadParams struct {
+	UploadID           string         `json:"upload_id"`
+	BucketName         string         `json:"bucket_name"`
+	Key                string         `json:"key"`
+	ContentType        string         `json:"content_type"`
+	ContentEncoding    sql.NullString `json:"content_encoding"`
+	ContentDisposition sql.NullString `json:"content_disposition"`
+	CacheControl       sql.NullString `json:"cache_control"`
+	StorageClass       string         `json:"storage_class"`
+	Metadata           string         `json:"metadata"`
+	ChecksumAlgorithm  sql.NullString `json:"checksum_algorithm"`
+	ChecksumType       sql.NullString `json:"checksum_type"`
+	Tags               string         `json:"tags"`
+}
+
+func (q *Queries) CreateMultipartUpload(ctx context.Context, arg CreateMultipartUploadParams) error {
+	_, err := q.exec(ctx, q.createMultipartUploadStmt, CreateMultipartUpload,
+		arg.UploadID,
+		arg.BucketName,
+		arg.Key,
+		arg.ContentType,
+		arg.ContentEncoding,
+		arg.ContentDisposition,
+		arg.CacheControl,
+		arg.StorageClass,
+		arg.Metadata,
+		arg.ChecksumAlgorithm,
+		arg.ChecksumType,
+		arg.Tags,
+	)
+	return err
+}
+
+const CreateObjectPart = `-- name: CreateObjectPart :exec
+INSERT INTO object_parts (object_id, part_number, size, etag, checksum_value)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateObjectPartParams struct {
+	ObjectID      int64          `json:"object_id"`
+	PartNumber    int64          `json:"part_number"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+}
+
+// Object part layout queries
+func (q *Queries) CreateObjectPart(ctx context.Context, arg CreateObjectPartParams) error {
+	_, err := q.exec(ctx, q.createObjectPartStmt, CreateObjectPart,
+		arg.ObjectID,
+		arg.PartNumber,
+		arg.Size,
+		arg.ETag,
+		arg.ChecksumValue,
+	)
+	return err
+}
+
+const DeleteMultipartUpload = `-- name: DeleteMultipartUpload :exec
+DELETE FROM multipart_uploads
+WHERE upload_id = ?
+`
+
+func (q *Queries) DeleteMultipartUpload(ctx context.Context, uploadID string) error {
+	_, err := q.exec(ctx, q.deleteMultipartUploadStmt, DeleteMultipartUpload, uploadID)
+	return err
+}
+
+const DeleteObjectParts = `-- name: DeleteObjectParts :exec
+DELETE FROM object_parts
+WHERE object_id = ?
+`
+
+func (q *Queries) DeleteObjectParts(ctx context.Context, objectID int64) error {
+	_, err := q.exec(ctx, q.deleteObjectPartsStmt, DeleteObjectParts, objectID)
+	return err
+}
+
+const GetMultipartUpload = `-- name: GetMultipartUpload :one
+SELECT upload_id, bucket_name, key, content_type, content_encoding,
+       content_disposition, cache_control, storage_class, metadata, created_at,
+       checksum_algorithm, checksum_type, tags
+FROM multipart_uploads
+WHERE upload_id = ? AND bucket_name = ? AND key = ?
+`
+
+type GetMultipartUploadParams struct {
+	UploadID   string `json:"upload_id"`
+	BucketName string `json:"bucket_name"`
+	Key        string `json:"key"`
+}
+
+func (q *Queries) GetMultipartUpload(ctx context.Context, arg GetMultipartUploadParams) (MultipartUpload, error) {
+	row := q.queryRow(ctx, q.getMultipartUploadStmt, GetMultipartUpload, arg.UploadID, arg.BucketName, arg.Key)
+	var i MultipartUpload
+	err := row.Scan(
+		&i.UploadID,
+		&i.BucketName,
+		&i.Key,
+		&i.ContentType,
+		&i.ContentEncoding,
+		&i.ContentDisposition,
+		&i.CacheControl,
+		&i.StorageClass,
+		&i.Metadata,
+		&i.CreatedAt,
+		&i.ChecksumAlgorithm,
+		&i.ChecksumType,
+		&i.Tags,
+	)
+	return i, err
+}
+
+const GetMultipartUploadPartsWithBlobs = `-- name: GetMultipartUploadPartsWithBlobs :many
+SELECT part_number, blob_id, size, etag, checksum_value
+FROM multipart_upload_parts
+WHERE upload_id = ?
+ORDER BY part_number ASC
+`
+
+type GetMultipartUploadPartsWithBlobsRow struct {
+	PartNumber    int64          `json:"part_number"`
+	BlobID        sql.NullString `json:"blob_id"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+}
+
+func (q *Queries) GetMultipartUploadPartsWithBlobs(ctx context.Context, uploadID string) ([]GetMultipartUploadPartsWithBlobsRow, error) {
+	rows, err := q.query(ctx, q.getMultipartUploadPartsWithBlobsStmt, GetMultipartUploadPartsWithBlobs, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetMultipartUploadPartsWithBlobsRow{}
+	for rows.Next() {
+		var i GetMultipartUploadPartsWithBlobsRow
+		if err := rows.Scan(
+			&i.PartNumber,
+			&i.BlobID,
+			&i.Size,
+			&i.ETag,
+			&i.ChecksumValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListMultipartUploadParts = `-- name: ListMultipartUploadParts :many
+SELECT part_number, size, etag, checksum_value, created_at
+FROM multipart_upload_parts
+WHERE upload_id = ?1
+  AND part_number > ?2
+ORDER BY part_number ASC
+LIMIT ?3
+`
+
+type ListMultipartUploadPartsParams struct {
+	UploadID         string `json:"upload_id"`
+	PartNumberMarker int64  `json:"part_number_marker"`
+	Limit            int64  `json:"limit"`
+}
+
+type ListMultipartUploadPartsRow struct {
+	PartNumber    int64          `json:"part_number"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+func (q *Queries) ListMultipartUploadParts(ctx context.Context, arg ListMultipartUploadPartsParams) ([]ListMultipartUploadPartsRow, error) {
+	rows, err := q.query(ctx, q.listMultipartUploadPartsStmt, ListMultipartUploadParts, arg.UploadID, arg.PartNumberMarker, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListMultipartUploadPartsRow{}
+	for rows.Next() {
+		var i ListMultipartUploadPartsRow
+		if err := rows.Scan(
+			&i.PartNumber,
+			&i.Size,
+			&i.ETag,
+			&i.ChecksumValue,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListMultipartUploads = `-- name: ListMultipartUploads :many
+SELECT upload_id, bucket_name, key, content_type, content_encoding,
+       content_disposition, cache_control, storage_class, metadata, created_at,
+       checksum_algorithm, checksum_type, tags
+FROM multipart_uploads
+WHERE bucket_name = ?1
+  AND (CAST(?2 AS TEXT) = '' OR key LIKE ?2 || '%')
+  AND (CAST(?3 AS TEXT) = ''
+       OR key > ?3
+       OR (key = ?3 AND CAST(?4 AS TEXT) != '' AND upload_id > ?4))
+ORDER BY key ASC, upload_id ASC
+LIMIT ?5
+`
+
+type ListMultipartUploadsParams struct {
+	BucketName     string `json:"bucket_name"`
+	Prefix         string `json:"prefix"`
+	KeyMarker      string `json:"key_marker"`
+	UploadIDMarker string `json:"upload_id_marker"`
+	Limit          int64  `json:"limit"`
+}
+
+func (q *Queries) ListMultipartUploads(ctx context.Context, arg ListMultipartUploadsParams) ([]MultipartUpload, error) {
+	rows, err := q.query(ctx, q.listMultipartUploadsStmt, ListMultipartUploads,
+		arg.BucketName,
+		arg.Prefix,
+		arg.KeyMarker,
+		arg.UploadIDMarker,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []MultipartUpload{}
+	for rows.Next() {
+		var i MultipartUpload
+		if err := rows.Scan(
+			&i.UploadID,
+			&i.BucketName,
+			&i.Key,
+			&i.ContentType,
+			&i.ContentEncoding,
+			&i.ContentDisposition,
+			&i.CacheControl,
+			&i.StorageClass,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.ChecksumAlgorithm,
+			&i.ChecksumType,
+			&i.Tags,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListObjectParts = `-- name: ListObjectParts :many
+SELECT part_number, size, etag, checksum_value
+FROM object_parts
+WHERE object_id = ?
+ORDER BY part_number ASC
+`
+
+type ListObjectPartsRow struct {
+	PartNumber    int64          `json:"part_number"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+}
+
+func (q *Queries) ListObjectParts(ctx context.Context, objectID int64) ([]ListObjectPartsRow, error) {
+	rows, err := q.query(ctx, q.listObjectPartsStmt, ListObjectParts, objectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListObjectPartsRow{}
+	for rows.Next() {
+		var i ListObjectPartsRow
+		if err := rows.Scan(
+			&i.PartNumber,
+			&i.Size,
+			&i.ETag,
+			&i.ChecksumValue,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const PutMultipartUploadPart = `-- name: PutMultipartUploadPart :exec
+INSERT INTO multipart_upload_parts (upload_id, part_number, blob_id, size, etag, checksum_value)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(upload_id, part_number) DO UPDATE SET
+    blob_id = excluded.blob_id,
+    size = excluded.size,
+    etag = excluded.etag,
+    checksum_value = excluded.checksum_value,
+    created_at = CURRENT_TIMESTAMP
+`
+
+type PutMultipartUploadPartParams struct {
+	UploadID      string         `json:"upload_id"`
+	PartNumber    int64          `json:"part_number"`
+	BlobID        sql.NullString `json:"blob_id"`
+	Size          int64          `json:"size"`
+	ETag          string         `json:"etag"`
+	ChecksumValue sql.NullString `json:"checksum_value"`
+}
+
+func (q *Queries) PutMultipartUploadPart(ctx context.Context, arg PutMultipartUploadPartParams) error {
+	_, err := q.exec(ctx, q.putMultipartUploadPartStmt, PutMultipartUploadPart,
+		arg.UploadID,
+		arg.PartNumber,
+		arg.BlobID,
+		arg.Size,
+		arg.ETag,
+		arg.ChecksumValue,
+	)
+	return err
+}
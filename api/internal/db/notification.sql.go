@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 // source: notification.sql
 
 package db
@@ -10,10 +10,144 @@ import (
 	"database/sql"
 )
 
+const AddNotificationJobDeliveredTarget = `-- name: AddNotificationJobDeliveredTarget :exec
+UPDATE notification_jobs
+SET delivered_targets = json_insert(COALESCE(delivered_targets, '[]'), '$[#]', CAST(?1 AS TEXT))
+WHERE id = ?2 AND status = 'pending'
+`
+
+type AddNotificationJobDeliveredTargetParams struct {
+	Target string `json:"target"`
+	ID     int64  `json:"id"`
+}
+
+// Records that a pending job was delivered to one of its targets
+func (q *Queries) AddNotificationJobDeliveredTarget(ctx context.Context, arg AddNotificationJobDeliveredTargetParams) error {
+	_, err := q.exec(ctx, q.addNotificationJobDeliveredTargetStmt, AddNotificationJobDeliveredTarget, arg.Target, arg.ID)
+	return err
+}
+
+const CancelNotificationJob = `-- name: CancelNotificationJob :execrows
+UPDATE notification_jobs
+SET status = 'cancelled',
+    claimed_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending'
+`
+
+func (q *Queries) CancelNotificationJob(ctx context.Context, id int64) (int64, error) {
+	result, err := q.exec(ctx, q.cancelNotificationJobStmt, CancelNotificationJob, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const ClaimNotificationJobs = `-- name: ClaimNotificationJobs :many
+UPDATE notification_jobs
+SET claimed_until = ?1
+WHERE id IN (
+    SELECT j.id
+    FROM notification_jobs j
+    JOIN events e ON e.id = j.event_id
+    WHERE j.status = 'pending'
+      AND (j.next_attempt_at IS NULL OR j.next_attempt_at <= ?2)
+      AND (j.claimed_until IS NULL OR j.claimed_until <= ?2)
+      AND NOT EXISTS (
+          SELECT 1
+          FROM notification_jobs earlier
+          JOIN events earlier_event ON earlier_event.id = earlier.event_id
+          WHERE earlier.notification_id = j.notification_id
+            AND earlier.status = 'pending'
+            AND earlier.id < j.id
+            AND earlier_event.bucket_name = e.bucket_name
+            AND earlier_event.object_key = e.object_key
+      )
+    ORDER BY j.id
+    LIMIT ?3
+)
+RETURNING id
+`
+
+type ClaimNotificationJobsParams struct {
+	ClaimedUntil sql.NullTime `json:"claimed_until"`
+	Now          sql.NullTime `json:"now"`
+	MaxJobs      int64        `json:"max_jobs"`
+}
+
+// Claims due pending jobs until claimed_until. A job waits while an earlier
+// job for the same object and notification is pending, so that each
+// destination receives the events of an object in order.
+func (q *Queries) ClaimNotificationJobs(ctx context.Context, arg ClaimNotificationJobsParams) ([]int64, error) {
+	rows, err := q.query(ctx, q.claimNotificationJobsStmt, ClaimNotificationJobs, arg.ClaimedUntil, arg.Now, arg.MaxJobs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const CreateConfigNotification = `-- name: CreateConfigNotification :one
+INSERT INTO notifications (bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, managed_by)
+VALUES (?, ?, ?, ?, ?, ?, ?, 'config')
+RETURNING id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
+`
+
+type CreateConfigNotificationParams struct {
+	BucketName      string         `json:"bucket_name"`
+	EventType       string         `json:"event_type"`
+	DestinationType string         `json:"destination_type"`
+	DestinationArn  string         `json:"destination_arn"`
+	FilterPrefix    sql.NullString `json:"filter_prefix"`
+	FilterSuffix    sql.NullString `json:"filter_suffix"`
+	Enabled         bool           `json:"enabled"`
+}
+
+func (q *Queries) CreateConfigNotification(ctx context.Context, arg CreateConfigNotificationParams) (Notification, error) {
+	row := q.queryRow(ctx, q.createConfigNotificationStmt, CreateConfigNotification,
+		arg.BucketName,
+		arg.EventType,
+		arg.DestinationType,
+		arg.DestinationArn,
+		arg.FilterPrefix,
+		arg.FilterSuffix,
+		arg.Enabled,
+	)
+	var i Notification
+	err := row.Scan(
+		&i.ID,
+		&i.BucketName,
+		&i.EventType,
+		&i.DestinationType,
+		&i.DestinationArn,
+		&i.FilterPrefix,
+		&i.FilterSuffix,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ManagedBy,
+	)
+	return i, err
+}
+
 const CreateNotification = `-- name: CreateNotification :one
 INSERT INTO notifications (bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled)
 VALUES (?, ?, ?, ?, ?, ?, ?)
-RETURNING id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+RETURNING id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 `
 
 type CreateNotificationParams struct {
@@ -48,10 +182,26 @@ func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotification
 		&i.Enabled,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ManagedBy,
 	)
 	return i, err
 }
 
+const CreateNotificationJob = `-- name: CreateNotificationJob :exec
+INSERT INTO notification_jobs (event_id, notification_id)
+VALUES (?, ?)
+`
+
+type CreateNotificationJobParams struct {
+	EventID        int64 `json:"event_id"`
+	NotificationID int64 `json:"notification_id"`
+}
+
+func (q *Queries) CreateNotificationJob(ctx context.Context, arg CreateNotificationJobParams) error {
+	_, err := q.exec(ctx, q.createNotificationJobStmt, CreateNotificationJob, arg.EventID, arg.NotificationID)
+	return err
+}
+
 const DeleteNotification = `-- name: DeleteNotification :exec
 DELETE FROM notifications
 WHERE id = ?
@@ -62,8 +212,21 @@ func (q *Queries) DeleteNotification(ctx context.Context, id int64) error {
 	return err
 }
 
+const DeleteNotificationJob = `-- name: DeleteNotificationJob :execrows
+DELETE FROM notification_jobs
+WHERE id = ?
+`
+
+func (q *Queries) DeleteNotificationJob(ctx context.Context, id int64) (int64, error) {
+	result, err := q.exec(ctx, q.deleteNotificationJobStmt, DeleteNotificationJob, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const GetNotification = `-- name: GetNotification :one
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 FROM notifications
 WHERE id = ?
 `
@@ -82,12 +245,71 @@ func (q *Queries) GetNotification(ctx context.Context, id int64) (Notification,
 		&i.Enabled,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ManagedBy,
+	)
+	return i, err
+}
+
+const GetNotificationJob = `-- name: GetNotificationJob :one
+SELECT
+    notification_jobs.id, notification_jobs.event_id, notification_jobs.notification_id, notification_jobs.status, notification_jobs.attempts, notification_jobs.error_message, notification_jobs.created_at, notification_jobs.updated_at, notification_jobs.next_attempt_at, notification_jobs.claimed_until, notification_jobs.response_status, notification_jobs.response_body, notification_jobs.delivered_targets,
+    events.id, events.bucket_name, events.object_id, events.event_type, events.event_time, events.object_key, events.object_size, events.object_etag, events.object_version_id,
+    notifications.id, notifications.bucket_name, notifications.event_type, notifications.destination_type, notifications.destination_arn, notifications.filter_prefix, notifications.filter_suffix, notifications.enabled, notifications.created_at, notifications.updated_at, notifications.managed_by
+FROM notification_jobs
+JOIN events ON notification_jobs.event_id = events.id
+JOIN notifications ON notification_jobs.notification_id = notifications.id
+WHERE notification_jobs.id = ?
+`
+
+type GetNotificationJobRow struct {
+	NotificationJob NotificationJob `json:"notification_job"`
+	Event           Event           `json:"event"`
+	Notification    Notification    `json:"notification"`
+}
+
+func (q *Queries) GetNotificationJob(ctx context.Context, id int64) (GetNotificationJobRow, error) {
+	row := q.queryRow(ctx, q.getNotificationJobStmt, GetNotificationJob, id)
+	var i GetNotificationJobRow
+	err := row.Scan(
+		&i.NotificationJob.ID,
+		&i.NotificationJob.EventID,
+		&i.NotificationJob.NotificationID,
+		&i.NotificationJob.Status,
+		&i.NotificationJob.Attempts,
+		&i.NotificationJob.ErrorMessage,
+		&i.NotificationJob.CreatedAt,
+		&i.NotificationJob.UpdatedAt,
+		&i.NotificationJob.NextAttemptAt,
+		&i.NotificationJob.ClaimedUntil,
+		&i.NotificationJob.ResponseStatus,
+		&i.NotificationJob.ResponseBody,
+		&i.NotificationJob.DeliveredTargets,
+		&i.Event.ID,
+		&i.Event.BucketName,
+		&i.Event.ObjectID,
+		&i.Event.EventType,
+		&i.Event.EventTime,
+		&i.Event.ObjectKey,
+		&i.Event.ObjectSize,
+		&i.Event.ObjectETag,
+		&i.Event.ObjectVersionID,
+		&i.Notification.ID,
+		&i.Notification.BucketName,
+		&i.Notification.EventType,
+		&i.Notification.DestinationType,
+		&i.Notification.DestinationArn,
+		&i.Notification.FilterPrefix,
+		&i.Notification.FilterSuffix,
+		&i.Notification.Enabled,
+		&i.Notification.CreatedAt,
+		&i.Notification.UpdatedAt,
+		&i.Notification.ManagedBy,
 	)
 	return i, err
 }
 
 const ListEnabledNotificationsByBucket = `-- name: ListEnabledNotificationsByBucket :many
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 FROM notifications
 WHERE bucket_name = ? AND enabled = 1
 ORDER BY created_at DESC
@@ -113,6 +335,7 @@ func (q *Queries) ListEnabledNotificationsByBucket(ctx context.Context, bucketNa
 			&i.Enabled,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ManagedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -127,33 +350,87 @@ func (q *Queries) ListEnabledNotificationsByBucket(ctx context.Context, bucketNa
 	return items, nil
 }
 
-const ListNotificationsByBucket = `-- name: ListNotificationsByBucket :many
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
-FROM notifications
-WHERE bucket_name = ?
-ORDER BY created_at DESC
+const ListNotificationJobs = `-- name: ListNotificationJobs :many
+SELECT
+    notification_jobs.id, notification_jobs.event_id, notification_jobs.notification_id, notification_jobs.status, notification_jobs.attempts, notification_jobs.error_message, notification_jobs.created_at, notification_jobs.updated_at, notification_jobs.next_attempt_at, notification_jobs.claimed_until, notification_jobs.response_status, notification_jobs.response_body, notification_jobs.delivered_targets,
+    events.id, events.bucket_name, events.object_id, events.event_type, events.event_time, events.object_key, events.object_size, events.object_etag, events.object_version_id,
+    notifications.id, notifications.bucket_name, notifications.event_type, notifications.destination_type, notifications.destination_arn, notifications.filter_prefix, notifications.filter_suffix, notifications.enabled, notifications.created_at, notifications.updated_at, notifications.managed_by
+FROM notification_jobs
+JOIN events ON notification_jobs.event_id = events.id
+JOIN notifications ON notification_jobs.notification_id = notifications.id
+WHERE (CAST(?1 AS TEXT) IS NULL OR events.bucket_name = CAST(?1 AS TEXT))
+  AND (CAST(?2 AS TEXT) IS NULL OR notification_jobs.status = CAST(?2 AS TEXT))
+  AND (CAST(?3 AS TEXT) IS NULL OR notification_jobs.created_at >= CAST(?3 AS TEXT))
+  AND (CAST(?4 AS TEXT) IS NULL OR notification_jobs.created_at < CAST(?4 AS TEXT))
+ORDER BY notification_jobs.id DESC
+LIMIT ?6 OFFSET ?5
 `
 
-func (q *Queries) ListNotificationsByBucket(ctx context.Context, bucketName string) ([]Notification, error) {
-	rows, err := q.query(ctx, q.listNotificationsByBucketStmt, ListNotificationsByBucket, bucketName)
+type ListNotificationJobsParams struct {
+	BucketName sql.NullString `json:"bucket_name"`
+	Status     sql.NullString `json:"status"`
+	Since      sql.NullString `json:"since"`
+	Until      sql.NullString `json:"until"`
+	SkipJobs   int64          `json:"skip_jobs"`
+	MaxJobs    int64          `json:"max_jobs"`
+}
+
+type ListNotificationJobsRow struct {
+	NotificationJob NotificationJob `json:"notification_job"`
+	Event           Event           `json:"event"`
+	Notification    Notification    `json:"notification"`
+}
+
+func (q *Queries) ListNotificationJobs(ctx context.Context, arg ListNotificationJobsParams) ([]ListNotificationJobsRow, error) {
+	rows, err := q.query(ctx, q.listNotificationJobsStmt, ListNotificationJobs,
+		arg.BucketName,
+		arg.Status,
+		arg.Since,
+		arg.Until,
+		arg.SkipJobs,
+		arg.MaxJobs,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Notification{}
+	items := []ListNotificationJobsRow{}
 	for rows.Next() {
-		var i Notification
+		var i ListNotificationJobsRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.BucketName,
-			&i.EventType,
-			&i.DestinationType,
-			&i.DestinationArn,
-			&i.FilterPrefix,
-			&i.FilterSuffix,
-			&i.Enabled,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.NotificationJob.ID,
+			&i.NotificationJob.EventID,
+			&i.NotificationJob.NotificationID,
+			&i.NotificationJob.Status,
+			&i.NotificationJob.Attempts,
+			&i.NotificationJob.ErrorMessage,
+			&i.NotificationJob.CreatedAt,
+			&i.NotificationJob.UpdatedAt,
+			&i.NotificationJob.NextAttemptAt,
+			&i.NotificationJob.ClaimedUntil,
+			&i.NotificationJob.ResponseStatus,
+			&i.NotificationJob.ResponseBody,
+			&i.NotificationJob.DeliveredTargets,
+			&i.Event.ID,
+			&i.Event.BucketName,
+			&i.Event.ObjectID,
+			&i.Event.EventType,
+			&i.Event.EventTime,
+			&i.Event.ObjectKey,
+			&i.Event.ObjectSize,
+			&i.Event.ObjectETag,
+			&i.Event.ObjectVersionID,
+			&i.Notification.ID,
+			&i.Notification.BucketName,
+			&i.Notification.EventType,
+			&i.Notification.DestinationType,
+			&i.Notification.DestinationArn,
+			&i.Notification.FilterPrefix,
+			&i.Notification.FilterSuffix,
+			&i.Notification.Enabled,
+			&i.Notification.CreatedAt,
+			&i.Notification.UpdatedAt,
+			&i.Notification.ManagedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -168,37 +445,55 @@ func (q *Queries) ListNotificationsByBucket(ctx context.Context, bucketName stri
 	return items, nil
 }
 
-const ListNotificationsByEventType = `-- name: ListNotificationsByEventType :many
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
-FROM notifications
-WHERE bucket_name = ? AND event_type = ? AND enabled = 1
+const ListNotificationJobsByEvent = `-- name: ListNotificationJobsByEvent :many
+SELECT
+    notification_jobs.id, notification_jobs.event_id, notification_jobs.notification_id, notification_jobs.status, notification_jobs.attempts, notification_jobs.error_message, notification_jobs.created_at, notification_jobs.updated_at, notification_jobs.next_attempt_at, notification_jobs.claimed_until, notification_jobs.response_status, notification_jobs.response_body, notification_jobs.delivered_targets,
+    notifications.id, notifications.bucket_name, notifications.event_type, notifications.destination_type, notifications.destination_arn, notifications.filter_prefix, notifications.filter_suffix, notifications.enabled, notifications.created_at, notifications.updated_at, notifications.managed_by
+FROM notification_jobs
+JOIN notifications ON notification_jobs.notification_id = notifications.id
+WHERE notification_jobs.event_id = ?
+ORDER BY notification_jobs.id
 `
 
-type ListNotificationsByEventTypeParams struct {
-	BucketName string `json:"bucket_name"`
-	EventType  string `json:"event_type"`
+type ListNotificationJobsByEventRow struct {
+	NotificationJob NotificationJob `json:"notification_job"`
+	Notification    Notification    `json:"notification"`
 }
 
-func (q *Queries) ListNotificationsByEventType(ctx context.Context, arg ListNotificationsByEventTypeParams) ([]Notification, error) {
-	rows, err := q.query(ctx, q.listNotificationsByEventTypeStmt, ListNotificationsByEventType, arg.BucketName, arg.EventType)
+func (q *Queries) ListNotificationJobsByEvent(ctx context.Context, eventID int64) ([]ListNotificationJobsByEventRow, error) {
+	rows, err := q.query(ctx, q.listNotificationJobsByEventStmt, ListNotificationJobsByEvent, eventID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Notification{}
+	items := []ListNotificationJobsByEventRow{}
 	for rows.Next() {
-		var i Notification
+		var i ListNotificationJobsByEventRow
 		if err := rows.Scan(
-			&i.ID,
-			&i.BucketName,
-			&i.EventType,
-			&i.DestinationType,
-			&i.DestinationArn,
-			&i.FilterPrefix,
-			&i.FilterSuffix,
-			&i.Enabled,
-			&i.CreatedAt,
-			&i.UpdatedAt,
+			&i.NotificationJob.ID,
+			&i.NotificationJob.EventID,
+			&i.NotificationJob.NotificationID,
+			&i.NotificationJob.Status,
+			&i.NotificationJob.Attempts,
+			&i.NotificationJob.ErrorMessage,
+			&i.NotificationJob.CreatedAt,
+			&i.NotificationJob.UpdatedAt,
+			&i.NotificationJob.NextAttemptAt,
+			&i.NotificationJob.ClaimedUntil,
+			&i.NotificationJob.ResponseStatus,
+			&i.NotificationJob.ResponseBody,
+			&i.NotificationJob.DeliveredTargets,
+			&i.Notification.ID,
+			&i.Notification.BucketName,
+			&i.Notification.EventType,
+			&i.Notification.DestinationType,
+			&i.Notification.DestinationArn,
+			&i.Notification.FilterPrefix,
+			&i.Notification.FilterSuffix,
+			&i.Notification.Enabled,
+			&i.Notification.CreatedAt,
+			&i.Notification.UpdatedAt,
+			&i.Notification.ManagedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -213,33 +508,41 @@ func (q *Queries) ListNotificationsByEventType(ctx context.Context, arg ListNoti
 	return items, nil
 }
 
-const ListPendingNotificationJobs = `-- name: ListPendingNotificationJobs :many
+const ListNotificationJobsUpdatedSince = `-- name: ListNotificationJobsUpdatedSince :many
 SELECT
-    notification_jobs.id, notification_jobs.event_id, notification_jobs.notification_id, notification_jobs.status, notification_jobs.attempts, notification_jobs.error_message, notification_jobs.created_at, notification_jobs.updated_at,
-    events.id, events.bucket_name, events.object_id, events.event_type, events.event_time,
-    notifications.id, notifications.bucket_name, notifications.event_type, notifications.destination_type, notifications.destination_arn, notifications.filter_prefix, notifications.filter_suffix, notifications.enabled, notifications.created_at, notifications.updated_at
+    notification_jobs.id, notification_jobs.event_id, notification_jobs.notification_id, notification_jobs.status, notification_jobs.attempts, notification_jobs.error_message, notification_jobs.created_at, notification_jobs.updated_at, notification_jobs.next_attempt_at, notification_jobs.claimed_until, notification_jobs.response_status, notification_jobs.response_body, notification_jobs.delivered_targets,
+    events.id, events.bucket_name, events.object_id, events.event_type, events.event_time, events.object_key, events.object_size, events.object_etag, events.object_version_id,
+    notifications.id, notifications.bucket_name, notifications.event_type, notifications.destination_type, notifications.destination_arn, notifications.filter_prefix, notifications.filter_suffix, notifications.enabled, notifications.created_at, notifications.updated_at, notifications.managed_by
 FROM notification_jobs
 JOIN events ON notification_jobs.event_id = events.id
 JOIN notifications ON notification_jobs.notification_id = notifications.id
-WHERE notification_jobs.status = 'pending'
-ORDER BY notification_jobs.created_at ASC
+WHERE notification_jobs.updated_at >= CAST(?1 AS TEXT)
+  AND (CAST(?2 AS TEXT) IS NULL OR events.bucket_name = CAST(?2 AS TEXT))
+ORDER BY notification_jobs.id
 `
 
-type ListPendingNotificationJobsRow struct {
+type ListNotificationJobsUpdatedSinceParams struct {
+	Since      string         `json:"since"`
+	BucketName sql.NullString `json:"bucket_name"`
+}
+
+type ListNotificationJobsUpdatedSinceRow struct {
 	NotificationJob NotificationJob `json:"notification_job"`
 	Event           Event           `json:"event"`
 	Notification    Notification    `json:"notification"`
 }
 
-func (q *Queries) ListPendingNotificationJobs(ctx context.Context) ([]ListPendingNotificationJobsRow, error) {
-	rows, err := q.query(ctx, q.listPendingNotificationJobsStmt, ListPendingNotificationJobs)
+// Lists the jobs updated at or after the given time, optionally only those of
+// a bucket
+func (q *Queries) ListNotificationJobsUpdatedSince(ctx context.Context, arg ListNotificationJobsUpdatedSinceParams) ([]ListNotificationJobsUpdatedSinceRow, error) {
+	rows, err := q.query(ctx, q.listNotificationJobsUpdatedSinceStmt, ListNotificationJobsUpdatedSince, arg.Since, arg.BucketName)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []ListPendingNotificationJobsRow{}
+	items := []ListNotificationJobsUpdatedSinceRow{}
 	for rows.Next() {
-		var i ListPendingNotificationJobsRow
+		var i ListNotificationJobsUpdatedSinceRow
 		if err := rows.Scan(
 			&i.NotificationJob.ID,
 			&i.NotificationJob.EventID,
@@ -249,11 +552,20 @@ func (q *Queries) ListPendingNotificationJobs(ctx context.Context) ([]ListPendin
 			&i.NotificationJob.ErrorMessage,
 			&i.NotificationJob.CreatedAt,
 			&i.NotificationJob.UpdatedAt,
+			&i.NotificationJob.NextAttemptAt,
+			&i.NotificationJob.ClaimedUntil,
+			&i.NotificationJob.ResponseStatus,
+			&i.NotificationJob.ResponseBody,
+			&i.NotificationJob.DeliveredTargets,
 			&i.Event.ID,
 			&i.Event.BucketName,
 			&i.Event.ObjectID,
 			&i.Event.EventType,
 			&i.Event.EventTime,
+			&i.Event.ObjectKey,
+			&i.Event.ObjectSize,
+			&i.Event.ObjectETag,
+			&i.Event.ObjectVersionID,
 			&i.Notification.ID,
 			&i.Notification.BucketName,
 			&i.Notification.EventType,
@@ -264,6 +576,49 @@ func (q *Queries) ListPendingNotificationJobs(ctx context.Context) ([]ListPendin
 			&i.Notification.Enabled,
 			&i.Notification.CreatedAt,
 			&i.Notification.UpdatedAt,
+			&i.Notification.ManagedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ListNotificationsByBucket = `-- name: ListNotificationsByBucket :many
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
+FROM notifications
+WHERE bucket_name = ?
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListNotificationsByBucket(ctx context.Context, bucketName string) ([]Notification, error) {
+	rows, err := q.query(ctx, q.listNotificationsByBucketStmt, ListNotificationsByBucket, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.BucketName,
+			&i.EventType,
+			&i.DestinationType,
+			&i.DestinationArn,
+			&i.FilterPrefix,
+			&i.FilterSuffix,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ManagedBy,
 		); err != nil {
 			return nil, err
 		}
@@ -278,6 +633,165 @@ func (q *Queries) ListPendingNotificationJobs(ctx context.Context) ([]ListPendin
 	return items, nil
 }
 
+const ListNotificationsByEventType = `-- name: ListNotificationsByEventType :many
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
+FROM notifications
+WHERE bucket_name = ? AND event_type = ? AND enabled = 1
+`
+
+type ListNotificationsByEventTypeParams struct {
+	BucketName string `json:"bucket_name"`
+	EventType  string `json:"event_type"`
+}
+
+func (q *Queries) ListNotificationsByEventType(ctx context.Context, arg ListNotificationsByEventTypeParams) ([]Notification, error) {
+	rows, err := q.query(ctx, q.listNotificationsByEventTypeStmt, ListNotificationsByEventType, arg.BucketName, arg.EventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Notification{}
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.BucketName,
+			&i.EventType,
+			&i.DestinationType,
+			&i.DestinationArn,
+			&i.FilterPrefix,
+			&i.FilterSuffix,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ManagedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const PurgeNotificationJobs = `-- name: PurgeNotificationJobs :execrows
+DELETE FROM notification_jobs
+WHERE (CASE WHEN CAST(?1 AS TEXT) IS NULL THEN status != 'pending'
+            ELSE status = CAST(?1 AS TEXT) END)
+  AND (CAST(?2 AS TEXT) IS NULL
+       OR event_id IN (SELECT id FROM events WHERE bucket_name = CAST(?2 AS TEXT)))
+  AND (CAST(?3 AS TEXT) IS NULL OR updated_at < CAST(?3 AS TEXT))
+`
+
+type PurgeNotificationJobsParams struct {
+	Status     sql.NullString `json:"status"`
+	BucketName sql.NullString `json:"bucket_name"`
+	Before     sql.NullString `json:"before"`
+}
+
+// Deletes the jobs with the given status, or all jobs that are no longer
+// pending, last updated before the given time
+func (q *Queries) PurgeNotificationJobs(ctx context.Context, arg PurgeNotificationJobsParams) (int64, error) {
+	result, err := q.exec(ctx, q.purgeNotificationJobsStmt, PurgeNotificationJobs, arg.Status, arg.BucketName, arg.Before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const ReleaseNotificationJob = `-- name: ReleaseNotificationJob :exec
+UPDATE notification_jobs
+SET claimed_until = NULL
+WHERE id = ?
+`
+
+func (q *Queries) ReleaseNotificationJob(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.releaseNotificationJobStmt, ReleaseNotificationJob, id)
+	return err
+}
+
+const RequeueDeadLetterNotificationJobs = `-- name: RequeueDeadLetterNotificationJobs :execrows
+UPDATE notification_jobs
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'dead_letter'
+  AND (CAST(?1 AS TEXT) IS NULL
+       OR event_id IN (SELECT id FROM events WHERE bucket_name = CAST(?1 AS TEXT)))
+`
+
+func (q *Queries) RequeueDeadLetterNotificationJobs(ctx context.Context, bucketName sql.NullString) (int64, error) {
+	result, err := q.exec(ctx, q.requeueDeadLetterNotificationJobsStmt, RequeueDeadLetterNotificationJobs, bucketName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const RetryNotificationJob = `-- name: RetryNotificationJob :execrows
+UPDATE notification_jobs
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = NULL,
+    claimed_until = NULL,
+    error_message = NULL,
+    delivered_targets = CASE WHEN status = 'completed' THEN NULL ELSE delivered_targets END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+// Makes a job due again with the full number of attempts. An attempt in
+// progress loses its lease, so its outcome is not recorded. A completed job is
+// delivered to all of its targets again.
+func (q *Queries) RetryNotificationJob(ctx context.Context, id int64) (int64, error) {
+	result, err := q.exec(ctx, q.retryNotificationJobStmt, RetryNotificationJob, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const ScheduleNotificationJobRetry = `-- name: ScheduleNotificationJobRetry :exec
+UPDATE notification_jobs
+SET attempts = ?1,
+    error_message = ?2,
+    response_status = ?3,
+    response_body = ?4,
+    next_attempt_at = ?5,
+    claimed_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?6 AND status = 'pending' AND claimed_until = ?7
+`
+
+type ScheduleNotificationJobRetryParams struct {
+	Attempts       int64          `json:"attempts"`
+	ErrorMessage   sql.NullString `json:"error_message"`
+	ResponseStatus sql.NullInt64  `json:"response_status"`
+	ResponseBody   sql.NullString `json:"response_body"`
+	NextAttemptAt  sql.NullTime   `json:"next_attempt_at"`
+	ID             int64          `json:"id"`
+	ClaimedUntil   sql.NullTime   `json:"claimed_until"`
+}
+
+func (q *Queries) ScheduleNotificationJobRetry(ctx context.Context, arg ScheduleNotificationJobRetryParams) error {
+	_, err := q.exec(ctx, q.scheduleNotificationJobRetryStmt, ScheduleNotificationJobRetry,
+		arg.Attempts,
+		arg.ErrorMessage,
+		arg.ResponseStatus,
+		arg.ResponseBody,
+		arg.NextAttemptAt,
+		arg.ID,
+		arg.ClaimedUntil,
+	)
+	return err
+}
+
 const UpdateNotification = `-- name: UpdateNotification :exec
 UPDATE notifications
 SET event_type = ?, destination_type = ?, destination_arn = ?, filter_prefix = ?, filter_suffix = ?, enabled = ?, updated_at = CURRENT_TIMESTAMP
@@ -325,26 +839,37 @@ func (q *Queries) UpdateNotificationEnabled(ctx context.Context, arg UpdateNotif
 
 const UpdateNotificationJobStatus = `-- name: UpdateNotificationJobStatus :exec
 UPDATE notification_jobs
-SET status = ?,
-    attempts = ?,
-    error_message = ?,
+SET status = ?1,
+    attempts = ?2,
+    error_message = ?3,
+    response_status = ?4,
+    response_body = ?5,
+    claimed_until = NULL,
     updated_at = CURRENT_TIMESTAMP
-WHERE id = ?
+WHERE id = ?6 AND status = 'pending' AND claimed_until = ?7
 `
 
 type UpdateNotificationJobStatusParams struct {
-	Status       string         `json:"status"`
-	Attempts     int64          `json:"attempts"`
-	ErrorMessage sql.NullString `json:"error_message"`
-	ID           int64          `json:"id"`
+	Status         string         `json:"status"`
+	Attempts       int64          `json:"attempts"`
+	ErrorMessage   sql.NullString `json:"error_message"`
+	ResponseStatus sql.NullInt64  `json:"response_status"`
+	ResponseBody   sql.NullString `json:"response_body"`
+	ID             int64          `json:"id"`
+	ClaimedUntil   sql.NullTime   `json:"claimed_until"`
 }
 
+// Records the outcome of an attempt, unless the job was cancelled or retried
+// meanwhile and so no longer holds the lease of the attempt
 func (q *Queries) UpdateNotificationJobStatus(ctx context.Context, arg UpdateNotificationJobStatusParams) error {
 	_, err := q.exec(ctx, q.updateNotificationJobStatusStmt, UpdateNotificationJobStatus,
 		arg.Status,
 		arg.Attempts,
 		arg.ErrorMessage,
+		arg.ResponseStatus,
+		arg.ResponseBody,
 		arg.ID,
+		arg.ClaimedUntil,
 	)
 	return err
 }
@@ -1,6 +1,6 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 // source: object.sql
 
 package db
@@ -11,92 +11,10 @@ import (
 	"time"
 )
 
-const CopyObject = `-- name: CopyObject :one
-INSERT INTO objects (
-    bucket_name,
-    key,
-    data,
-    size,
-    etag,
-    content_type,
-    content_encoding,
-    content_disposition,
-    cache_control,
-    expires,
-    storage_class,
-    server_side_encryption
-)
-SELECT ?, ?, o.data, o.size, o.etag, o.content_type, o.content_encoding,
-       o.content_disposition, o.cache_control, o.expires, ?, ?
-FROM objects o
-WHERE o.bucket_name = ? AND o.key = ?
-RETURNING id, bucket_name, key, size, etag, content_type, content_encoding,
-          content_disposition, cache_control, expires, storage_class,
-          server_side_encryption, version_id, created_at, updated_at
-`
-
-type CopyObjectParams struct {
-	BucketName           string         `json:"bucket_name"`
-	Key                  string         `json:"key"`
-	StorageClass         string         `json:"storage_class"`
-	ServerSideEncryption sql.NullString `json:"server_side_encryption"`
-	BucketName_2         string         `json:"bucket_name_2"`
-	Key_2                string         `json:"key_2"`
-}
-
-type CopyObjectRow struct {
-	ID                   int64          `json:"id"`
-	BucketName           string         `json:"bucket_name"`
-	Key                  string         `json:"key"`
-	Size                 int64          `json:"size"`
-	ETag                 string         `json:"etag"`
-	ContentType          string         `json:"content_type"`
-	ContentEncoding      sql.NullString `json:"content_encoding"`
-	ContentDisposition   sql.NullString `json:"content_disposition"`
-	CacheControl         sql.NullString `json:"cache_control"`
-	Expires              sql.NullTime   `json:"expires"`
-	StorageClass         string         `json:"storage_class"`
-	ServerSideEncryption sql.NullString `json:"server_side_encryption"`
-	VersionID            sql.NullString `json:"version_id"`
-	CreatedAt            time.Time      `json:"created_at"`
-	UpdatedAt            time.Time      `json:"updated_at"`
-}
-
-func (q *Queries) CopyObject(ctx context.Context, arg CopyObjectParams) (CopyObjectRow, error) {
-	row := q.queryRow(ctx, q.copyObjectStmt, CopyObject,
-		arg.BucketName,
-		arg.Key,
-		arg.StorageClass,
-		arg.ServerSideEncryption,
-		arg.BucketName_2,
-		arg.Key_2,
-	)
-	var i CopyObjectRow
-	err := row.Scan(
-		&i.ID,
-		&i.BucketName,
-		&i.Key,
-		&i.Size,
-		&i.ETag,
-		&i.ContentType,
-		&i.ContentEncoding,
-		&i.ContentDisposition,
-		&i.CacheControl,
-		&i.Expires,
-		&i.StorageClass,
-		&i.ServerSideEncryption,
-		&i.VersionID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
-	)
-	return i, err
-}
-
 const CreateObject = `-- name: CreateObject :one
 INSERT INTO objects (
     bucket_name,
     key,
-    data,
     size,
     etag,
     content_type,
@@ -106,9 +24,13 @@ INSERT INTO objects (
     expires,
     storage_class,
     server_side_encryption,
-    version_id
+    version_id,
+    checksum_algorithm,
+    checksum_value,
+    checksum_type,
+    blob_id
 )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id, bucket_name, key, size, etag, content_type, content_encoding,
           content_disposition, cache_control, expires, storage_class,
           server_side_encryption, version_id, created_at, updated_at
@@ -117,7 +39,6 @@ RETURNING id, bucket_name, key, size, etag, content_type, content_encoding,
 type CreateObjectParams struct {
 	BucketName           string         `json:"bucket_name"`
 	Key                  string         `json:"key"`
-	Data                 []byte         `json:"data"`
 	Size                 int64          `json:"size"`
 	ETag                 string         `json:"etag"`
 	ContentType          string         `json:"content_type"`
@@ -128,6 +49,10 @@ type CreateObjectParams struct {
 	StorageClass         string         `json:"storage_class"`
 	ServerSideEncryption sql.NullString `json:"server_side_encryption"`
 	VersionID            sql.NullString `json:"version_id"`
+	ChecksumAlgorithm    sql.NullString `json:"checksum_algorithm"`
+	ChecksumValue        sql.NullString `json:"checksum_value"`
+	ChecksumType         sql.NullString `json:"checksum_type"`
+	BlobID               sql.NullString `json:"blob_id"`
 }
 
 type CreateObjectRow struct {
@@ -152,7 +77,6 @@ func (q *Queries) CreateObject(ctx context.Context, arg CreateObjectParams) (Cre
 	row := q.queryRow(ctx, q.createObjectStmt, CreateObject,
 		arg.BucketName,
 		arg.Key,
-		arg.Data,
 		arg.Size,
 		arg.ETag,
 		arg.ContentType,
@@ -163,6 +87,10 @@ func (q *Queries) CreateObject(ctx context.Context, arg CreateObjectParams) (Cre
 		arg.StorageClass,
 		arg.ServerSideEncryption,
 		arg.VersionID,
+		arg.ChecksumAlgorithm,
+		arg.ChecksumValue,
+		arg.ChecksumType,
+		arg.BlobID,
 	)
 	var i CreateObjectRow
 	err := row.Scan(
@@ -231,7 +159,7 @@ func (q *Queries) DeleteAllObjectTags(ctx context.Context, objectID int64) error
 
 const DeleteObject = `-- name: DeleteObject :exec
 DELETE FROM objects
-WHERE bucket_name = ? AND key = ?
+WHERE bucket_name = ? AND key = ? AND version_id IS NULL
 `
 
 type DeleteObjectParams struct {
@@ -239,6 +167,7 @@ type DeleteObjectParams struct {
 	Key        string `json:"key"`
 }
 
+// Deletes the null version, the only version of an unversioned object
 func (q *Queries) DeleteObject(ctx context.Context, arg DeleteObjectParams) error {
 	_, err := q.exec(ctx, q.deleteObjectStmt, DeleteObject, arg.BucketName, arg.Key)
 	return err
@@ -265,11 +194,13 @@ func (q *Queries) DeleteObjectTags(ctx context.Context, objectID int64) error {
 }
 
 const GetObject = `-- name: GetObject :one
-SELECT id, bucket_name, key, data, size, etag, content_type, content_encoding,
+SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
-       server_side_encryption, version_id, created_at, updated_at
+       server_side_encryption, version_id, created_at, updated_at,
+       checksum_algorithm, checksum_value, checksum_type, is_latest, is_delete_marker,
+       blob_id
 FROM objects
-WHERE bucket_name = ? AND key = ?
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0
 `
 
 type GetObjectParams struct {
@@ -284,7 +215,6 @@ func (q *Queries) GetObject(ctx context.Context, arg GetObjectParams) (Object, e
 		&i.ID,
 		&i.BucketName,
 		&i.Key,
-		&i.Data,
 		&i.Size,
 		&i.ETag,
 		&i.ContentType,
@@ -297,12 +227,18 @@ func (q *Queries) GetObject(ctx context.Context, arg GetObjectParams) (Object, e
 		&i.VersionID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ChecksumAlgorithm,
+		&i.ChecksumValue,
+		&i.ChecksumType,
+		&i.IsLatest,
+		&i.IsDeleteMarker,
+		&i.BlobID,
 	)
 	return i, err
 }
 
 const GetObjectByID = `-- name: GetObjectByID :one
-SELECT objects.id, objects.bucket_name, objects."key", objects.data, objects.size, objects.etag, objects.content_type, objects.content_encoding, objects.content_disposition, objects.cache_control, objects.expires, objects.storage_class, objects.server_side_encryption, objects.version_id, objects.created_at, objects.updated_at
+SELECT objects.id, objects.bucket_name, objects."key", objects.size, objects.etag, objects.content_type, objects.content_encoding, objects.content_disposition, objects.cache_control, objects.expires, objects.storage_class, objects.server_side_encryption, objects.version_id, objects.created_at, objects.updated_at, objects.checksum_algorithm, objects.checksum_value, objects.checksum_type, objects.is_latest, objects.is_delete_marker, objects.blob_id
 FROM objects
 WHERE id = ?
 `
@@ -318,7 +254,6 @@ func (q *Queries) GetObjectByID(ctx context.Context, id int64) (GetObjectByIDRow
 		&i.Object.ID,
 		&i.Object.BucketName,
 		&i.Object.Key,
-		&i.Object.Data,
 		&i.Object.Size,
 		&i.Object.ETag,
 		&i.Object.ContentType,
@@ -331,6 +266,12 @@ func (q *Queries) GetObjectByID(ctx context.Context, id int64) (GetObjectByIDRow
 		&i.Object.VersionID,
 		&i.Object.CreatedAt,
 		&i.Object.UpdatedAt,
+		&i.Object.ChecksumAlgorithm,
+		&i.Object.ChecksumValue,
+		&i.Object.ChecksumType,
+		&i.Object.IsLatest,
+		&i.Object.IsDeleteMarker,
+		&i.Object.BlobID,
 	)
 	return i, err
 }
@@ -338,7 +279,7 @@ func (q *Queries) GetObjectByID(ctx context.Context, id int64) (GetObjectByIDRow
 const GetObjectID = `-- name: GetObjectID :one
 SELECT id
 FROM objects
-WHERE bucket_name = ? AND key = ?
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0
 `
 
 type GetObjectIDParams struct {
@@ -356,9 +297,10 @@ func (q *Queries) GetObjectID(ctx context.Context, arg GetObjectIDParams) (int64
 const GetObjectMetadata = `-- name: GetObjectMetadata :one
 SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
-       server_side_encryption, version_id, created_at, updated_at
+       server_side_encryption, version_id, created_at, updated_at,
+       checksum_algorithm, checksum_value, checksum_type, is_latest, is_delete_marker
 FROM objects
-WHERE bucket_name = ? AND key = ?
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0
 `
 
 type GetObjectMetadataParams struct {
@@ -382,6 +324,11 @@ type GetObjectMetadataRow struct {
 	VersionID            sql.NullString `json:"version_id"`
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
+	ChecksumAlgorithm    sql.NullString `json:"checksum_algorithm"`
+	ChecksumValue        sql.NullString `json:"checksum_value"`
+	ChecksumType         sql.NullString `json:"checksum_type"`
+	IsLatest             bool           `json:"is_latest"`
+	IsDeleteMarker       bool           `json:"is_delete_marker"`
 }
 
 func (q *Queries) GetObjectMetadata(ctx context.Context, arg GetObjectMetadataParams) (GetObjectMetadataRow, error) {
@@ -403,6 +350,11 @@ func (q *Queries) GetObjectMetadata(ctx context.Context, arg GetObjectMetadataPa
 		&i.VersionID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ChecksumAlgorithm,
+		&i.ChecksumValue,
+		&i.ChecksumType,
+		&i.IsLatest,
+		&i.IsDeleteMarker,
 	)
 	return i, err
 }
@@ -480,7 +432,7 @@ SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
        server_side_encryption, version_id, created_at, updated_at
 FROM objects
-WHERE bucket_name = ?
+WHERE bucket_name = ? AND is_latest = 1 AND is_delete_marker = 0
   AND (? = '' OR key >= ?)
   AND (? = '' OR key LIKE ? || '%')
 ORDER BY key ASC
@@ -565,7 +517,7 @@ SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
        server_side_encryption, version_id, created_at, updated_at
 FROM objects
-WHERE bucket_name = ?
+WHERE bucket_name = ? AND is_latest = 1 AND is_delete_marker = 0
   AND (? = '' OR key >= ?)
   AND (? = '' OR key LIKE ? || '%')
 ORDER BY key ASC
@@ -645,7 +597,7 @@ func (q *Queries) ListObjectsWithDelimiter(ctx context.Context, arg ListObjectsW
 const ObjectExists = `-- name: ObjectExists :one
 SELECT COUNT(*) > 0 as object_exists
 FROM objects
-WHERE bucket_name = ? AND key = ?
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0
 `
 
 type ObjectExistsParams struct {
@@ -662,7 +614,7 @@ func (q *Queries) ObjectExists(ctx context.Context, arg ObjectExistsParams) (boo
 
 const UpdateObject = `-- name: UpdateObject :exec
 UPDATE objects
-SET data = ?,
+SET blob_id = ?,
     size = ?,
     etag = ?,
     content_type = ?,
@@ -672,12 +624,15 @@ SET data = ?,
     expires = ?,
     storage_class = ?,
     server_side_encryption = ?,
+    checksum_algorithm = ?,
+    checksum_value = ?,
+    checksum_type = ?,
     updated_at = CURRENT_TIMESTAMP
-WHERE bucket_name = ? AND key = ?
+WHERE bucket_name = ? AND key = ? AND is_latest = 1
 `
 
 type UpdateObjectParams struct {
-	Data                 []byte         `json:"data"`
+	BlobID               sql.NullString `json:"blob_id"`
 	Size                 int64          `json:"size"`
 	ETag                 string         `json:"etag"`
 	ContentType          string         `json:"content_type"`
@@ -687,13 +642,16 @@ type UpdateObjectParams struct {
 	Expires              sql.NullTime   `json:"expires"`
 	StorageClass         string         `json:"storage_class"`
 	ServerSideEncryption sql.NullString `json:"server_side_encryption"`
+	ChecksumAlgorithm    sql.NullString `json:"checksum_algorithm"`
+	ChecksumValue        sql.NullString `json:"checksum_value"`
+	ChecksumType         sql.NullString `json:"checksum_type"`
 	BucketName           string         `json:"bucket_name"`
 	Key                  string         `json:"key"`
 }
 
 func (q *Queries) UpdateObject(ctx context.Context, arg UpdateObjectParams) error {
 	_, err := q.exec(ctx, q.updateObjectStmt, UpdateObject,
-		arg.Data,
+		arg.BlobID,
 		arg.Size,
 		arg.ETag,
 		arg.ContentType,
@@ -703,8 +661,96 @@ func (q *Queries) UpdateObject(ctx context.Context, arg UpdateObjectParams) erro
 		arg.Expires,
 		arg.StorageClass,
 		arg.ServerSideEncryption,
+		arg.ChecksumAlgorithm,
+		arg.ChecksumValue,
+		arg.ChecksumType,
 		arg.BucketName,
 		arg.Key,
 	)
 	return err
 }
+
+const UpsertObject = `-- name: UpsertObject :one
+INSERT INTO objects (
+    bucket_name,
+    key,
+    size,
+    etag,
+    content_type,
+    content_encoding,
+    content_disposition,
+    cache_control,
+    expires,
+    storage_class,
+    server_side_encryption,
+    version_id,
+    checksum_algorithm,
+    checksum_value,
+    checksum_type,
+    blob_id
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (bucket_name, key) WHERE is_latest = 1 DO UPDATE
+SET blob_id = excluded.blob_id,
+    size = excluded.size,
+    etag = excluded.etag,
+    content_type = excluded.content_type,
+    content_encoding = excluded.content_encoding,
+    content_disposition = excluded.content_disposition,
+    cache_control = excluded.cache_control,
+    expires = excluded.expires,
+    storage_class = excluded.storage_class,
+    server_side_encryption = excluded.server_side_encryption,
+    version_id = excluded.version_id,
+    checksum_algorithm = excluded.checksum_algorithm,
+    checksum_value = excluded.checksum_value,
+    checksum_type = excluded.checksum_type,
+    is_delete_marker = 0,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id
+`
+
+type UpsertObjectParams struct {
+	BucketName           string         `json:"bucket_name"`
+	Key                  string         `json:"key"`
+	Size                 int64          `json:"size"`
+	ETag                 string         `json:"etag"`
+	ContentType          string         `json:"content_type"`
+	ContentEncoding      sql.NullString `json:"content_encoding"`
+	ContentDisposition   sql.NullString `json:"content_disposition"`
+	CacheControl         sql.NullString `json:"cache_control"`
+	Expires              sql.NullTime   `json:"expires"`
+	StorageClass         string         `json:"storage_class"`
+	ServerSideEncryption sql.NullString `json:"server_side_encryption"`
+	VersionID            sql.NullString `json:"version_id"`
+	ChecksumAlgorithm    sql.NullString `json:"checksum_algorithm"`
+	ChecksumValue        sql.NullString `json:"checksum_value"`
+	ChecksumType         sql.NullString `json:"checksum_type"`
+	BlobID               sql.NullString `json:"blob_id"`
+}
+
+// Creates the current version of key, or overwrites it in place when the
+// bucket is unversioned
+func (q *Queries) UpsertObject(ctx context.Context, arg UpsertObjectParams) (int64, error) {
+	row := q.queryRow(ctx, q.upsertObjectStmt, UpsertObject,
+		arg.BucketName,
+		arg.Key,
+		arg.Size,
+		arg.ETag,
+		arg.ContentType,
+		arg.ContentEncoding,
+		arg.ContentDisposition,
+		arg.CacheControl,
+		arg.Expires,
+		arg.StorageClass,
+		arg.ServerSideEncryption,
+		arg.VersionID,
+		arg.ChecksumAlgorithm,
+		arg.ChecksumValue,
+		arg.ChecksumType,
+		arg.BlobID,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
@@ -1,61 +1,132 @@
 // Code generated by sqlc. DO NOT EDIT.
 // versions:
-//   sqlc v1.29.0
+//   sqlc v1.30.0
 
 package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
+	// Records that a pending job was delivered to one of its targets
+	AddNotificationJobDeliveredTarget(ctx context.Context, arg AddNotificationJobDeliveredTargetParams) error
 	BucketExists(ctx context.Context, name string) (bool, error)
 	BucketPolicyExists(ctx context.Context, bucketName string) (bool, error)
-	CopyObject(ctx context.Context, arg CopyObjectParams) (CopyObjectRow, error)
+	CancelNotificationJob(ctx context.Context, id int64) (int64, error)
+	// Claims due pending jobs until claimed_until. A job waits while an earlier
+	// job for the same object and notification is pending, so that each
+	// destination receives the events of an object in order.
+	ClaimNotificationJobs(ctx context.Context, arg ClaimNotificationJobsParams) ([]int64, error)
 	CountObjectsInBucket(ctx context.Context, bucketName string) (int64, error)
+	CreateBlob(ctx context.Context, arg CreateBlobParams) error
+	CreateBlobChunk(ctx context.Context, arg CreateBlobChunkParams) error
 	CreateBucket(ctx context.Context, arg CreateBucketParams) error
 	CreateBucketTag(ctx context.Context, arg CreateBucketTagParams) error
+	CreateConfigNotification(ctx context.Context, arg CreateConfigNotificationParams) (Notification, error)
+	CreateDeleteMarker(ctx context.Context, arg CreateDeleteMarkerParams) (int64, error)
 	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	CreateMultipartUpload(ctx context.Context, arg CreateMultipartUploadParams) error
 	CreateNotification(ctx context.Context, arg CreateNotificationParams) (Notification, error)
+	CreateNotificationJob(ctx context.Context, arg CreateNotificationJobParams) error
 	CreateObject(ctx context.Context, arg CreateObjectParams) (CreateObjectRow, error)
 	// Object Metadata queries
 	CreateObjectMetadata(ctx context.Context, arg CreateObjectMetadataParams) error
+	// Object part layout queries
+	CreateObjectPart(ctx context.Context, arg CreateObjectPartParams) error
 	// Object Tags queries
 	CreateObjectTag(ctx context.Context, arg CreateObjectTagParams) error
 	DeleteAllObjectTags(ctx context.Context, objectID int64) error
+	DeleteBlob(ctx context.Context, id string) error
+	DeleteBlobChunks(ctx context.Context, blobID string) error
 	DeleteBucket(ctx context.Context, name string) error
 	DeleteBucketPolicy(ctx context.Context, bucketName string) error
 	DeleteBucketTag(ctx context.Context, arg DeleteBucketTagParams) error
 	DeleteBucketTags(ctx context.Context, bucketName string) error
+	DeleteMultipartUpload(ctx context.Context, uploadID string) error
 	DeleteNotification(ctx context.Context, id int64) error
+	DeleteNotificationJob(ctx context.Context, id int64) (int64, error)
+	// Deletes the null version, the only version of an unversioned object
 	DeleteObject(ctx context.Context, arg DeleteObjectParams) error
 	DeleteObjectMetadata(ctx context.Context, objectID int64) error
+	DeleteObjectParts(ctx context.Context, objectID int64) error
 	DeleteObjectTags(ctx context.Context, objectID int64) error
-	GetBucket(ctx context.Context, name string) (Bucket, error)
+	DeleteObjectVersionByID(ctx context.Context, id int64) error
+	DeleteReleasedBlob(ctx context.Context, blobID string) error
+	DemoteLatestObjectVersion(ctx context.Context, arg DemoteLatestObjectVersionParams) error
+	// Returns the chunk containing the byte at position
+	GetBlobChunk(ctx context.Context, arg GetBlobChunkParams) (GetBlobChunkRow, error)
+	GetBlobSize(ctx context.Context, id string) (int64, error)
+	GetBucket(ctx context.Context, name string) (GetBucketRow, error)
 	GetBucketPolicy(ctx context.Context, bucketName string) (GetBucketPolicyRow, error)
 	GetBucketTags(ctx context.Context, bucketName string) ([]GetBucketTagsRow, error)
+	GetBucketVersioning(ctx context.Context, name string) (sql.NullString, error)
+	GetLatestEventID(ctx context.Context) (int64, error)
+	// Unlike GetObjectMetadata, the current version may be a delete marker
+	GetLatestObjectVersion(ctx context.Context, arg GetLatestObjectVersionParams) (GetLatestObjectVersionRow, error)
+	GetMultipartUpload(ctx context.Context, arg GetMultipartUploadParams) (MultipartUpload, error)
+	GetMultipartUploadPartsWithBlobs(ctx context.Context, uploadID string) ([]GetMultipartUploadPartsWithBlobsRow, error)
 	GetNotification(ctx context.Context, id int64) (Notification, error)
+	GetNotificationJob(ctx context.Context, id int64) (GetNotificationJobRow, error)
 	GetObject(ctx context.Context, arg GetObjectParams) (Object, error)
 	GetObjectByID(ctx context.Context, id int64) (GetObjectByIDRow, error)
 	GetObjectID(ctx context.Context, arg GetObjectIDParams) (int64, error)
 	GetObjectMetadata(ctx context.Context, arg GetObjectMetadataParams) (GetObjectMetadataRow, error)
 	GetObjectMetadataByObjectID(ctx context.Context, objectID int64) ([]GetObjectMetadataByObjectIDRow, error)
 	GetObjectTags(ctx context.Context, objectID int64) ([]GetObjectTagsRow, error)
-	ListBuckets(ctx context.Context) ([]Bucket, error)
-	ListBucketsFiltered(ctx context.Context, arg ListBucketsFilteredParams) ([]Bucket, error)
+	// The null version is addressed by the version ID "null"
+	GetObjectVersion(ctx context.Context, arg GetObjectVersionParams) (Object, error)
+	ListBlobIDs(ctx context.Context, limit int64) ([]string, error)
+	ListBuckets(ctx context.Context) ([]ListBucketsRow, error)
+	ListBucketsFiltered(ctx context.Context, arg ListBucketsFilteredParams) ([]ListBucketsFilteredRow, error)
 	ListEnabledNotificationsByBucket(ctx context.Context, bucketName string) ([]Notification, error)
+	// Lists events, newest first, optionally only those of a bucket, with a job
+	// in the given status or that occurred in [since, until)
+	ListEvents(ctx context.Context, arg ListEventsParams) ([]Event, error)
+	// Lists the events recorded after the event with the given ID, oldest first,
+	// optionally only those of a bucket
+	ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]Event, error)
 	ListEventsByBucket(ctx context.Context, arg ListEventsByBucketParams) ([]Event, error)
+	ListMultipartUploadParts(ctx context.Context, arg ListMultipartUploadPartsParams) ([]ListMultipartUploadPartsRow, error)
+	ListMultipartUploads(ctx context.Context, arg ListMultipartUploadsParams) ([]MultipartUpload, error)
+	ListNotificationJobs(ctx context.Context, arg ListNotificationJobsParams) ([]ListNotificationJobsRow, error)
+	ListNotificationJobsByEvent(ctx context.Context, eventID int64) ([]ListNotificationJobsByEventRow, error)
+	// Lists the jobs updated at or after the given time, optionally only those of
+	// a bucket
+	ListNotificationJobsUpdatedSince(ctx context.Context, arg ListNotificationJobsUpdatedSinceParams) ([]ListNotificationJobsUpdatedSinceRow, error)
 	ListNotificationsByBucket(ctx context.Context, bucketName string) ([]Notification, error)
 	ListNotificationsByEventType(ctx context.Context, arg ListNotificationsByEventTypeParams) ([]Notification, error)
+	ListObjectParts(ctx context.Context, objectID int64) ([]ListObjectPartsRow, error)
+	ListObjectVersions(ctx context.Context, arg ListObjectVersionsParams) ([]ListObjectVersionsRow, error)
 	ListObjects(ctx context.Context, arg ListObjectsParams) ([]ListObjectsRow, error)
 	ListObjectsWithDelimiter(ctx context.Context, arg ListObjectsWithDelimiterParams) ([]ListObjectsWithDelimiterRow, error)
-	ListPendingNotificationJobs(ctx context.Context) ([]ListPendingNotificationJobsRow, error)
+	ListReleasedBlobs(ctx context.Context, limit int64) ([]string, error)
 	ObjectExists(ctx context.Context, arg ObjectExistsParams) (bool, error)
+	// Makes the most recent remaining version current after the current one was deleted
+	PromoteLatestObjectVersion(ctx context.Context, arg PromoteLatestObjectVersionParams) error
+	// Deletes the jobs with the given status, or all jobs that are no longer
+	// pending, last updated before the given time
+	PurgeNotificationJobs(ctx context.Context, arg PurgeNotificationJobsParams) (int64, error)
 	PutBucketPolicy(ctx context.Context, arg PutBucketPolicyParams) error
+	PutMultipartUploadPart(ctx context.Context, arg PutMultipartUploadPartParams) error
+	ReleaseNotificationJob(ctx context.Context, id int64) error
+	RequeueDeadLetterNotificationJobs(ctx context.Context, bucketName sql.NullString) (int64, error)
+	// Makes a job due again with the full number of attempts. An attempt in
+	// progress loses its lease, so its outcome is not recorded. A completed job is
+	// delivered to all of its targets again.
+	RetryNotificationJob(ctx context.Context, id int64) (int64, error)
+	ScheduleNotificationJobRetry(ctx context.Context, arg ScheduleNotificationJobRetryParams) error
+	UpdateBucketVersioning(ctx context.Context, arg UpdateBucketVersioningParams) error
 	UpdateNotification(ctx context.Context, arg UpdateNotificationParams) error
 	UpdateNotificationEnabled(ctx context.Context, arg UpdateNotificationEnabledParams) error
+	// Records the outcome of an attempt, unless the job was cancelled or retried
+	// meanwhile and so no longer holds the lease of the attempt
 	UpdateNotificationJobStatus(ctx context.Context, arg UpdateNotificationJobStatusParams) error
 	UpdateObject(ctx context.Context, arg UpdateObjectParams) error
+	// Creates the current version of key, or overwrites it in place when the
+	// bucket is unversioned
+	UpsertObject(ctx context.Context, arg UpsertObjectParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
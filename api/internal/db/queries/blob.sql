@@ -0,0 +1,44 @@
+-- name: CreateBlob :exec
+INSERT INTO blobs (id, size)
+VALUES (?, ?);
+
+-- name: CreateBlobChunk :exec
+INSERT INTO blob_chunks (blob_id, position, data)
+VALUES (?, ?, ?);
+
+-- name: GetBlobSize :one
+SELECT size
+FROM blobs
+WHERE id = ?;
+
+-- name: GetBlobChunk :one
+-- Returns the chunk containing the byte at position
+SELECT position, data
+FROM blob_chunks
+WHERE blob_id = sqlc.arg('blob_id') AND position <= sqlc.arg('position')
+ORDER BY position DESC
+LIMIT 1;
+
+-- name: DeleteBlob :exec
+DELETE FROM blobs
+WHERE id = ?;
+
+-- name: DeleteBlobChunks :exec
+DELETE FROM blob_chunks
+WHERE blob_id = ?;
+
+-- name: ListBlobIDs :many
+SELECT id
+FROM blobs
+ORDER BY id ASC
+LIMIT ?;
+
+-- name: ListReleasedBlobs :many
+SELECT blob_id
+FROM released_blobs
+ORDER BY released_at ASC
+LIMIT ?;
+
+-- name: DeleteReleasedBlob :exec
+DELETE FROM released_blobs
+WHERE blob_id = ?;
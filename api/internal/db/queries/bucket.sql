@@ -72,4 +72,14 @@ WHERE bucket_name = ?;
 -- name: BucketPolicyExists :one
 SELECT COUNT(*) > 0 as policy_exists
 FROM bucket_policies
-WHERE bucket_name = ?;
\ No newline at end of file
+WHERE bucket_name = ?;
+
+-- name: GetBucketVersioning :one
+SELECT versioning_status
+FROM buckets
+WHERE name = ?;
+
+-- name: UpdateBucketVersioning :exec
+UPDATE buckets
+SET versioning_status = ?
+WHERE name = ?;
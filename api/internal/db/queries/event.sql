@@ -1,12 +1,39 @@
 -- name: CreateEvent :one
-INSERT INTO events (bucket_name, object_id, event_type)
-VALUES (?, ?, ?)
+INSERT INTO events (bucket_name, object_id, event_type, object_key, object_size, object_etag, object_version_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
 
 -- name: ListEventsByBucket :many
-SELECT id, bucket_name, object_id, event_type, event_time
+SELECT id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
 FROM events
 WHERE bucket_name = ?
 ORDER BY event_time DESC
 LIMIT ? OFFSET ?;
+
+-- name: ListEvents :many
+-- Lists events, newest first, optionally only those of a bucket, with a job
+-- in the given status or that occurred in [since, until)
+SELECT id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
+FROM events
+WHERE (CAST(sqlc.narg(bucket_name) AS TEXT) IS NULL OR bucket_name = CAST(sqlc.narg(bucket_name) AS TEXT))
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL
+       OR EXISTS (SELECT 1 FROM notification_jobs WHERE event_id = events.id AND status = CAST(sqlc.narg(status) AS TEXT)))
+  AND (CAST(sqlc.narg(since) AS TEXT) IS NULL OR event_time >= CAST(sqlc.narg(since) AS TEXT))
+  AND (CAST(sqlc.narg(until) AS TEXT) IS NULL OR event_time < CAST(sqlc.narg(until) AS TEXT))
+ORDER BY id DESC
+LIMIT sqlc.arg(max_events) OFFSET sqlc.arg(skip_events);
+
+-- name: GetLatestEventID :one
+SELECT CAST(COALESCE(MAX(id), 0) AS INTEGER) AS id
+FROM events;
+
+-- name: ListEventsAfter :many
+-- Lists the events recorded after the event with the given ID, oldest first,
+-- optionally only those of a bucket
+SELECT id, bucket_name, object_id, event_type, event_time, object_key, object_size, object_etag, object_version_id
+FROM events
+WHERE id > sqlc.arg(after_id)
+  AND (CAST(sqlc.narg(bucket_name) AS TEXT) IS NULL OR bucket_name = CAST(sqlc.narg(bucket_name) AS TEXT))
+ORDER BY id
+LIMIT sqlc.arg(max_events);
@@ -0,0 +1,79 @@
+-- name: CreateMultipartUpload :exec
+INSERT INTO multipart_uploads (
+    upload_id,
+    bucket_name,
+    key,
+    content_type,
+    content_encoding,
+    content_disposition,
+    cache_control,
+    storage_class,
+    metadata,
+    checksum_algorithm,
+    checksum_type,
+    tags
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetMultipartUpload :one
+SELECT upload_id, bucket_name, key, content_type, content_encoding,
+       content_disposition, cache_control, storage_class, metadata, created_at,
+       checksum_algorithm, checksum_type, tags
+FROM multipart_uploads
+WHERE upload_id = ? AND bucket_name = ? AND key = ?;
+
+-- name: ListMultipartUploads :many
+SELECT upload_id, bucket_name, key, content_type, content_encoding,
+       content_disposition, cache_control, storage_class, metadata, created_at,
+       checksum_algorithm, checksum_type, tags
+FROM multipart_uploads
+WHERE bucket_name = sqlc.arg('bucket_name')
+  AND (CAST(sqlc.arg('prefix') AS TEXT) = '' OR key LIKE sqlc.arg('prefix') || '%')
+  AND (CAST(sqlc.arg('key_marker') AS TEXT) = ''
+       OR key > sqlc.arg('key_marker')
+       OR (key = sqlc.arg('key_marker') AND CAST(sqlc.arg('upload_id_marker') AS TEXT) != '' AND upload_id > sqlc.arg('upload_id_marker')))
+ORDER BY key ASC, upload_id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: DeleteMultipartUpload :exec
+DELETE FROM multipart_uploads
+WHERE upload_id = ?;
+
+-- name: PutMultipartUploadPart :exec
+INSERT INTO multipart_upload_parts (upload_id, part_number, blob_id, size, etag, checksum_value)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT(upload_id, part_number) DO UPDATE SET
+    blob_id = excluded.blob_id,
+    size = excluded.size,
+    etag = excluded.etag,
+    checksum_value = excluded.checksum_value,
+    created_at = CURRENT_TIMESTAMP;
+
+-- name: ListMultipartUploadParts :many
+SELECT part_number, size, etag, checksum_value, created_at
+FROM multipart_upload_parts
+WHERE upload_id = sqlc.arg('upload_id')
+  AND part_number > sqlc.arg('part_number_marker')
+ORDER BY part_number ASC
+LIMIT sqlc.arg('limit');
+
+-- name: GetMultipartUploadPartsWithBlobs :many
+SELECT part_number, blob_id, size, etag, checksum_value
+FROM multipart_upload_parts
+WHERE upload_id = ?
+ORDER BY part_number ASC;
+
+-- Object part layout queries
+-- name: CreateObjectPart :exec
+INSERT INTO object_parts (object_id, part_number, size, etag, checksum_value)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: ListObjectParts :many
+SELECT part_number, size, etag, checksum_value
+FROM object_parts
+WHERE object_id = ?
+ORDER BY part_number ASC;
+
+-- name: DeleteObjectParts :exec
+DELETE FROM object_parts
+WHERE object_id = ?;
@@ -3,25 +3,30 @@ INSERT INTO notifications (bucket_name, event_type, destination_type, destinatio
 VALUES (?, ?, ?, ?, ?, ?, ?)
 RETURNING *;
 
+-- name: CreateConfigNotification :one
+INSERT INTO notifications (bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, managed_by)
+VALUES (?, ?, ?, ?, ?, ?, ?, 'config')
+RETURNING *;
+
 -- name: GetNotification :one
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 FROM notifications
 WHERE id = ?;
 
 -- name: ListNotificationsByBucket :many
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 FROM notifications
 WHERE bucket_name = ?
 ORDER BY created_at DESC;
 
 -- name: ListEnabledNotificationsByBucket :many
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 FROM notifications
 WHERE bucket_name = ? AND enabled = 1
 ORDER BY created_at DESC;
 
 -- name: ListNotificationsByEventType :many
-SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at
+SELECT id, bucket_name, event_type, destination_type, destination_arn, filter_prefix, filter_suffix, enabled, created_at, updated_at, managed_by
 FROM notifications
 WHERE bucket_name = ? AND event_type = ? AND enabled = 1;
 
@@ -39,7 +44,35 @@ WHERE id = ?;
 DELETE FROM notifications
 WHERE id = ?;
 
--- name: ListPendingNotificationJobs :many
+-- name: ClaimNotificationJobs :many
+-- Claims due pending jobs until claimed_until. A job waits while an earlier
+-- job for the same object and notification is pending, so that each
+-- destination receives the events of an object in order.
+UPDATE notification_jobs
+SET claimed_until = sqlc.arg(claimed_until)
+WHERE id IN (
+    SELECT j.id
+    FROM notification_jobs j
+    JOIN events e ON e.id = j.event_id
+    WHERE j.status = 'pending'
+      AND (j.next_attempt_at IS NULL OR j.next_attempt_at <= sqlc.arg(now))
+      AND (j.claimed_until IS NULL OR j.claimed_until <= sqlc.arg(now))
+      AND NOT EXISTS (
+          SELECT 1
+          FROM notification_jobs earlier
+          JOIN events earlier_event ON earlier_event.id = earlier.event_id
+          WHERE earlier.notification_id = j.notification_id
+            AND earlier.status = 'pending'
+            AND earlier.id < j.id
+            AND earlier_event.bucket_name = e.bucket_name
+            AND earlier_event.object_key = e.object_key
+      )
+    ORDER BY j.id
+    LIMIT sqlc.arg(max_jobs)
+)
+RETURNING id;
+
+-- name: GetNotificationJob :one
 SELECT
     sqlc.embed(notification_jobs),
     sqlc.embed(events),
@@ -47,13 +80,126 @@ SELECT
 FROM notification_jobs
 JOIN events ON notification_jobs.event_id = events.id
 JOIN notifications ON notification_jobs.notification_id = notifications.id
-WHERE notification_jobs.status = 'pending'
-ORDER BY notification_jobs.created_at ASC;
+WHERE notification_jobs.id = ?;
+
+-- name: ListNotificationJobsByEvent :many
+SELECT
+    sqlc.embed(notification_jobs),
+    sqlc.embed(notifications)
+FROM notification_jobs
+JOIN notifications ON notification_jobs.notification_id = notifications.id
+WHERE notification_jobs.event_id = ?
+ORDER BY notification_jobs.id;
+
+-- name: ReleaseNotificationJob :exec
+UPDATE notification_jobs
+SET claimed_until = NULL
+WHERE id = ?;
 
 -- name: UpdateNotificationJobStatus :exec
+-- Records the outcome of an attempt, unless the job was cancelled or retried
+-- meanwhile and so no longer holds the lease of the attempt
+UPDATE notification_jobs
+SET status = sqlc.arg(status),
+    attempts = sqlc.arg(attempts),
+    error_message = sqlc.arg(error_message),
+    response_status = sqlc.arg(response_status),
+    response_body = sqlc.arg(response_body),
+    claimed_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND status = 'pending' AND claimed_until = sqlc.arg(claimed_until);
+
+-- name: ScheduleNotificationJobRetry :exec
+UPDATE notification_jobs
+SET attempts = sqlc.arg(attempts),
+    error_message = sqlc.arg(error_message),
+    response_status = sqlc.arg(response_status),
+    response_body = sqlc.arg(response_body),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    claimed_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND status = 'pending' AND claimed_until = sqlc.arg(claimed_until);
+
+-- name: AddNotificationJobDeliveredTarget :exec
+-- Records that a pending job was delivered to one of its targets
+UPDATE notification_jobs
+SET delivered_targets = json_insert(COALESCE(delivered_targets, '[]'), '$[#]', CAST(sqlc.arg(target) AS TEXT))
+WHERE id = sqlc.arg(id) AND status = 'pending';
+
+-- name: RequeueDeadLetterNotificationJobs :execrows
 UPDATE notification_jobs
-SET status = ?,
-    attempts = ?,
-    error_message = ?,
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE status = 'dead_letter'
+  AND (CAST(sqlc.narg(bucket_name) AS TEXT) IS NULL
+       OR event_id IN (SELECT id FROM events WHERE bucket_name = CAST(sqlc.narg(bucket_name) AS TEXT)));
+
+-- name: ListNotificationJobs :many
+SELECT
+    sqlc.embed(notification_jobs),
+    sqlc.embed(events),
+    sqlc.embed(notifications)
+FROM notification_jobs
+JOIN events ON notification_jobs.event_id = events.id
+JOIN notifications ON notification_jobs.notification_id = notifications.id
+WHERE (CAST(sqlc.narg(bucket_name) AS TEXT) IS NULL OR events.bucket_name = CAST(sqlc.narg(bucket_name) AS TEXT))
+  AND (CAST(sqlc.narg(status) AS TEXT) IS NULL OR notification_jobs.status = CAST(sqlc.narg(status) AS TEXT))
+  AND (CAST(sqlc.narg(since) AS TEXT) IS NULL OR notification_jobs.created_at >= CAST(sqlc.narg(since) AS TEXT))
+  AND (CAST(sqlc.narg(until) AS TEXT) IS NULL OR notification_jobs.created_at < CAST(sqlc.narg(until) AS TEXT))
+ORDER BY notification_jobs.id DESC
+LIMIT sqlc.arg(max_jobs) OFFSET sqlc.arg(skip_jobs);
+
+-- name: ListNotificationJobsUpdatedSince :many
+-- Lists the jobs updated at or after the given time, optionally only those of
+-- a bucket
+SELECT
+    sqlc.embed(notification_jobs),
+    sqlc.embed(events),
+    sqlc.embed(notifications)
+FROM notification_jobs
+JOIN events ON notification_jobs.event_id = events.id
+JOIN notifications ON notification_jobs.notification_id = notifications.id
+WHERE notification_jobs.updated_at >= CAST(sqlc.arg(since) AS TEXT)
+  AND (CAST(sqlc.narg(bucket_name) AS TEXT) IS NULL OR events.bucket_name = CAST(sqlc.narg(bucket_name) AS TEXT))
+ORDER BY notification_jobs.id;
+
+-- name: RetryNotificationJob :execrows
+-- Makes a job due again with the full number of attempts. An attempt in
+-- progress loses its lease, so its outcome is not recorded. A completed job is
+-- delivered to all of its targets again.
+UPDATE notification_jobs
+SET status = 'pending',
+    attempts = 0,
+    next_attempt_at = NULL,
+    claimed_until = NULL,
+    error_message = NULL,
+    delivered_targets = CASE WHEN status = 'completed' THEN NULL ELSE delivered_targets END,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = ?;
+
+-- name: CancelNotificationJob :execrows
+UPDATE notification_jobs
+SET status = 'cancelled',
+    claimed_until = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- name: DeleteNotificationJob :execrows
+DELETE FROM notification_jobs
+WHERE id = ?;
+
+-- name: PurgeNotificationJobs :execrows
+-- Deletes the jobs with the given status, or all jobs that are no longer
+-- pending, last updated before the given time
+DELETE FROM notification_jobs
+WHERE (CASE WHEN CAST(sqlc.narg(status) AS TEXT) IS NULL THEN status != 'pending'
+            ELSE status = CAST(sqlc.narg(status) AS TEXT) END)
+  AND (CAST(sqlc.narg(bucket_name) AS TEXT) IS NULL
+       OR event_id IN (SELECT id FROM events WHERE bucket_name = CAST(sqlc.narg(bucket_name) AS TEXT)))
+  AND (CAST(sqlc.narg(before) AS TEXT) IS NULL OR updated_at < CAST(sqlc.narg(before) AS TEXT));
+
+-- name: CreateNotificationJob :exec
+INSERT INTO notification_jobs (event_id, notification_id)
+VALUES (?, ?);
@@ -2,7 +2,6 @@
 INSERT INTO objects (
     bucket_name,
     key,
-    data,
     size,
     etag,
     content_type,
@@ -12,19 +11,66 @@ INSERT INTO objects (
     expires,
     storage_class,
     server_side_encryption,
-    version_id
+    version_id,
+    checksum_algorithm,
+    checksum_value,
+    checksum_type,
+    blob_id
 )
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 RETURNING id, bucket_name, key, size, etag, content_type, content_encoding,
           content_disposition, cache_control, expires, storage_class,
           server_side_encryption, version_id, created_at, updated_at;
 
+-- name: UpsertObject :one
+-- Creates the current version of key, or overwrites it in place when the
+-- bucket is unversioned
+INSERT INTO objects (
+    bucket_name,
+    key,
+    size,
+    etag,
+    content_type,
+    content_encoding,
+    content_disposition,
+    cache_control,
+    expires,
+    storage_class,
+    server_side_encryption,
+    version_id,
+    checksum_algorithm,
+    checksum_value,
+    checksum_type,
+    blob_id
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (bucket_name, key) WHERE is_latest = 1 DO UPDATE
+SET blob_id = excluded.blob_id,
+    size = excluded.size,
+    etag = excluded.etag,
+    content_type = excluded.content_type,
+    content_encoding = excluded.content_encoding,
+    content_disposition = excluded.content_disposition,
+    cache_control = excluded.cache_control,
+    expires = excluded.expires,
+    storage_class = excluded.storage_class,
+    server_side_encryption = excluded.server_side_encryption,
+    version_id = excluded.version_id,
+    checksum_algorithm = excluded.checksum_algorithm,
+    checksum_value = excluded.checksum_value,
+    checksum_type = excluded.checksum_type,
+    is_delete_marker = 0,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING id;
+
 -- name: GetObject :one
-SELECT id, bucket_name, key, data, size, etag, content_type, content_encoding,
+SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
-       server_side_encryption, version_id, created_at, updated_at
+       server_side_encryption, version_id, created_at, updated_at,
+       checksum_algorithm, checksum_value, checksum_type, is_latest, is_delete_marker,
+       blob_id
 FROM objects
-WHERE bucket_name = ? AND key = ?;
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0;
 
 -- name: GetObjectByID :one
 SELECT sqlc.embed(objects)
@@ -34,13 +80,14 @@ WHERE id = ?;
 -- name: GetObjectMetadata :one
 SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
-       server_side_encryption, version_id, created_at, updated_at
+       server_side_encryption, version_id, created_at, updated_at,
+       checksum_algorithm, checksum_value, checksum_type, is_latest, is_delete_marker
 FROM objects
-WHERE bucket_name = ? AND key = ?;
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0;
 
 -- name: UpdateObject :exec
 UPDATE objects
-SET data = ?,
+SET blob_id = ?,
     size = ?,
     etag = ?,
     content_type = ?,
@@ -50,24 +97,28 @@ SET data = ?,
     expires = ?,
     storage_class = ?,
     server_side_encryption = ?,
+    checksum_algorithm = ?,
+    checksum_value = ?,
+    checksum_type = ?,
     updated_at = CURRENT_TIMESTAMP
-WHERE bucket_name = ? AND key = ?;
+WHERE bucket_name = ? AND key = ? AND is_latest = 1;
 
 -- name: DeleteObject :exec
+-- Deletes the null version, the only version of an unversioned object
 DELETE FROM objects
-WHERE bucket_name = ? AND key = ?;
+WHERE bucket_name = ? AND key = ? AND version_id IS NULL;
 
 -- name: ObjectExists :one
 SELECT COUNT(*) > 0 as object_exists
 FROM objects
-WHERE bucket_name = ? AND key = ?;
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0;
 
 -- name: ListObjects :many
 SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
        server_side_encryption, version_id, created_at, updated_at
 FROM objects
-WHERE bucket_name = ?
+WHERE bucket_name = ? AND is_latest = 1 AND is_delete_marker = 0
   AND (? = '' OR key >= ?)
   AND (? = '' OR key LIKE ? || '%')
 ORDER BY key ASC
@@ -78,38 +129,15 @@ SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
        content_disposition, cache_control, expires, storage_class,
        server_side_encryption, version_id, created_at, updated_at
 FROM objects
-WHERE bucket_name = ?
+WHERE bucket_name = ? AND is_latest = 1 AND is_delete_marker = 0
   AND (? = '' OR key >= ?)
   AND (? = '' OR key LIKE ? || '%')
 ORDER BY key ASC;
 
--- name: CopyObject :one
-INSERT INTO objects (
-    bucket_name,
-    key,
-    data,
-    size,
-    etag,
-    content_type,
-    content_encoding,
-    content_disposition,
-    cache_control,
-    expires,
-    storage_class,
-    server_side_encryption
-)
-SELECT ?, ?, o.data, o.size, o.etag, o.content_type, o.content_encoding,
-       o.content_disposition, o.cache_control, o.expires, ?, ?
-FROM objects o
-WHERE o.bucket_name = ? AND o.key = ?
-RETURNING id, bucket_name, key, size, etag, content_type, content_encoding,
-          content_disposition, cache_control, expires, storage_class,
-          server_side_encryption, version_id, created_at, updated_at;
-
 -- name: GetObjectID :one
 SELECT id
 FROM objects
-WHERE bucket_name = ? AND key = ?;
+WHERE bucket_name = ? AND key = ? AND is_latest = 1 AND is_delete_marker = 0;
 
 -- Object Metadata queries
 -- name: CreateObjectMetadata :exec
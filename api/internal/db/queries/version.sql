@@ -0,0 +1,49 @@
+-- name: GetObjectVersion :one
+-- The null version is addressed by the version ID "null"
+SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
+       content_disposition, cache_control, expires, storage_class,
+       server_side_encryption, version_id, created_at, updated_at,
+       checksum_algorithm, checksum_value, checksum_type, is_latest, is_delete_marker,
+       blob_id
+FROM objects
+WHERE bucket_name = sqlc.arg('bucket_name') AND key = sqlc.arg('key')
+  AND COALESCE(version_id, 'null') = CAST(sqlc.arg('version_id') AS TEXT);
+
+-- name: GetLatestObjectVersion :one
+-- Unlike GetObjectMetadata, the current version may be a delete marker
+SELECT id, bucket_name, key, size, etag, version_id, is_delete_marker
+FROM objects
+WHERE bucket_name = ? AND key = ? AND is_latest = 1;
+
+-- name: DemoteLatestObjectVersion :exec
+UPDATE objects
+SET is_latest = 0
+WHERE bucket_name = ? AND key = ? AND is_latest = 1;
+
+-- name: PromoteLatestObjectVersion :exec
+-- Makes the most recent remaining version current after the current one was deleted
+UPDATE objects
+SET is_latest = 1
+WHERE id = (
+    SELECT o.id FROM objects o
+    WHERE o.bucket_name = sqlc.arg('bucket_name') AND o.key = sqlc.arg('key')
+    ORDER BY o.id DESC
+    LIMIT 1
+);
+
+-- name: CreateDeleteMarker :one
+INSERT INTO objects (bucket_name, key, size, etag, version_id, is_delete_marker)
+VALUES (?, ?, 0, '', ?, 1)
+RETURNING id;
+
+-- name: DeleteObjectVersionByID :exec
+DELETE FROM objects
+WHERE id = ?;
+
+-- name: ListObjectVersions :many
+SELECT id, key, size, etag, storage_class, version_id, updated_at, is_latest, is_delete_marker
+FROM objects
+WHERE bucket_name = sqlc.arg('bucket_name')
+  AND substr(key, 1, length(CAST(sqlc.arg('prefix') AS TEXT))) = CAST(sqlc.arg('prefix') AS TEXT)
+  AND key >= CAST(sqlc.arg('key_marker') AS TEXT)
+ORDER BY key ASC, id DESC;
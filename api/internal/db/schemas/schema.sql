@@ -2,7 +2,8 @@
 CREATE TABLE IF NOT EXISTS buckets (
     name TEXT PRIMARY KEY NOT NULL,
     region TEXT NOT NULL DEFAULT 'us-east-1',
-    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    versioning_status TEXT -- NULL (never enabled), 'Enabled', 'Suspended'
 );
 
 -- Create index on created_at for sorting
@@ -34,7 +35,6 @@ CREATE TABLE IF NOT EXISTS objects (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     bucket_name TEXT NOT NULL,
     key TEXT NOT NULL,
-    data BLOB,
     size INTEGER NOT NULL,
     etag TEXT NOT NULL,
     content_type TEXT NOT NULL DEFAULT 'application/octet-stream',
@@ -47,7 +47,12 @@ CREATE TABLE IF NOT EXISTS objects (
     version_id TEXT,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    UNIQUE(bucket_name, key),
+    checksum_algorithm TEXT, -- 'CRC32', 'CRC32C', 'CRC64NVME', 'SHA1', 'SHA256'
+    checksum_value TEXT, -- base64 encoded checksum
+    checksum_type TEXT, -- 'FULL_OBJECT', 'COMPOSITE'
+    is_latest BOOLEAN NOT NULL DEFAULT 1, -- current version of the key
+    is_delete_marker BOOLEAN NOT NULL DEFAULT 0,
+    blob_id TEXT, -- payload in the blob store, NULL for delete markers
     FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE
 );
 
@@ -57,6 +62,10 @@ CREATE INDEX IF NOT EXISTS idx_objects_key ON objects(key);
 CREATE INDEX IF NOT EXISTS idx_objects_bucket_key ON objects(bucket_name, key);
 CREATE INDEX IF NOT EXISTS idx_objects_updated_at ON objects(updated_at);
 
+-- A key has at most one current version and one null version
+CREATE UNIQUE INDEX IF NOT EXISTS idx_objects_latest ON objects(bucket_name, key) WHERE is_latest = 1;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_objects_version_id ON objects(bucket_name, key, COALESCE(version_id, 'null'));
+
 -- Object metadata table (for custom user metadata)
 CREATE TABLE IF NOT EXISTS object_metadata (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -81,6 +90,106 @@ CREATE TABLE IF NOT EXISTS object_tags (
 
 CREATE INDEX IF NOT EXISTS idx_object_tags_object_id ON object_tags(object_id);
 
+-- Multipart uploads table (uploads in progress)
+CREATE TABLE IF NOT EXISTS multipart_uploads (
+    upload_id TEXT PRIMARY KEY NOT NULL,
+    bucket_name TEXT NOT NULL,
+    key TEXT NOT NULL,
+    content_type TEXT NOT NULL DEFAULT 'application/octet-stream',
+    content_encoding TEXT,
+    content_disposition TEXT,
+    cache_control TEXT,
+    storage_class TEXT NOT NULL DEFAULT 'STANDARD',
+    metadata TEXT NOT NULL DEFAULT '{}', -- JSON encoded user metadata
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checksum_algorithm TEXT,
+    checksum_type TEXT,
+    tags TEXT NOT NULL DEFAULT '{}', -- JSON encoded tags
+    FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_multipart_uploads_bucket_key ON multipart_uploads(bucket_name, key);
+
+-- Multipart upload parts table
+CREATE TABLE IF NOT EXISTS multipart_upload_parts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    upload_id TEXT NOT NULL,
+    part_number INTEGER NOT NULL,
+    size INTEGER NOT NULL,
+    etag TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checksum_value TEXT,
+    blob_id TEXT, -- payload in the blob store
+    FOREIGN KEY (upload_id) REFERENCES multipart_uploads(upload_id) ON DELETE CASCADE,
+    UNIQUE(upload_id, part_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_multipart_upload_parts_upload_id ON multipart_upload_parts(upload_id);
+
+-- Object parts table (part layout of objects created by CompleteMultipartUpload)
+CREATE TABLE IF NOT EXISTS object_parts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    object_id INTEGER NOT NULL,
+    part_number INTEGER NOT NULL,
+    size INTEGER NOT NULL,
+    etag TEXT NOT NULL,
+    checksum_value TEXT,
+    FOREIGN KEY (object_id) REFERENCES objects(id) ON DELETE CASCADE,
+    UNIQUE(object_id, part_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_object_parts_object_id ON object_parts(object_id);
+
+-- Complete blobs of the SQLite blob store
+CREATE TABLE IF NOT EXISTS blobs (
+    id TEXT PRIMARY KEY NOT NULL,
+    size INTEGER NOT NULL DEFAULT 0
+);
+
+-- Blob payloads of the SQLite blob store, split into chunks
+CREATE TABLE IF NOT EXISTS blob_chunks (
+    blob_id TEXT NOT NULL,
+    position INTEGER NOT NULL, -- byte offset of the chunk in the blob
+    data BLOB NOT NULL,
+    PRIMARY KEY (blob_id, position)
+);
+
+-- Blobs no longer referenced by any row, removed from the blob store after
+-- the transaction that released them commits
+CREATE TABLE IF NOT EXISTS released_blobs (
+    blob_id TEXT PRIMARY KEY NOT NULL,
+    released_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Triggers to release blobs when their row is deleted or pointed at a new blob
+CREATE TRIGGER IF NOT EXISTS release_object_blob_on_delete
+AFTER DELETE ON objects
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS release_object_blob_on_update
+AFTER UPDATE OF blob_id ON objects
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL AND OLD.blob_id IS NOT NEW.blob_id
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS release_part_blob_on_delete
+AFTER DELETE ON multipart_upload_parts
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS release_part_blob_on_update
+AFTER UPDATE OF blob_id ON multipart_upload_parts
+FOR EACH ROW WHEN OLD.blob_id IS NOT NULL AND OLD.blob_id IS NOT NEW.blob_id
+BEGIN
+    INSERT OR IGNORE INTO released_blobs (blob_id) VALUES (OLD.blob_id);
+END;
+
 -- S3 notifications table
 CREATE TABLE IF NOT EXISTS notifications (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -93,6 +202,7 @@ CREATE TABLE IF NOT EXISTS notifications (
     enabled BOOLEAN NOT NULL DEFAULT 1,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    managed_by TEXT NOT NULL DEFAULT 'api', -- 'api', or 'config' for rules of config.yaml
     FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE
 );
 
@@ -105,11 +215,16 @@ CREATE INDEX IF NOT EXISTS idx_notifications_enabled ON notifications(enabled);
 CREATE TABLE IF NOT EXISTS events (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     bucket_name TEXT NOT NULL,
-    object_id INTEGER NOT NULL,
+    object_id INTEGER,
     event_type TEXT NOT NULL,
     event_time DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Snapshot of the object when the event occurred
+    object_key TEXT NOT NULL DEFAULT '',
+    object_size INTEGER NOT NULL DEFAULT 0,
+    object_etag TEXT NOT NULL DEFAULT '',
+    object_version_id TEXT,
     FOREIGN KEY (bucket_name) REFERENCES buckets(name) ON DELETE CASCADE,
-    FOREIGN KEY (object_id) REFERENCES objects(id) ON DELETE CASCADE
+    FOREIGN KEY (object_id) REFERENCES objects(id) ON DELETE SET NULL
 );
 
 -- Indexes for notification events table
@@ -122,11 +237,20 @@ CREATE TABLE IF NOT EXISTS notification_jobs (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     event_id INTEGER NOT NULL,
     notification_id INTEGER NOT NULL,
-    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'completed', 'failed'
+    status TEXT NOT NULL DEFAULT 'pending', -- 'pending', 'completed', 'dead_letter', 'cancelled'
     attempts INTEGER NOT NULL DEFAULT 0,
     error_message TEXT,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- When a pending job is due; NULL for jobs that were never attempted
+    next_attempt_at DATETIME,
+    -- Lease of the worker delivering the job
+    claimed_until DATETIME,
+    -- Status and beginning of the body of the last response
+    response_status INTEGER,
+    response_body TEXT,
+    -- JSON array of the targets a job sent to several targets was delivered to
+    delivered_targets TEXT,
     FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE CASCADE,
     FOREIGN KEY (notification_id) REFERENCES notifications(id) ON DELETE CASCADE
 );
@@ -136,18 +260,4 @@ CREATE INDEX IF NOT EXISTS idx_notification_jobs_event_id ON notification_jobs(e
 CREATE INDEX IF NOT EXISTS idx_notification_jobs_notification_id ON notification_jobs(notification_id);
 CREATE INDEX IF NOT EXISTS idx_notification_jobs_status ON notification_jobs(status);
 CREATE INDEX IF NOT EXISTS idx_notification_jobs_created_at ON notification_jobs(created_at);
-
--- Trigger to automatically create notification jobs when an event is inserted
-CREATE TRIGGER IF NOT EXISTS create_notification_jobs_on_event_insert
-AFTER INSERT ON events
-FOR EACH ROW
-BEGIN
-    INSERT INTO notification_jobs (event_id, notification_id)
-    SELECT
-        NEW.id,
-        n.id
-    FROM notifications n
-    WHERE n.bucket_name = NEW.bucket_name
-      AND n.event_type = NEW.event_type
-      AND n.enabled = 1;
-END;
+CREATE INDEX IF NOT EXISTS idx_notification_jobs_next_attempt_at ON notification_jobs(status, next_attempt_at);
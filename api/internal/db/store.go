@@ -3,19 +3,34 @@ package db
 import (
 	"context"
 	"database/sql"
+	"io"
+	"log"
+
+	"github.com/tkasuz/s3local/internal/blob"
 )
 
+// DataSourceName returns the go-sqlite3 data source name of the database file
+// at path. Transactions take the write lock when they begin (BEGIN IMMEDIATE):
+// a transaction that reads before it writes would otherwise fail with
+// SQLITE_BUSY when another one writes first, instead of waiting for it.
+func DataSourceName(path string) string {
+	return path + "?_foreign_keys=on&_txlock=immediate"
+}
+
 // Store wraps database connection and queries for easy transaction support
 type Store struct {
 	DB      *sql.DB
 	Queries *Queries
+	// Blobs holds object and part payloads referenced by blob_id
+	Blobs blob.Store
 }
 
 // NewStore creates a new Store
-func NewStore(database *sql.DB, queries *Queries) *Store {
+func NewStore(database *sql.DB, queries *Queries, blobs blob.Store) *Store {
 	return &Store{
 		DB:      database,
 		Queries: queries,
+		Blobs:   blobs,
 	}
 }
 
@@ -35,5 +50,26 @@ func (s *Store) ExecTx(ctx context.Context, fn func(*Queries) error) error {
 		return err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Blobs released by the transaction are deleted once it is committed
+	if err := s.ReleaseBlobs(ctx); err != nil {
+		log.Printf("Failed to release blobs: %v", err)
+	}
+	return nil
+}
+
+// PutBlob writes r to the blob store under a new blob ID
+func (s *Store) PutBlob(ctx context.Context, r io.Reader) (string, int64, error) {
+	id, err := blob.NewID()
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := s.Blobs.Put(ctx, id, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return id, n, nil
 }
@@ -0,0 +1,223 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: version.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const CreateDeleteMarker = `-- name: CreateDeleteMarker :one
+INSERT INTO objects (bucket_name, key, size, etag, version_id, is_delete_marker)
+VALUES (?, ?, 0, '', ?, 1)
+RETURNING id
+`
+
+type CreateDeleteMarkerParams struct {
+	BucketName string         `json:"bucket_name"`
+	Key        string         `json:"key"`
+	VersionID  sql.NullString `json:"version_id"`
+}
+
+func (q *Queries) CreateDeleteMarker(ctx context.Context, arg CreateDeleteMarkerParams) (int64, error) {
+	row := q.queryRow(ctx, q.createDeleteMarkerStmt, CreateDeleteMarker, arg.BucketName, arg.Key, arg.VersionID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const DeleteObjectVersionByID = `-- name: DeleteObjectVersionByID :exec
+DELETE FROM objects
+WHERE id = ?
+`
+
+func (q *Queries) DeleteObjectVersionByID(ctx context.Context, id int64) error {
+	_, err := q.exec(ctx, q.deleteObjectVersionByIDStmt, DeleteObjectVersionByID, id)
+	return err
+}
+
+const DemoteLatestObjectVersion = `-- name: DemoteLatestObjectVersion :exec
+UPDATE objects
+SET is_latest = 0
+WHERE bucket_name = ? AND key = ? AND is_latest = 1
+`
+
+type DemoteLatestObjectVersionParams struct {
+	BucketName string `json:"bucket_name"`
+	Key        string `json:"key"`
+}
+
+func (q *Queries) DemoteLatestObjectVersion(ctx context.Context, arg DemoteLatestObjectVersionParams) error {
+	_, err := q.exec(ctx, q.demoteLatestObjectVersionStmt, DemoteLatestObjectVersion, arg.BucketName, arg.Key)
+	return err
+}
+
+const GetLatestObjectVersion = `-- name: GetLatestObjectVersion :one
+SELECT id, bucket_name, key, size, etag, version_id, is_delete_marker
+FROM objects
+WHERE bucket_name = ? AND key = ? AND is_latest = 1
+`
+
+type GetLatestObjectVersionParams struct {
+	BucketName string `json:"bucket_name"`
+	Key        string `json:"key"`
+}
+
+type GetLatestObjectVersionRow struct {
+	ID             int64          `json:"id"`
+	BucketName     string         `json:"bucket_name"`
+	Key            string         `json:"key"`
+	Size           int64          `json:"size"`
+	ETag           string         `json:"etag"`
+	VersionID      sql.NullString `json:"version_id"`
+	IsDeleteMarker bool           `json:"is_delete_marker"`
+}
+
+// Unlike GetObjectMetadata, the current version may be a delete marker
+func (q *Queries) GetLatestObjectVersion(ctx context.Context, arg GetLatestObjectVersionParams) (GetLatestObjectVersionRow, error) {
+	row := q.queryRow(ctx, q.getLatestObjectVersionStmt, GetLatestObjectVersion, arg.BucketName, arg.Key)
+	var i GetLatestObjectVersionRow
+	err := row.Scan(
+		&i.ID,
+		&i.BucketName,
+		&i.Key,
+		&i.Size,
+		&i.ETag,
+		&i.VersionID,
+		&i.IsDeleteMarker,
+	)
+	return i, err
+}
+
+const GetObjectVersion = `-- name: GetObjectVersion :one
+SELECT id, bucket_name, key, size, etag, content_type, content_encoding,
+       content_disposition, cache_control, expires, storage_class,
+       server_side_encryption, version_id, created_at, updated_at,
+       checksum_algorithm, checksum_value, checksum_type, is_latest, is_delete_marker,
+       blob_id
+FROM objects
+WHERE bucket_name = ?1 AND key = ?2
+  AND COALESCE(version_id, 'null') = CAST(?3 AS TEXT)
+`
+
+type GetObjectVersionParams struct {
+	BucketName string `json:"bucket_name"`
+	Key        string `json:"key"`
+	VersionID  string `json:"version_id"`
+}
+
+// The null version is addressed by the version ID "null"
+func (q *Queries) GetObjectVersion(ctx context.Context, arg GetObjectVersionParams) (Object, error) {
+	row := q.queryRow(ctx, q.getObjectVersionStmt, GetObjectVersion, arg.BucketName, arg.Key, arg.VersionID)
+	var i Object
+	err := row.Scan(
+		&i.ID,
+		&i.BucketName,
+		&i.Key,
+		&i.Size,
+		&i.ETag,
+		&i.ContentType,
+		&i.ContentEncoding,
+		&i.ContentDisposition,
+		&i.CacheControl,
+		&i.Expires,
+		&i.StorageClass,
+		&i.ServerSideEncryption,
+		&i.VersionID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ChecksumAlgorithm,
+		&i.ChecksumValue,
+		&i.ChecksumType,
+		&i.IsLatest,
+		&i.IsDeleteMarker,
+		&i.BlobID,
+	)
+	return i, err
+}
+
+const ListObjectVersions = `-- name: ListObjectVersions :many
+SELECT id, key, size, etag, storage_class, version_id, updated_at, is_latest, is_delete_marker
+FROM objects
+WHERE bucket_name = ?1
+  AND substr(key, 1, length(CAST(?2 AS TEXT))) = CAST(?2 AS TEXT)
+  AND key >= CAST(?3 AS TEXT)
+ORDER BY key ASC, id DESC
+`
+
+type ListObjectVersionsParams struct {
+	BucketName string `json:"bucket_name"`
+	Prefix     string `json:"prefix"`
+	KeyMarker  string `json:"key_marker"`
+}
+
+type ListObjectVersionsRow struct {
+	ID             int64          `json:"id"`
+	Key            string         `json:"key"`
+	Size           int64          `json:"size"`
+	ETag           string         `json:"etag"`
+	StorageClass   string         `json:"storage_class"`
+	VersionID      sql.NullString `json:"version_id"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	IsLatest       bool           `json:"is_latest"`
+	IsDeleteMarker bool           `json:"is_delete_marker"`
+}
+
+func (q *Queries) ListObjectVersions(ctx context.Context, arg ListObjectVersionsParams) ([]ListObjectVersionsRow, error) {
+	rows, err := q.query(ctx, q.listObjectVersionsStmt, ListObjectVersions, arg.BucketName, arg.Prefix, arg.KeyMarker)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListObjectVersionsRow{}
+	for rows.Next() {
+		var i ListObjectVersionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Key,
+			&i.Size,
+			&i.ETag,
+			&i.StorageClass,
+			&i.VersionID,
+			&i.UpdatedAt,
+			&i.IsLatest,
+			&i.IsDeleteMarker,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const PromoteLatestObjectVersion = `-- name: PromoteLatestObjectVersion :exec
+UPDATE objects
+SET is_latest = 1
+WHERE id = (
+    SELECT o.id FROM objects o
+    WHERE o.bucket_name = ?1 AND o.key = ?2
+    ORDER BY o.id DESC
+    LIMIT 1
+)
+`
+
+type PromoteLatestObjectVersionParams struct {
+	BucketName string `json:"bucket_name"`
+	Key        string `json:"key"`
+}
+
+// Makes the most recent remaining version current after the current one was deleted
+func (q *Queries) PromoteLatestObjectVersion(ctx context.Context, arg PromoteLatestObjectVersionParams) error {
+	_, err := q.exec(ctx, q.promoteLatestObjectVersionStmt, PromoteLatestObjectVersion, arg.BucketName, arg.Key)
+	return err
+}
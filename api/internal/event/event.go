@@ -0,0 +1,117 @@
+// Package event defines the S3 event notification types recorded by the
+// object handlers and matches them against bucket notification rules.
+package event
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Event types as configured in bucket notification configurations
+const (
+	ObjectCreatedPut                     = "s3:ObjectCreated:Put"
+	ObjectCreatedCopy                    = "s3:ObjectCreated:Copy"
+	ObjectCreatedCompleteMultipartUpload = "s3:ObjectCreated:CompleteMultipartUpload"
+
+	ObjectRemovedDelete              = "s3:ObjectRemoved:Delete"
+	ObjectRemovedDeleteMarkerCreated = "s3:ObjectRemoved:DeleteMarkerCreated"
+
+	ObjectTaggingPut    = "s3:ObjectTagging:Put"
+	ObjectTaggingDelete = "s3:ObjectTagging:Delete"
+)
+
+// TestEvent is sent to queue and topic destinations when a notification
+// configuration is saved. It cannot be configured.
+const TestEvent = "s3:TestEvent"
+
+// supportedTypes lists the event types accepted in notification
+// configurations. It includes types S3Local never emits so that
+// configurations written for S3 are accepted as they are.
+var supportedTypes = map[string]bool{
+	"s3:ObjectCreated:*":                               true,
+	ObjectCreatedPut:                                   true,
+	"s3:ObjectCreated:Post":                            true,
+	ObjectCreatedCopy:                                  true,
+	ObjectCreatedCompleteMultipartUpload:               true,
+	"s3:ObjectRemoved:*":                               true,
+	ObjectRemovedDelete:                                true,
+	ObjectRemovedDeleteMarkerCreated:                   true,
+	"s3:ObjectRestore:*":                               true,
+	"s3:ObjectRestore:Post":                            true,
+	"s3:ObjectRestore:Completed":                       true,
+	"s3:ObjectRestore:Delete":                          true,
+	"s3:ReducedRedundancyLostObject":                   true,
+	"s3:Replication:*":                                 true,
+	"s3:Replication:OperationFailedReplication":        true,
+	"s3:Replication:OperationMissedThreshold":          true,
+	"s3:Replication:OperationReplicatedAfterThreshold": true,
+	"s3:Replication:OperationNotTracked":               true,
+	"s3:LifecycleExpiration:*":                         true,
+	"s3:LifecycleExpiration:Delete":                    true,
+	"s3:LifecycleExpiration:DeleteMarkerCreated":       true,
+	"s3:LifecycleTransition":                           true,
+	"s3:IntelligentTiering":                            true,
+	"s3:ObjectTagging:*":                               true,
+	ObjectTaggingPut:                                   true,
+	ObjectTaggingDelete:                                true,
+	"s3:ObjectAcl:Put":                                 true,
+}
+
+// Supported reports whether eventType may be used in a notification configuration
+func Supported(eventType string) bool {
+	return supportedTypes[eventType]
+}
+
+// Match reports whether the configured event type pattern, either an event
+// type or a family such as s3:ObjectCreated:*, matches eventType
+func Match(pattern, eventType string) bool {
+	if family, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(eventType, family)
+	}
+	return pattern == eventType
+}
+
+// Overlap reports whether two configured event type patterns match a common
+// event type
+func Overlap(a, b string) bool {
+	return Match(a, b) || Match(b, a)
+}
+
+// MatchKey reports whether key passes the prefix and suffix filters of a
+// notification rule. Filters apply to the object key as stored, not to the
+// URL-encoded key of the event record.
+func MatchKey(prefix, suffix, key string) bool {
+	return strings.HasPrefix(key, prefix) && strings.HasSuffix(key, suffix)
+}
+
+// Rule selects the events of a notification rule: an event type pattern and
+// the prefix and suffix filters of the object key
+type Rule struct {
+	EventType string
+	Prefix    string
+	Suffix    string
+}
+
+// RulesOverlap reports whether an object event could match both rules: their
+// event type patterns match a common event type and some key passes both
+// prefix and suffix filters
+func RulesOverlap(a, b Rule) bool {
+	if !Overlap(a.EventType, b.EventType) {
+		return false
+	}
+	prefixesOverlap := strings.HasPrefix(a.Prefix, b.Prefix) || strings.HasPrefix(b.Prefix, a.Prefix)
+	suffixesOverlap := strings.HasSuffix(a.Suffix, b.Suffix) || strings.HasSuffix(b.Suffix, a.Suffix)
+	return prefixesOverlap && suffixesOverlap
+}
+
+// RecordName returns the eventName of an S3 event record, which omits the
+// "s3:" prefix of the event type (e.g. ObjectCreated:Put)
+func RecordName(eventType string) string {
+	return strings.TrimPrefix(eventType, "s3:")
+}
+
+// RecordKey returns the object key of an S3 event record, which is URL
+// encoded with spaces as "+" and slashes left as they are
+func RecordKey(key string) string {
+	return strings.ReplaceAll(url.QueryEscape(key), "%2F", "/")
+}
@@ -0,0 +1,54 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Match("s3:ObjectCreated:Put", ObjectCreatedPut))
+	assert.True(t, Match("s3:ObjectCreated:*", ObjectCreatedPut))
+	assert.True(t, Match("s3:ObjectCreated:*", ObjectCreatedCompleteMultipartUpload))
+	assert.True(t, Match("s3:ObjectRemoved:*", ObjectRemovedDeleteMarkerCreated))
+	assert.False(t, Match("s3:ObjectCreated:Put", ObjectCreatedCopy))
+	assert.False(t, Match("s3:ObjectCreated:*", ObjectRemovedDelete))
+	assert.False(t, Match("s3:ObjectTagging:*", ObjectCreatedPut))
+
+	assert.True(t, Overlap("s3:ObjectCreated:Put", "s3:ObjectCreated:*"))
+	assert.False(t, Overlap("s3:ObjectCreated:*", "s3:ObjectRemoved:*"))
+}
+
+func TestMatchKey(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, MatchKey("", "", "any/key"))
+	assert.True(t, MatchKey("images/", ".jpg", "images/cat.jpg"))
+	assert.False(t, MatchKey("images/", ".jpg", "images/cat.png"))
+	assert.False(t, MatchKey("images/", ".jpg", "videos/cat.jpg"))
+
+	// Filters match the key, not its URL-encoded form
+	assert.True(t, MatchKey("my photos/", "", "my photos/cat.jpg"))
+	assert.False(t, MatchKey("my+photos/", "", "my photos/cat.jpg"))
+}
+
+func TestRulesOverlap(t *testing.T) {
+	t.Parallel()
+
+	created := Rule{EventType: "s3:ObjectCreated:*"}
+	assert.True(t, RulesOverlap(created, Rule{EventType: ObjectCreatedPut, Prefix: "images/"}))
+	assert.True(t, RulesOverlap(Rule{EventType: ObjectCreatedPut, Prefix: "images/"}, Rule{EventType: ObjectCreatedPut, Suffix: ".jpg"}))
+	assert.False(t, RulesOverlap(created, Rule{EventType: ObjectRemovedDelete}))
+	assert.False(t, RulesOverlap(Rule{EventType: ObjectCreatedPut, Prefix: "images/"}, Rule{EventType: ObjectCreatedPut, Prefix: "videos/"}))
+	assert.False(t, RulesOverlap(Rule{EventType: ObjectCreatedPut, Suffix: ".jpg"}, Rule{EventType: ObjectCreatedPut, Suffix: ".png"}))
+}
+
+func TestRecordKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "images/cat.jpg", RecordKey("images/cat.jpg"))
+	assert.Equal(t, "my+photos/caf%C3%A9%3D1.jpg", RecordKey("my photos/café=1.jpg"))
+	assert.Equal(t, "ObjectCreated:Put", RecordName(ObjectCreatedPut))
+}
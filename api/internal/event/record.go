@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+// Record inserts an event and queues a notification job for every enabled
+// notification rule of the bucket that matches its type and object key
+func Record(ctx context.Context, q *db.Queries, arg db.CreateEventParams) (db.Event, error) {
+	e, err := q.CreateEvent(ctx, arg)
+	if err != nil {
+		return db.Event{}, err
+	}
+
+	notifications, err := q.ListEnabledNotificationsByBucket(ctx, arg.BucketName)
+	if err != nil {
+		return db.Event{}, err
+	}
+	for _, n := range notifications {
+		if !Match(n.EventType, e.EventType) || !MatchKey(n.FilterPrefix.String, n.FilterSuffix.String, e.ObjectKey) {
+			continue
+		}
+		if err := q.CreateNotificationJob(ctx, db.CreateNotificationJobParams{
+			EventID:        e.ID,
+			NotificationID: n.ID,
+		}); err != nil {
+			return db.Event{}, err
+		}
+	}
+	return e, nil
+}
+
+// RecordTest inserts an s3:TestEvent for the bucket of notification n and
+// queues its delivery to the destination of n
+func RecordTest(ctx context.Context, q *db.Queries, n db.Notification) error {
+	e, err := q.CreateEvent(ctx, db.CreateEventParams{
+		BucketName: n.BucketName,
+		EventType:  TestEvent,
+	})
+	if err != nil {
+		return err
+	}
+	return q.CreateNotificationJob(ctx, db.CreateNotificationJobParams{
+		EventID:        e.ID,
+		NotificationID: n.ID,
+	})
+}
@@ -0,0 +1,77 @@
+package event_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	notificationIDs := make(map[string]int64)
+	for _, rule := range []db.CreateNotificationParams{
+		{EventType: "s3:ObjectCreated:*", DestinationArn: "all-created"},
+		{EventType: event.ObjectCreatedPut, DestinationArn: "jpg", FilterSuffix: sql.NullString{String: ".jpg", Valid: true}},
+		{EventType: event.ObjectCreatedPut, DestinationArn: "photos", FilterPrefix: sql.NullString{String: "my photos/", Valid: true}},
+		{EventType: "s3:ObjectRemoved:*", DestinationArn: "removed"},
+	} {
+		rule.BucketName = "test-bucket"
+		rule.DestinationType = "lambda"
+		rule.Enabled = true
+		n, err := store.Queries.CreateNotification(context.Background(), rule)
+		require.NoError(t, err)
+		notificationIDs[rule.DestinationArn] = n.ID
+	}
+
+	matched := func(t *testing.T, eventType, key string) []string {
+		var destinations []string
+		err := store.ExecTx(context.Background(), func(q *db.Queries) error {
+			e, err := event.Record(context.Background(), q, db.CreateEventParams{
+				BucketName: "test-bucket",
+				EventType:  eventType,
+				ObjectKey:  key,
+			})
+			if err != nil {
+				return err
+			}
+			jobs, err := q.ListNotificationJobsByEvent(context.Background(), e.ID)
+			if err != nil {
+				return err
+			}
+			for _, job := range jobs {
+				destinations = append(destinations, job.Notification.DestinationArn)
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return destinations
+	}
+
+	assert.ElementsMatch(t, []string{"all-created", "jpg", "photos"}, matched(t, event.ObjectCreatedPut, "my photos/cat.jpg"))
+	assert.ElementsMatch(t, []string{"all-created"}, matched(t, event.ObjectCreatedCopy, "my photos/cat.jpg"))
+	assert.ElementsMatch(t, []string{"all-created", "jpg"}, matched(t, event.ObjectCreatedPut, "cat.jpg"))
+	assert.ElementsMatch(t, []string{"removed"}, matched(t, event.ObjectRemovedDeleteMarkerCreated, "cat.png"))
+	assert.Empty(t, matched(t, event.ObjectTaggingPut, "cat.jpg"))
+
+	// Disabled rules are skipped
+	require.NoError(t, store.Queries.UpdateNotificationEnabled(context.Background(), db.UpdateNotificationEnabledParams{
+		Enabled: false,
+		ID:      notificationIDs["removed"],
+	}))
+	assert.Empty(t, matched(t, event.ObjectRemovedDelete, "cat.png"))
+}
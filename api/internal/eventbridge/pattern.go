@@ -0,0 +1,272 @@
+// Package eventbridge matches events against Amazon EventBridge event
+// patterns, so that routing rules can be tested without an event bus.
+package eventbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Pattern is a parsed EventBridge event pattern. Fields map to lists of
+// values or content filters (prefix, suffix, equals-ignore-case, wildcard,
+// anything-but, numeric, exists and cidr), or to nested patterns.
+type Pattern struct {
+	fields map[string]any
+}
+
+// ParsePattern parses a JSON event pattern
+func ParsePattern(data []byte) (*Pattern, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("invalid event pattern: %w", err)
+	}
+	if err := validateFields(fields); err != nil {
+		return nil, fmt.Errorf("invalid event pattern: %w", err)
+	}
+	return &Pattern{fields: fields}, nil
+}
+
+// Match reports whether the JSON event matches the pattern
+func (p *Pattern) Match(event []byte) (bool, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(event, &fields); err != nil {
+		return false, err
+	}
+	return matchFields(p.fields, fields), nil
+}
+
+func validateFields(fields map[string]any) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("empty pattern")
+	}
+	for name, value := range fields {
+		switch value := value.(type) {
+		case map[string]any:
+			if err := validateFields(value); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		case []any:
+			if len(value) == 0 {
+				return fmt.Errorf("%s: empty list of values", name)
+			}
+			for _, matcher := range value {
+				if filter, ok := matcher.(map[string]any); ok {
+					if err := validateFilter(filter); err != nil {
+						return fmt.Errorf("%s: %w", name, err)
+					}
+				}
+			}
+		default:
+			return fmt.Errorf("%s: values must be in a list", name)
+		}
+	}
+	return nil
+}
+
+func validateFilter(filter map[string]any) error {
+	if len(filter) != 1 {
+		return fmt.Errorf("a content filter has a single operator")
+	}
+	for operator, operand := range filter {
+		var valid bool
+		switch operator {
+		case "prefix", "suffix", "equals-ignore-case", "wildcard":
+			_, valid = operand.(string)
+		case "exists":
+			_, valid = operand.(bool)
+		case "anything-but":
+			switch operand := operand.(type) {
+			case string, float64, []any:
+				valid = true
+			case map[string]any:
+				valid = validateFilter(operand) == nil
+			}
+		case "numeric":
+			_, valid = parseNumeric(operand)
+		case "cidr":
+			s, ok := operand.(string)
+			_, _, err := net.ParseCIDR(s)
+			valid = ok && err == nil
+		default:
+			return fmt.Errorf("unknown content filter %q", operator)
+		}
+		if !valid {
+			return fmt.Errorf("invalid operand of %q", operator)
+		}
+	}
+	return nil
+}
+
+// matchFields reports whether the event fields match every field of the
+// pattern
+func matchFields(pattern, fields map[string]any) bool {
+	for name, value := range pattern {
+		field, present := fields[name]
+		switch value := value.(type) {
+		case map[string]any:
+			nested, ok := field.(map[string]any)
+			if !ok || !matchFields(value, nested) {
+				return false
+			}
+		case []any:
+			if !matchAny(value, field, present) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchAny reports whether one of the matchers matches the field. A field
+// holding a list matches when one of its elements does.
+func matchAny(matchers []any, field any, present bool) bool {
+	values := []any{field}
+	if list, ok := field.([]any); ok {
+		values = list
+	}
+	for _, matcher := range matchers {
+		if filter, ok := matcher.(map[string]any); ok {
+			if exists, ok := filter["exists"].(bool); ok {
+				if exists == present {
+					return true
+				}
+				continue
+			}
+		}
+		if !present {
+			continue
+		}
+		for _, value := range values {
+			if matchValue(matcher, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchValue reports whether a single value matches an exact value or a
+// content filter
+func matchValue(matcher, value any) bool {
+	filter, ok := matcher.(map[string]any)
+	if !ok {
+		return matcher == value
+	}
+	s, isString := value.(string)
+	for operator, operand := range filter {
+		switch operator {
+		case "prefix":
+			return isString && strings.HasPrefix(s, operand.(string))
+		case "suffix":
+			return isString && strings.HasSuffix(s, operand.(string))
+		case "equals-ignore-case":
+			return isString && strings.EqualFold(s, operand.(string))
+		case "wildcard":
+			return isString && matchWildcard(operand.(string), s)
+		case "anything-but":
+			return !matchAnythingBut(operand, value)
+		case "numeric":
+			n, ok := value.(float64)
+			if !ok {
+				return false
+			}
+			conditions, _ := parseNumeric(operand)
+			for _, c := range conditions {
+				if !c.match(n) {
+					return false
+				}
+			}
+			return true
+		case "cidr":
+			_, network, _ := net.ParseCIDR(operand.(string))
+			ip := net.ParseIP(s)
+			return isString && ip != nil && network.Contains(ip)
+		}
+	}
+	return false
+}
+
+// matchAnythingBut reports whether value is one of the excluded values
+func matchAnythingBut(excluded, value any) bool {
+	switch excluded := excluded.(type) {
+	case []any:
+		for _, e := range excluded {
+			if e == value {
+				return true
+			}
+		}
+		return false
+	case map[string]any:
+		return matchValue(excluded, value)
+	default:
+		return excluded == value
+	}
+}
+
+// matchWildcard reports whether s matches pattern, in which * matches any run
+// of characters
+func matchWildcard(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 {
+			return strings.HasSuffix(s, part)
+		}
+		idx := strings.Index(s, part)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(part):]
+	}
+	return s == ""
+}
+
+// numericCondition is one comparison of a numeric filter
+type numericCondition struct {
+	operator string
+	operand  float64
+}
+
+func (c numericCondition) match(n float64) bool {
+	switch c.operator {
+	case "<":
+		return n < c.operand
+	case "<=":
+		return n <= c.operand
+	case "=":
+		return n == c.operand
+	case ">=":
+		return n >= c.operand
+	case ">":
+		return n > c.operand
+	}
+	return false
+}
+
+// parseNumeric parses the operand of a numeric filter, e.g. [">", 0, "<=", 5]
+func parseNumeric(operand any) ([]numericCondition, bool) {
+	list, ok := operand.([]any)
+	if !ok || len(list) == 0 || len(list)%2 != 0 {
+		return nil, false
+	}
+	var conditions []numericCondition
+	for i := 0; i < len(list); i += 2 {
+		operator, ok := list[i].(string)
+		n, isNumber := list[i+1].(float64)
+		if !ok || !isNumber {
+			return nil, false
+		}
+		switch operator {
+		case "<", "<=", "=", ">=", ">":
+		default:
+			return nil, false
+		}
+		conditions = append(conditions, numericCondition{operator: operator, operand: n})
+	}
+	return conditions, true
+}
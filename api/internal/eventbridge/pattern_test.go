@@ -0,0 +1,64 @@
+package eventbridge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const objectCreated = `{
+	"source": "aws.s3",
+	"detail-type": "Object Created",
+	"resources": ["arn:aws:s3:::test-bucket"],
+	"detail": {
+		"bucket": {"name": "test-bucket"},
+		"object": {"key": "images/cat.png", "size": 1024},
+		"reason": "PutObject",
+		"source-ip-address": "10.0.1.7"
+	}
+}`
+
+func TestPattern(t *testing.T) {
+	t.Parallel()
+
+	for pattern, expected := range map[string]bool{
+		`{"source": ["aws.s3"]}`:                                                true,
+		`{"source": ["aws.ec2"]}`:                                               false,
+		`{"detail-type": ["Object Created", "Object Deleted"]}`:                 true,
+		`{"resources": ["arn:aws:s3:::test-bucket"]}`:                           true,
+		`{"detail": {"bucket": {"name": ["test-bucket"]}}}`:                     true,
+		`{"detail": {"bucket": {"name": ["other-bucket"]}}}`:                    false,
+		`{"detail": {"object": {"key": [{"prefix": "images/"}]}}}`:              true,
+		`{"detail": {"object": {"key": [{"suffix": ".jpg"}]}}}`:                 false,
+		`{"detail": {"object": {"key": [{"wildcard": "*/*.png"}]}}}`:            true,
+		`{"detail": {"object": {"key": [{"wildcard": "*.jpg"}]}}}`:              false,
+		`{"detail": {"reason": [{"equals-ignore-case": "putobject"}]}}`:         true,
+		`{"detail": {"reason": [{"anything-but": ["CopyObject"]}]}}`:            true,
+		`{"detail": {"reason": [{"anything-but": {"prefix": "Put"}}]}}`:         false,
+		`{"detail": {"object": {"size": [{"numeric": [">", 0, "<=", 1024]}]}}}`: true,
+		`{"detail": {"object": {"size": [{"numeric": [">", 1024]}]}}}`:          false,
+		`{"detail": {"object": {"version-id": [{"exists": false}]}}}`:           true,
+		`{"detail": {"object": {"key": [{"exists": true}]}}}`:                   true,
+		`{"detail": {"source-ip-address": [{"cidr": "10.0.0.0/16"}]}}`:          true,
+		// Every field of the pattern must match
+		`{"source": ["aws.s3"], "detail-type": ["Object Deleted"]}`: false,
+	} {
+		p, err := ParsePattern([]byte(pattern))
+		require.NoError(t, err, pattern)
+		matched, err := p.Match([]byte(objectCreated))
+		require.NoError(t, err)
+		assert.Equal(t, expected, matched, pattern)
+	}
+
+	for _, pattern := range []string{
+		`{}`,
+		`{"source": "aws.s3"}`,
+		`{"source": []}`,
+		`{"detail": {"object": {"key": [{"regex": ".*"}]}}}`,
+		`{"detail": {"object": {"size": [{"numeric": [">"]}]}}}`,
+	} {
+		_, err := ParsePattern([]byte(pattern))
+		assert.Error(t, err, pattern)
+	}
+}
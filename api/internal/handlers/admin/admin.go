@@ -0,0 +1,116 @@
+// Package admin implements the S3Local specific HTTP API below /_s3local,
+// used to inspect and control notification delivery. Unlike the S3 API it
+// speaks JSON.
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Notification job statuses
+const (
+	statusPending    = "pending"
+	statusCompleted  = "completed"
+	statusDeadLetter = "dead_letter"
+	statusCancelled  = "cancelled"
+)
+
+// Number of entries returned by list requests by default and at most
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// sqliteTimeFormat is the format of CURRENT_TIMESTAMP, which timestamps are
+// compared with
+const sqliteTimeFormat = "2006-01-02 15:04:05"
+
+// writeJSON writes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response of the form {"error": "..."}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// filter holds the query parameters of list requests
+type filter struct {
+	bucket sql.NullString
+	status sql.NullString
+	since  sql.NullString
+	until  sql.NullString
+	limit  int64
+	offset int64
+}
+
+// parseFilter parses the bucket, status, since, until, limit and offset query
+// parameters. since and until are RFC 3339 times.
+func parseFilter(query url.Values) (filter, error) {
+	f := filter{
+		bucket: nullString(query.Get("bucket")),
+		limit:  defaultLimit,
+	}
+
+	var err error
+	if f.status, err = parseStatus(query.Get("status")); err != nil {
+		return filter{}, err
+	}
+	if f.since, err = parseTime("since", query.Get("since")); err != nil {
+		return filter{}, err
+	}
+	if f.until, err = parseTime("until", query.Get("until")); err != nil {
+		return filter{}, err
+	}
+	if limit := query.Get("limit"); limit != "" {
+		f.limit, err = strconv.ParseInt(limit, 10, 64)
+		if err != nil || f.limit < 1 || f.limit > maxLimit {
+			return filter{}, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+	}
+	if offset := query.Get("offset"); offset != "" {
+		f.offset, err = strconv.ParseInt(offset, 10, 64)
+		if err != nil || f.offset < 0 {
+			return filter{}, fmt.Errorf("invalid offset %q", offset)
+		}
+	}
+	return f, nil
+}
+
+// parseStatus validates a job status given as a query parameter
+func parseStatus(status string) (sql.NullString, error) {
+	switch status {
+	case "":
+		return sql.NullString{}, nil
+	case statusPending, statusCompleted, statusDeadLetter, statusCancelled:
+		return nullString(status), nil
+	default:
+		return sql.NullString{}, fmt.Errorf("unknown status %q", status)
+	}
+}
+
+// parseTime parses the RFC 3339 time of query parameter name into the format
+// of the timestamps it is compared with
+func parseTime(name, value string) (sql.NullString, error) {
+	if value == "" {
+		return sql.NullString{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("%s must be an RFC 3339 time: %q", name, value)
+	}
+	return nullString(t.UTC().Format(sqliteTimeFormat)), nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+)
+
+// Time AwaitEvent waits by default and at most
+const (
+	defaultAwaitTimeout = 10 * time.Second
+	maxAwaitTimeout     = 5 * time.Minute
+)
+
+// AwaitEvent handles GET /_s3local/events/await[?bucket=&key=&event_type=&after=&delivered=true&timeout=]
+//
+// The oldest event after the event with ID after that matches the bucket, the
+// path.Match pattern key and the event type, which may be a family such as
+// s3:ObjectCreated:*, is returned once it is recorded. With delivered=true the
+// worker is woken and the response waits until every job of the event is
+// completed, dead-lettered or cancelled. If that does not happen within
+// timeout, a Go duration, the response is 408 Request Timeout.
+func AwaitEvent(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	query := r.URL.Query()
+
+	s := &stream{
+		q:      store.Queries,
+		bucket: nullString(query.Get("bucket")),
+	}
+	if eventType := query.Get("event_type"); eventType != "" {
+		if !event.Supported(eventType) && eventType != event.TestEvent {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported event type %q", eventType))
+			return
+		}
+		s.eventTypes = []string{eventType}
+	}
+	key := query.Get("key")
+	if _, err := path.Match(key, ""); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid key pattern %q", key))
+		return
+	}
+	if after := query.Get("after"); after != "" {
+		var err error
+		if s.afterID, err = strconv.ParseInt(after, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid after %q", after))
+			return
+		}
+	}
+	timeout := defaultAwaitTimeout
+	if value := query.Get("timeout"); value != "" {
+		var err error
+		timeout, err = time.ParseDuration(value)
+		if err != nil || timeout <= 0 || timeout > maxAwaitTimeout {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("timeout must be a duration of at most %s", maxAwaitTimeout))
+			return
+		}
+	}
+	delivered := query.Get("delivered") == "true"
+
+	// The response may be written after the write timeout of the server
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	poll := time.NewTicker(streamInterval)
+	defer poll.Stop()
+	var found *db.Event
+	for {
+		if found == nil {
+			e, err := s.nextMatch(r, key)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			// Deliver the jobs of the event without waiting for the next tick
+			if found = e; found != nil && delivered {
+				wakeWorker(r)
+			}
+		}
+		if found != nil {
+			e, err := loadEvent(r.Context(), store.Queries, *found)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err)
+				return
+			}
+			if !delivered || settled(e.Jobs) {
+				writeJSON(w, http.StatusOK, e)
+				return
+			}
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			if found != nil {
+				writeError(w, http.StatusRequestTimeout, fmt.Errorf("event %d was not delivered within %s", found.ID, timeout))
+			} else {
+				writeError(w, http.StatusRequestTimeout, fmt.Errorf("no matching event was recorded within %s", timeout))
+			}
+			return
+		case <-poll.C:
+		}
+	}
+}
+
+// nextMatch returns the first event recorded since the last call that matches
+// the stream and the path.Match pattern key, or nil
+func (s *stream) nextMatch(r *http.Request, key string) (*db.Event, error) {
+	for {
+		events, err := s.q.ListEventsAfter(r.Context(), db.ListEventsAfterParams{
+			AfterID:    s.afterID,
+			BucketName: s.bucket,
+			MaxEvents:  streamBatch,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			s.afterID = e.ID
+			if matched, _ := path.Match(key, e.ObjectKey); (key == "" || matched) && s.match(e.EventType, e.ObjectKey) {
+				return &e, nil
+			}
+		}
+		if len(events) < streamBatch {
+			return nil, nil
+		}
+	}
+}
+
+// settled reports whether none of jobs is pending
+func settled(jobs []Job) bool {
+	for _, job := range jobs {
+		if job.Status == statusPending {
+			return false
+		}
+	}
+	return true
+}
+
+// TickResult is the response of Tick
+type TickResult struct {
+	Sent int `json:"sent"`
+}
+
+// Tick handles POST /_s3local/worker/tick
+//
+// The notification worker claims the jobs that are due now instead of at its
+// next tick, and the response is sent once they are sent, successfully or
+// not. Jobs whose destination is busy and jobs scheduled for a retry are left
+// for a later tick.
+func Tick(w http.ResponseWriter, r *http.Request) {
+	worker := ctx.GetWorker(r.Context())
+	if worker == nil {
+		writeError(w, http.StatusServiceUnavailable, errors.New("the notification worker is not running"))
+		return
+	}
+	sent, err := worker.Tick(r.Context())
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, TickResult{Sent: sent})
+}
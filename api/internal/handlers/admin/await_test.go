@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+// fakeWorker counts how often it is woken and ticked
+type fakeWorker struct {
+	wakes atomic.Int32
+	ticks atomic.Int32
+}
+
+func (w *fakeWorker) Wake() {
+	w.wakes.Add(1)
+}
+
+func (w *fakeWorker) Tick(ctx context.Context) (int, error) {
+	return int(w.ticks.Add(1)), nil
+}
+
+func TestAwaitEvent(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+	worker := &fakeWorker{}
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Use(ctx.WithWorker(worker))
+	r.Get("/_s3local/events/await", AwaitEvent)
+	r.Post("/_s3local/worker/tick", Tick)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "queue",
+		DestinationArn:  "http://localhost/queue",
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	record := func(t *testing.T, eventType, key string) db.Event {
+		e, err := event.Record(context.Background(), store.Queries, db.CreateEventParams{
+			BucketName: "test-bucket",
+			EventType:  eventType,
+			ObjectKey:  key,
+		})
+		require.NoError(t, err)
+		return e
+	}
+	await := func(t *testing.T, query string, status int) Event {
+		resp, err := http.Get(ts.URL + "/_s3local/events/await?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+
+		var e Event
+		if status == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&e))
+		}
+		return e
+	}
+
+	t.Run("Return an event already recorded", func(t *testing.T) {
+		record(t, event.ObjectRemovedDelete, "images/cat.png")
+		expected := record(t, event.ObjectCreatedPut, "images/cat.png")
+
+		e := await(t, "bucket=test-bucket&key=images/*.png&event_type=s3:ObjectCreated:*", http.StatusOK)
+		assert.Equal(t, expected.ID, e.ID)
+		require.Len(t, e.Jobs, 1)
+		assert.Equal(t, "pending", e.Jobs[0].Status)
+	})
+
+	t.Run("Wait for an event to be recorded", func(t *testing.T) {
+		latest, err := store.Queries.GetLatestEventID(context.Background())
+		require.NoError(t, err)
+
+		recorded := make(chan int64, 1)
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			for _, key := range []string{"docs/readme.txt", "images/dog.png"} {
+				e, err := event.Record(context.Background(), store.Queries, db.CreateEventParams{
+					BucketName: "test-bucket",
+					EventType:  event.ObjectCreatedPut,
+					ObjectKey:  key,
+				})
+				assert.NoError(t, err)
+				recorded <- e.ID
+			}
+		}()
+		e := await(t, "key=images/*&after="+strconv.FormatInt(latest, 10), http.StatusOK)
+		<-recorded
+		assert.Equal(t, <-recorded, e.ID)
+		assert.Equal(t, "images/dog.png", e.ObjectKey)
+	})
+
+	t.Run("Wait for the jobs of an event", func(t *testing.T) {
+		e := record(t, event.ObjectCreatedPut, "videos/cat.mp4")
+		query := "key=videos/*&delivered=true&timeout=300ms"
+
+		// The worker is woken but the job stays pending
+		wakes := worker.wakes.Load()
+		await(t, query, http.StatusRequestTimeout)
+		assert.Greater(t, worker.wakes.Load(), wakes)
+
+		_, err := store.DB.Exec("UPDATE notification_jobs SET status = 'completed', attempts = 1 WHERE event_id = ?", e.ID)
+		require.NoError(t, err)
+		delivered := await(t, query, http.StatusOK)
+		assert.Equal(t, e.ID, delivered.ID)
+		assert.Equal(t, "completed", delivered.Jobs[0].Status)
+	})
+
+	t.Run("Time out", func(t *testing.T) {
+		await(t, "key=missing&timeout=300ms", http.StatusRequestTimeout)
+	})
+
+	t.Run("Reject invalid parameters", func(t *testing.T) {
+		await(t, "key=[", http.StatusBadRequest)
+		await(t, "event_type=s3:Unknown", http.StatusBadRequest)
+		await(t, "timeout=forever", http.StatusBadRequest)
+		await(t, "after=abc", http.StatusBadRequest)
+	})
+
+	t.Run("Tick the worker", func(t *testing.T) {
+		resp, err := http.Post(ts.URL+"/_s3local/worker/tick", "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		var result TickResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		assert.Equal(t, 1, result.Sent)
+	})
+}
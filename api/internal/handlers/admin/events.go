@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+)
+
+// Event is an event recorded for a bucket, with the jobs delivering it
+type Event struct {
+	ID              int64     `json:"id"`
+	Bucket          string    `json:"bucket"`
+	EventType       string    `json:"event_type"`
+	EventTime       time.Time `json:"event_time"`
+	ObjectKey       string    `json:"object_key,omitempty"`
+	ObjectSize      int64     `json:"object_size"`
+	ObjectETag      string    `json:"object_etag,omitempty"`
+	ObjectVersionID string    `json:"object_version_id,omitempty"`
+	Jobs            []Job     `json:"jobs"`
+}
+
+// ListEventsResult is the response of ListEvents
+type ListEventsResult struct {
+	Events []Event `json:"events"`
+}
+
+// ListEvents handles GET /_s3local/events[?bucket=&status=&since=&until=&limit=&offset=]
+//
+// Events are listed newest first. status selects the events with a job in
+// that status; since and until bound the event time.
+func ListEvents(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := store.Queries.ListEvents(r.Context(), db.ListEventsParams{
+		BucketName: f.bucket,
+		Status:     f.status,
+		Since:      f.since,
+		Until:      f.until,
+		MaxEvents:  f.limit,
+		SkipEvents: f.offset,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := ListEventsResult{Events: make([]Event, 0, len(events))}
+	for _, e := range events {
+		event, err := loadEvent(r.Context(), store.Queries, e)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		result.Events = append(result.Events, event)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// loadEvent returns event e with its jobs
+func loadEvent(ctx context.Context, q *db.Queries, e db.Event) (Event, error) {
+	jobs, err := q.ListNotificationJobsByEvent(ctx, e.ID)
+	if err != nil {
+		return Event{}, err
+	}
+	event := Event{
+		ID:              e.ID,
+		Bucket:          e.BucketName,
+		EventType:       e.EventType,
+		EventTime:       e.EventTime,
+		ObjectKey:       e.ObjectKey,
+		ObjectSize:      e.ObjectSize,
+		ObjectETag:      e.ObjectETag,
+		ObjectVersionID: e.ObjectVersionID.String,
+		Jobs:            make([]Job, 0, len(jobs)),
+	}
+	for _, job := range jobs {
+		event.Jobs = append(event.Jobs, newJob(job.NotificationJob, e, job.Notification))
+	}
+	return event, nil
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestListEvents(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Get("/_s3local/events", ListEvents)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "queue",
+		DestinationArn:  "http://localhost/queue",
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	for _, e := range []db.CreateEventParams{
+		{BucketName: "test-bucket", EventType: event.ObjectCreatedPut, ObjectKey: "a.txt", ObjectSize: 1},
+		{BucketName: "test-bucket", EventType: event.ObjectRemovedDelete, ObjectKey: "a.txt"},
+	} {
+		_, err = event.Record(context.Background(), store.Queries, e)
+		require.NoError(t, err)
+	}
+
+	listEvents := func(t *testing.T, query string) []Event {
+		resp, err := http.Get(ts.URL + "/_s3local/events" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var result ListEventsResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Events
+	}
+
+	// Newest first, with the jobs delivering each event
+	events := listEvents(t, "?bucket=test-bucket")
+	require.Len(t, events, 2)
+	assert.Equal(t, event.ObjectRemovedDelete, events[0].EventType)
+	assert.Empty(t, events[0].Jobs)
+	assert.Equal(t, event.ObjectCreatedPut, events[1].EventType)
+	assert.Equal(t, int64(1), events[1].ObjectSize)
+	require.Len(t, events[1].Jobs, 1)
+	assert.Equal(t, "pending", events[1].Jobs[0].Status)
+	assert.Equal(t, "http://localhost/queue", events[1].Jobs[0].DestinationArn)
+
+	// Events with a job in the given status
+	events = listEvents(t, "?status=pending")
+	require.Len(t, events, 1)
+	assert.Equal(t, event.ObjectCreatedPut, events[0].EventType)
+
+	assert.Empty(t, listEvents(t, "?bucket=other-bucket"))
+	assert.Len(t, listEvents(t, "?limit=1"), 1)
+}
@@ -0,0 +1,277 @@
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+)
+
+// Job is a notification job delivering an event to a destination
+type Job struct {
+	ID               int64      `json:"id"`
+	EventID          int64      `json:"event_id"`
+	Bucket           string     `json:"bucket"`
+	EventType        string     `json:"event_type"`
+	ObjectKey        string     `json:"object_key,omitempty"`
+	DestinationType  string     `json:"destination_type"`
+	DestinationArn   string     `json:"destination_arn"`
+	Status           string     `json:"status"`
+	Attempts         int64      `json:"attempts"`
+	ErrorMessage     string     `json:"error_message,omitempty"`
+	ResponseStatus   int64      `json:"response_status,omitempty"`
+	ResponseBody     string     `json:"response_body,omitempty"`
+	DeliveredTargets []string   `json:"delivered_targets,omitempty"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// newJob returns the job delivering event e to the destination of n
+func newJob(job db.NotificationJob, e db.Event, n db.Notification) Job {
+	j := Job{
+		ID:              job.ID,
+		EventID:         e.ID,
+		Bucket:          e.BucketName,
+		EventType:       e.EventType,
+		ObjectKey:       e.ObjectKey,
+		DestinationType: n.DestinationType,
+		DestinationArn:  n.DestinationArn,
+		Status:          job.Status,
+		Attempts:        job.Attempts,
+		ErrorMessage:    job.ErrorMessage.String,
+		ResponseStatus:  job.ResponseStatus.Int64,
+		ResponseBody:    job.ResponseBody.String,
+		CreatedAt:       job.CreatedAt,
+		UpdatedAt:       job.UpdatedAt,
+	}
+	if job.DeliveredTargets.Valid {
+		json.Unmarshal([]byte(job.DeliveredTargets.String), &j.DeliveredTargets)
+	}
+	if job.NextAttemptAt.Valid && job.Status == statusPending {
+		j.NextAttemptAt = &job.NextAttemptAt.Time
+	}
+	return j
+}
+
+// ListJobsResult is the response of ListJobs
+type ListJobsResult struct {
+	Jobs []Job `json:"jobs"`
+}
+
+// ListJobs handles GET /_s3local/jobs[?bucket=&status=&since=&until=&limit=&offset=]
+//
+// Jobs are listed newest first; since and until bound their creation time.
+func ListJobs(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	jobs, err := store.Queries.ListNotificationJobs(r.Context(), db.ListNotificationJobsParams{
+		BucketName: f.bucket,
+		Status:     f.status,
+		Since:      f.since,
+		Until:      f.until,
+		MaxJobs:    f.limit,
+		SkipJobs:   f.offset,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	result := ListJobsResult{Jobs: make([]Job, 0, len(jobs))}
+	for _, job := range jobs {
+		result.Jobs = append(result.Jobs, newJob(job.NotificationJob, job.Event, job.Notification))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GetJob handles GET /_s3local/jobs/{id}
+func GetJob(w http.ResponseWriter, r *http.Request) {
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+	writeJob(w, r, id)
+}
+
+// RetryJob handles POST /_s3local/jobs/{id}/retry
+//
+// The job is delivered again with the full number of attempts, whatever its
+// status. A pending job waiting for its next attempt becomes due at once, and
+// the outcome of an attempt in progress is not recorded.
+func RetryJob(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+
+	retried, err := store.Queries.RetryNotificationJob(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if retried == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %d not found", id))
+		return
+	}
+	wakeWorker(r)
+	writeJob(w, r, id)
+}
+
+// CancelJob handles POST /_s3local/jobs/{id}/cancel
+//
+// Only pending jobs can be cancelled. An attempt in progress is not aborted,
+// but its outcome is not recorded.
+func CancelJob(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+
+	cancelled, err := store.Queries.CancelNotificationJob(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if cancelled == 0 {
+		job, err := store.Queries.GetNotificationJob(r.Context(), id)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			writeError(w, http.StatusNotFound, fmt.Errorf("job %d not found", id))
+		case err != nil:
+			writeError(w, http.StatusInternalServerError, err)
+		default:
+			writeError(w, http.StatusConflict, fmt.Errorf("job %d is %s, not pending", id, job.NotificationJob.Status))
+		}
+		return
+	}
+	writeJob(w, r, id)
+}
+
+// DeleteJob handles DELETE /_s3local/jobs/{id}
+func DeleteJob(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	id, ok := jobID(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := store.Queries.DeleteNotificationJob(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if deleted == 0 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %d not found", id))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PurgeResult is the response of PurgeJobs
+type PurgeResult struct {
+	Purged int64 `json:"purged"`
+}
+
+// PurgeJobs handles DELETE /_s3local/jobs[?bucket=&status=&before=]
+//
+// Jobs in the given status, or all jobs that are no longer pending, are
+// deleted, optionally only those of one bucket or last updated before the
+// RFC 3339 time before.
+func PurgeJobs(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+
+	status, err := parseStatus(r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	before, err := parseTime("before", r.URL.Query().Get("before"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	purged, err := store.Queries.PurgeNotificationJobs(r.Context(), db.PurgeNotificationJobsParams{
+		Status:     status,
+		BucketName: nullString(r.URL.Query().Get("bucket")),
+		Before:     before,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, PurgeResult{Purged: purged})
+}
+
+// RequeueResult is the response of RequeueDeadLetterJobs
+type RequeueResult struct {
+	Requeued int64 `json:"requeued"`
+}
+
+// RequeueDeadLetterJobs handles POST /_s3local/jobs/requeue[?bucket={bucket}]
+//
+// Dead-lettered notification jobs, optionally only those of one bucket, are
+// moved back to pending and get the full number of attempts again.
+func RequeueDeadLetterJobs(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+
+	requeued, err := store.Queries.RequeueDeadLetterNotificationJobs(r.Context(), nullString(r.URL.Query().Get("bucket")))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if requeued > 0 {
+		wakeWorker(r)
+	}
+	writeJSON(w, http.StatusOK, RequeueResult{Requeued: requeued})
+}
+
+// wakeWorker makes the notification worker look for due jobs without waiting
+// for its next tick
+func wakeWorker(r *http.Request) {
+	if worker := ctx.GetWorker(r.Context()); worker != nil {
+		worker.Wake()
+	}
+}
+
+// jobID parses the {id} URL parameter, writing an error response if it is
+// not a job ID
+func jobID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid job ID %q", chi.URLParam(r, "id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// writeJob writes the job with the given ID
+func writeJob(w http.ResponseWriter, r *http.Request, id int64) {
+	store := ctx.GetStore(r.Context())
+	job, err := store.Queries.GetNotificationJob(r.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %d not found", id))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newJob(job.NotificationJob, job.Event, job.Notification))
+}
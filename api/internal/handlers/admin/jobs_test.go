@@ -0,0 +1,210 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestRequeueDeadLetterJobs(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Post("/_s3local/jobs/requeue", RequeueDeadLetterJobs)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, name := range []string{"bucket-a", "bucket-b"} {
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   name,
+			Region: "us-east-1",
+		})
+		require.NoError(t, err)
+		_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+			BucketName:      name,
+			EventType:       "s3:ObjectCreated:*",
+			DestinationType: "lambda",
+			DestinationArn:  "http://localhost/" + name,
+			Enabled:         true,
+		})
+		require.NoError(t, err)
+		_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+			BucketName: name,
+			EventType:  event.ObjectCreatedPut,
+			ObjectKey:  "test-key",
+		})
+		require.NoError(t, err)
+	}
+	_, err := store.DB.Exec("UPDATE notification_jobs SET status = 'dead_letter', attempts = 5")
+	require.NoError(t, err)
+
+	requeue := func(t *testing.T, query string) int64 {
+		resp, err := http.Post(ts.URL+"/_s3local/jobs/requeue"+query, "", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+		var result RequeueResult
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return result.Requeued
+	}
+
+	assert.Equal(t, int64(1), requeue(t, "?bucket=bucket-a"))
+	assert.Equal(t, int64(1), requeue(t, ""))
+	assert.Equal(t, int64(0), requeue(t, ""))
+
+	var pending int
+	err = store.DB.QueryRow("SELECT COUNT(*) FROM notification_jobs WHERE status = 'pending' AND attempts = 0").Scan(&pending)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pending)
+}
+
+func TestJobs(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	worker := &fakeWorker{}
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Use(ctx.WithWorker(worker))
+	r.Get("/_s3local/jobs", ListJobs)
+	r.Delete("/_s3local/jobs", PurgeJobs)
+	r.Get("/_s3local/jobs/{id}", GetJob)
+	r.Delete("/_s3local/jobs/{id}", DeleteJob)
+	r.Post("/_s3local/jobs/{id}/retry", RetryJob)
+	r.Post("/_s3local/jobs/{id}/cancel", CancelJob)
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	for _, name := range []string{"bucket-a", "bucket-b"} {
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   name,
+			Region: "us-east-1",
+		})
+		require.NoError(t, err)
+		_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+			BucketName:      name,
+			EventType:       "s3:ObjectCreated:*",
+			DestinationType: "lambda",
+			DestinationArn:  "http://localhost/" + name,
+			Enabled:         true,
+		})
+		require.NoError(t, err)
+		_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+			BucketName: name,
+			EventType:  event.ObjectCreatedPut,
+			ObjectKey:  "test-key",
+		})
+		require.NoError(t, err)
+	}
+	_, err := store.DB.Exec(`UPDATE notification_jobs
+		SET status = 'dead_letter', attempts = 3, error_message = 'HTTP 503: unavailable',
+		    response_status = 503, response_body = 'unavailable'
+		WHERE event_id IN (SELECT id FROM events WHERE bucket_name = 'bucket-a')`)
+	require.NoError(t, err)
+
+	do := func(t *testing.T, method, path string, status int, v any) {
+		req, err := http.NewRequest(method, ts.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, status, resp.StatusCode)
+		if v != nil {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		}
+	}
+	listJobs := func(t *testing.T, query string) []Job {
+		var result ListJobsResult
+		do(t, http.MethodGet, "/_s3local/jobs"+query, http.StatusOK, &result)
+		return result.Jobs
+	}
+
+	t.Run("List jobs with filters", func(t *testing.T) {
+		jobs := listJobs(t, "")
+		require.Len(t, jobs, 2)
+		assert.Equal(t, "bucket-b", jobs[0].Bucket)
+
+		jobs = listJobs(t, "?bucket=bucket-a")
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "dead_letter", jobs[0].Status)
+		assert.Equal(t, int64(3), jobs[0].Attempts)
+		assert.Equal(t, "HTTP 503: unavailable", jobs[0].ErrorMessage)
+		assert.Equal(t, int64(503), jobs[0].ResponseStatus)
+		assert.Equal(t, "unavailable", jobs[0].ResponseBody)
+		assert.Equal(t, "http://localhost/bucket-a", jobs[0].DestinationArn)
+		assert.Equal(t, "test-key", jobs[0].ObjectKey)
+
+		jobs = listJobs(t, "?status=pending")
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "bucket-b", jobs[0].Bucket)
+
+		assert.Len(t, listJobs(t, "?limit=1&offset=1"), 1)
+		assert.Len(t, listJobs(t, "?since="+time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)), 2)
+		assert.Empty(t, listJobs(t, "?until="+time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)))
+
+		do(t, http.MethodGet, "/_s3local/jobs?status=unknown", http.StatusBadRequest, nil)
+		do(t, http.MethodGet, "/_s3local/jobs?since=yesterday", http.StatusBadRequest, nil)
+	})
+
+	t.Run("Retry and cancel jobs", func(t *testing.T) {
+		id := listJobs(t, "?bucket=bucket-a")[0].ID
+		path := "/_s3local/jobs/" + strconv.FormatInt(id, 10)
+
+		var job Job
+		do(t, http.MethodPost, path+"/retry", http.StatusOK, &job)
+		assert.Equal(t, "pending", job.Status)
+		assert.Zero(t, job.Attempts)
+		assert.Empty(t, job.ErrorMessage)
+		assert.Equal(t, int32(1), worker.wakes.Load())
+
+		do(t, http.MethodPost, path+"/cancel", http.StatusOK, &job)
+		assert.Equal(t, "cancelled", job.Status)
+
+		// Only pending jobs can be cancelled
+		do(t, http.MethodPost, path+"/cancel", http.StatusConflict, nil)
+
+		do(t, http.MethodGet, path, http.StatusOK, &job)
+		assert.Equal(t, "cancelled", job.Status)
+
+		do(t, http.MethodPost, "/_s3local/jobs/999/retry", http.StatusNotFound, nil)
+		do(t, http.MethodPost, "/_s3local/jobs/999/cancel", http.StatusNotFound, nil)
+		do(t, http.MethodGet, "/_s3local/jobs/abc", http.StatusBadRequest, nil)
+	})
+
+	t.Run("Purge jobs", func(t *testing.T) {
+		// Pending jobs are only purged when asked for
+		var result PurgeResult
+		do(t, http.MethodDelete, "/_s3local/jobs?bucket=bucket-b", http.StatusOK, &result)
+		assert.Zero(t, result.Purged)
+
+		do(t, http.MethodDelete, "/_s3local/jobs", http.StatusOK, &result)
+		assert.Equal(t, int64(1), result.Purged)
+		assert.Len(t, listJobs(t, ""), 1)
+
+		id := listJobs(t, "")[0].ID
+		do(t, http.MethodDelete, "/_s3local/jobs/"+strconv.FormatInt(id, 10), http.StatusNoContent, nil)
+		do(t, http.MethodDelete, "/_s3local/jobs/"+strconv.FormatInt(id, 10), http.StatusNotFound, nil)
+		assert.Empty(t, listJobs(t, ""))
+	})
+}
@@ -0,0 +1,234 @@
+package admin
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+)
+
+const (
+	// streamInterval is how often an event stream looks for new events and
+	// job changes
+	streamInterval = 250 * time.Millisecond
+	// keepAliveInterval is how often an idle event stream sends a comment, so
+	// that proxies keep the connection open
+	keepAliveInterval = 15 * time.Second
+	// jobChangeWindow is how far back job updates are looked for, as
+	// updated_at has a resolution of one second
+	jobChangeWindow = 2 * time.Second
+	// streamBatch is the number of events read at once
+	streamBatch = 100
+)
+
+// StreamEvents handles GET /_s3local/events/stream[?bucket=&prefix=&event_type=&jobs=true]
+//
+// New events are pushed as Server-Sent Events named "event", and with
+// jobs=true the jobs whose status or attempts change as events named "job".
+// event_type may be repeated and accepts families such as s3:ObjectCreated:*.
+// Events carry their ID as the SSE id, so a client reconnecting with
+// Last-Event-ID receives the events it missed.
+func StreamEvents(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	query := r.URL.Query()
+
+	s := &stream{
+		w:          w,
+		rc:         http.NewResponseController(w),
+		q:          store.Queries,
+		bucket:     nullString(query.Get("bucket")),
+		prefix:     query.Get("prefix"),
+		eventTypes: query["event_type"],
+	}
+	for _, eventType := range s.eventTypes {
+		if !event.Supported(eventType) && eventType != event.TestEvent {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported event type %q", eventType))
+			return
+		}
+	}
+
+	// Resume after the last event the client received, or start with the
+	// events recorded from now on
+	var err error
+	if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+		s.afterID, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid Last-Event-ID %q", lastEventID))
+			return
+		}
+	} else if s.afterID, err = store.Queries.GetLatestEventID(r.Context()); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if query.Get("jobs") == "true" {
+		s.jobs = make(map[int64]jobState)
+		// Only changes made from now on are sent
+		if err := s.pollJobs(r, false); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	// The stream is kept open beyond the write timeout of the server
+	s.rc.SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	s.rc.Flush()
+
+	poll := time.NewTicker(streamInterval)
+	defer poll.Stop()
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-poll.C:
+			if err := s.pollEvents(r); err != nil {
+				return
+			}
+			if s.jobs != nil {
+				if err := s.pollJobs(r, true); err != nil {
+					return
+				}
+			}
+		}
+		if err := s.rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// stream is the state of an event stream, or of a request awaiting an event
+type stream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+	q  *db.Queries
+
+	// bucket, prefix and eventTypes select the events sent
+	bucket     sql.NullString
+	prefix     string
+	eventTypes []string
+
+	// afterID is the ID of the last event looked at
+	afterID int64
+	// jobs holds the last seen state of recently updated jobs, nil unless job
+	// changes are sent
+	jobs map[int64]jobState
+}
+
+// jobState is the state of a job whose changes are sent
+type jobState struct {
+	status    string
+	attempts  int64
+	updatedAt time.Time
+}
+
+// match reports whether an event of eventType for key is sent
+func (s *stream) match(eventType, key string) bool {
+	if !event.MatchKey(s.prefix, "", key) {
+		return false
+	}
+	if len(s.eventTypes) == 0 {
+		return true
+	}
+	for _, pattern := range s.eventTypes {
+		if event.Match(pattern, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// pollEvents sends the matching events recorded since the last poll
+func (s *stream) pollEvents(r *http.Request) error {
+	for {
+		events, err := s.q.ListEventsAfter(r.Context(), db.ListEventsAfterParams{
+			AfterID:    s.afterID,
+			BucketName: s.bucket,
+			MaxEvents:  streamBatch,
+		})
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			s.afterID = e.ID
+			if !s.match(e.EventType, e.ObjectKey) {
+				continue
+			}
+			event, err := loadEvent(r.Context(), s.q, e)
+			if err != nil {
+				return err
+			}
+			if err := s.send(strconv.FormatInt(e.ID, 10), "event", event); err != nil {
+				return err
+			}
+		}
+		if len(events) < streamBatch {
+			return nil
+		}
+	}
+}
+
+// pollJobs sends the matching jobs whose status or attempts changed since the
+// last poll, or only records their state unless send is set
+func (s *stream) pollJobs(r *http.Request, send bool) error {
+	since := time.Now().UTC().Add(-jobChangeWindow)
+	jobs, err := s.q.ListNotificationJobsUpdatedSince(r.Context(), db.ListNotificationJobsUpdatedSinceParams{
+		Since:      since.Format(sqliteTimeFormat),
+		BucketName: s.bucket,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		state := jobState{
+			status:    job.NotificationJob.Status,
+			attempts:  job.NotificationJob.Attempts,
+			updatedAt: job.NotificationJob.UpdatedAt,
+		}
+		last, seen := s.jobs[job.NotificationJob.ID]
+		s.jobs[job.NotificationJob.ID] = state
+		if !send || (seen && last.status == state.status && last.attempts == state.attempts) {
+			continue
+		}
+		if !s.match(job.Event.EventType, job.Event.ObjectKey) {
+			continue
+		}
+		if err := s.send("", "job", newJob(job.NotificationJob, job.Event, job.Notification)); err != nil {
+			return err
+		}
+	}
+
+	// Jobs updated before the window are not looked at again
+	for id, state := range s.jobs {
+		if state.updatedAt.Before(since.Add(-time.Second)) {
+			delete(s.jobs, id)
+		}
+	}
+	return nil
+}
+
+// send writes a Server-Sent Event with the given ID, if any, name and JSON data
+func (s *stream) send(id, name string, data any) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		fmt.Fprintf(s.w, "id: %s\n", id)
+	}
+	_, err = fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", name, body)
+	return err
+}
@@ -0,0 +1,151 @@
+package admin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+// sseMessage is a Server-Sent Event read from a stream
+type sseMessage struct {
+	id, name, data string
+}
+
+// readStream opens the event stream at path and returns the messages it
+// receives until the test ends
+func readStream(t *testing.T, ts *httptest.Server, path, lastEventID string) <-chan sseMessage {
+	reqCtx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, ts.URL+path, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	messages := make(chan sseMessage, 16)
+	go func() {
+		defer resp.Body.Close()
+		var msg sseMessage
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			field, value, _ := strings.Cut(scanner.Text(), ": ")
+			switch field {
+			case "id":
+				msg.id = value
+			case "event":
+				msg.name = value
+			case "data":
+				msg.data = value
+			case "":
+				messages <- msg
+				msg = sseMessage{}
+			}
+		}
+	}()
+	return messages
+}
+
+func receive(t *testing.T, messages <-chan sseMessage) sseMessage {
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no message received")
+		return sseMessage{}
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Get("/_s3local/events/stream", StreamEvents)
+
+	// Closed after the streams, which the server waits for
+	ts := httptest.NewServer(r)
+	t.Cleanup(ts.Close)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "queue",
+		DestinationArn:  "http://localhost/queue",
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+
+	messages := readStream(t, ts, "/_s3local/events/stream?bucket=test-bucket&prefix=images/&event_type=s3:ObjectCreated:*&jobs=true", "")
+
+	var recorded []db.Event
+	for _, e := range []db.CreateEventParams{
+		{BucketName: "test-bucket", EventType: event.ObjectCreatedPut, ObjectKey: "docs/readme.txt"},
+		{BucketName: "test-bucket", EventType: event.ObjectRemovedDelete, ObjectKey: "images/dog.png"},
+		{BucketName: "test-bucket", EventType: event.ObjectCreatedPut, ObjectKey: "images/cat.png", ObjectSize: 3},
+	} {
+		created, err := event.Record(context.Background(), store.Queries, e)
+		require.NoError(t, err)
+		recorded = append(recorded, created)
+	}
+
+	// Only the matching event and its job are sent
+	msg := receive(t, messages)
+	assert.Equal(t, "event", msg.name)
+	var e Event
+	require.NoError(t, json.Unmarshal([]byte(msg.data), &e))
+	assert.Equal(t, "images/cat.png", e.ObjectKey)
+	assert.Equal(t, int64(3), e.ObjectSize)
+	assert.Equal(t, strconv.FormatInt(recorded[2].ID, 10), msg.id)
+	require.Len(t, e.Jobs, 1)
+
+	msg = receive(t, messages)
+	assert.Equal(t, "job", msg.name)
+	assert.Empty(t, msg.id)
+	var job Job
+	require.NoError(t, json.Unmarshal([]byte(msg.data), &job))
+	assert.Equal(t, e.Jobs[0].ID, job.ID)
+	assert.Equal(t, "pending", job.Status)
+
+	// Status changes of the job are sent
+	_, err = store.DB.Exec("UPDATE notification_jobs SET status = 'completed', attempts = 1, response_status = 200, updated_at = CURRENT_TIMESTAMP WHERE id = ?", job.ID)
+	require.NoError(t, err)
+	msg = receive(t, messages)
+	assert.Equal(t, "job", msg.name)
+	require.NoError(t, json.Unmarshal([]byte(msg.data), &job))
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, int64(200), job.ResponseStatus)
+
+	// A client reconnecting with Last-Event-ID gets the events it missed
+	messages = readStream(t, ts, "/_s3local/events/stream?bucket=test-bucket", "1")
+	for _, expected := range recorded[1:] {
+		msg := receive(t, messages)
+		assert.Equal(t, "event", msg.name)
+		require.NoError(t, json.Unmarshal([]byte(msg.data), &e))
+		assert.Equal(t, expected.ID, e.ID)
+	}
+}
@@ -0,0 +1,163 @@
+package bucket
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+const (
+	// managedByConfig marks notification rules declared in config.yaml
+	managedByConfig = "config"
+	// configNotificationID is the Id of the configurations that list
+	// config-managed rules in GetBucketNotificationConfiguration
+	configNotificationID = "s3local-config"
+)
+
+// SyncConfigNotifications applies the notification rules of config.yaml to
+// every existing bucket. Rules of buckets that do not exist yet are applied by
+// CreateBucket, but are checked now so that invalid rules are reported at
+// startup.
+func SyncConfigNotifications(ctx context.Context, store *db.Store, rules []config.NotificationRule) error {
+	buckets, err := store.Queries.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(buckets))
+	for _, b := range buckets {
+		existing[b.Name] = true
+	}
+	missing := make(map[string]bool)
+	for _, rule := range rules {
+		if !existing[rule.Bucket] && !missing[rule.Bucket] {
+			missing[rule.Bucket] = true
+			if _, err := configNotificationRules(rules, rule.Bucket); err != nil {
+				return err
+			}
+			log.Printf("Notification rules of bucket %s apply once it is created", rule.Bucket)
+		}
+	}
+
+	return store.ExecTx(ctx, func(q *db.Queries) error {
+		for _, b := range buckets {
+			if err := applyConfigNotifications(ctx, q, rules, b.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// applyConfigNotifications replaces the config-managed notification rules of
+// bucketName with those of config.yaml. Unchanged rules are kept along with
+// their pending jobs, and new queue and topic rules receive a test message.
+func applyConfigNotifications(ctx context.Context, q *db.Queries, rules []config.NotificationRule, bucketName string) error {
+	desired, err := configNotificationRules(rules, bucketName)
+	if err != nil {
+		return err
+	}
+
+	existing, err := q.ListNotificationsByBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+	kept := make(map[db.CreateNotificationParams]bool)
+	for _, n := range existing {
+		params := notificationParams(n)
+		if n.ManagedBy != managedByConfig {
+			for _, rule := range desired {
+				if rulesOverlap(rule, params) {
+					log.Printf("Notification rule %d of bucket %s overlaps a rule of config.yaml", n.ID, bucketName)
+					break
+				}
+			}
+			continue
+		}
+		if containsRule(desired, params) && !kept[params] {
+			kept[params] = true
+			continue
+		}
+		if err := q.DeleteNotification(ctx, n.ID); err != nil {
+			return err
+		}
+	}
+
+	var created []db.Notification
+	for _, rule := range desired {
+		if kept[rule] {
+			continue
+		}
+		n, err := q.CreateConfigNotification(ctx, db.CreateConfigNotificationParams(rule))
+		if err != nil {
+			return err
+		}
+		created = append(created, n)
+	}
+	return recordTestEvents(ctx, q, created)
+}
+
+// configNotificationRules returns the rules of config.yaml for bucketName,
+// checked like a PutBucketNotificationConfiguration request
+func configNotificationRules(rules []config.NotificationRule, bucketName string) ([]db.CreateNotificationParams, error) {
+	desired, s3Err := notificationRules(bucketName, configNotificationConfiguration(rules, bucketName))
+	if s3Err != nil {
+		return nil, fmt.Errorf("notification rules of bucket %s in config.yaml: %s", bucketName, s3Err.Message)
+	}
+	return desired, nil
+}
+
+// configNotificationConfiguration returns the rules of config.yaml for
+// bucketName as a notification configuration
+func configNotificationConfiguration(rules []config.NotificationRule, bucketName string) NotificationConfiguration {
+	var cfg NotificationConfiguration
+	for _, rule := range rules {
+		if rule.Bucket != bucketName {
+			continue
+		}
+		var filter NotificationFilter
+		if rule.Prefix != "" {
+			filter.S3Key.FilterRules = append(filter.S3Key.FilterRules, FilterRule{Name: "prefix", Value: rule.Prefix})
+		}
+		if rule.Suffix != "" {
+			filter.S3Key.FilterRules = append(filter.S3Key.FilterRules, FilterRule{Name: "suffix", Value: rule.Suffix})
+		}
+
+		destination := rule.Destination.Target()
+		switch rule.Destination.Type {
+		case config.DriverSQS:
+			cfg.QueueConfigurations = append(cfg.QueueConfigurations, QueueConfiguration{Queue: destination, Events: rule.Events, Filter: filter})
+		case config.DriverSNS:
+			cfg.TopicConfigurations = append(cfg.TopicConfigurations, TopicConfiguration{Topic: destination, Events: rule.Events, Filter: filter})
+		case config.DriverLambda:
+			cfg.LambdaFunctionConfigurations = append(cfg.LambdaFunctionConfigurations, LambdaFunctionConfiguration{LambdaFunctionArn: destination, Events: rule.Events, Filter: filter})
+		}
+	}
+	return cfg
+}
+
+// notificationParams returns the rule stored in notification n
+func notificationParams(n db.Notification) db.CreateNotificationParams {
+	return db.CreateNotificationParams{
+		BucketName:      n.BucketName,
+		EventType:       n.EventType,
+		DestinationType: n.DestinationType,
+		DestinationArn:  n.DestinationArn,
+		FilterPrefix:    n.FilterPrefix,
+		FilterSuffix:    n.FilterSuffix,
+		Enabled:         n.Enabled,
+	}
+}
+
+// containsRule reports whether rules contains rule
+func containsRule(rules []db.CreateNotificationParams, rule db.CreateNotificationParams) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
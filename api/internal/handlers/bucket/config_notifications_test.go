@@ -0,0 +1,180 @@
+package bucket
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestConfigNotifications(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	cfg := &config.Config{Notifications: []config.NotificationRule{
+		{
+			Bucket: "test-bucket",
+			Events: []string{"s3:ObjectCreated:*"},
+			Prefix: "images/",
+			Destination: config.NotificationDestination{
+				Type: "sqs",
+				ARN:  "arn:aws:sqs:us-east-1:000000000000:images",
+			},
+		},
+		{
+			Bucket: "new-bucket",
+			Events: []string{"s3:ObjectRemoved:*"},
+			Destination: config.NotificationDestination{
+				Type: "lambda",
+				URL:  "http://localhost:9001/2015-03-31/functions/function/invocations",
+			},
+		},
+	}}
+	require.NoError(t, SyncConfigNotifications(context.Background(), store, cfg.Notifications))
+
+	r := chi.NewRouter()
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Use(ctx.WithStore(store))
+		r.Use(ctx.WithConfig(cfg))
+		r.Put("/", func(w http.ResponseWriter, req *http.Request) {
+			if req.URL.Query().Has("notification") {
+				PutBucketNotificationConfiguration(w, req)
+				return
+			}
+			CreateBucket(w, req)
+		})
+		r.Get("/", GetBucketNotificationConfiguration)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	getConfig := func(t *testing.T, bucket string) *s3.GetBucketNotificationConfigurationOutput {
+		resp, err := s3Client.GetBucketNotificationConfiguration(context.Background(), &s3.GetBucketNotificationConfigurationInput{
+			Bucket: aws.String(bucket),
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Rules are shown as config-managed", func(t *testing.T) {
+		resp := getConfig(t, "test-bucket")
+		require.Len(t, resp.QueueConfigurations, 1)
+		assert.Equal(t, "s3local-config", aws.ToString(resp.QueueConfigurations[0].Id))
+		assert.Equal(t, "arn:aws:sqs:us-east-1:000000000000:images", aws.ToString(resp.QueueConfigurations[0].QueueArn))
+	})
+
+	t.Run("API calls keep config-managed rules", func(t *testing.T) {
+		// Replacing the configuration with a round-tripped copy of it plus a
+		// new rule keeps the config-managed rule once
+		current := getConfig(t, "test-bucket")
+		_, err := s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+			NotificationConfiguration: &types.NotificationConfiguration{
+				QueueConfigurations: current.QueueConfigurations,
+				TopicConfigurations: []types.TopicConfiguration{{
+					TopicArn: aws.String("arn:aws:sns:us-east-1:000000000000:removed"),
+					Events:   []types.Event{"s3:ObjectRemoved:*"},
+				}},
+			},
+		})
+		require.NoError(t, err)
+
+		resp := getConfig(t, "test-bucket")
+		assert.Len(t, resp.QueueConfigurations, 1)
+		assert.Len(t, resp.TopicConfigurations, 1)
+
+		// An empty configuration removes only the rules set through the API
+		_, err = s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket:                    aws.String("test-bucket"),
+			NotificationConfiguration: &types.NotificationConfiguration{},
+		})
+		require.NoError(t, err)
+		resp = getConfig(t, "test-bucket")
+		assert.Len(t, resp.QueueConfigurations, 1)
+		assert.Empty(t, resp.TopicConfigurations)
+
+		// Rules overlapping a config-managed rule are rejected
+		_, err = s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+			NotificationConfiguration: &types.NotificationConfiguration{
+				QueueConfigurations: []types.QueueConfiguration{{
+					QueueArn: aws.String("arn:aws:sqs:us-east-1:000000000000:other"),
+					Events:   []types.Event{"s3:ObjectCreated:Put"},
+				}},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "InvalidArgument")
+	})
+
+	t.Run("Syncing again keeps unchanged rules", func(t *testing.T) {
+		before, err := store.Queries.ListNotificationsByBucket(context.Background(), "test-bucket")
+		require.NoError(t, err)
+		require.NoError(t, SyncConfigNotifications(context.Background(), store, cfg.Notifications))
+		after, err := store.Queries.ListNotificationsByBucket(context.Background(), "test-bucket")
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+
+		// Rules removed from config.yaml are deleted
+		require.NoError(t, SyncConfigNotifications(context.Background(), store, nil))
+		after, err = store.Queries.ListNotificationsByBucket(context.Background(), "test-bucket")
+		require.NoError(t, err)
+		assert.Empty(t, after)
+	})
+
+	t.Run("Rules apply once their bucket is created", func(t *testing.T) {
+		_, err := s3Client.CreateBucket(context.Background(), &s3.CreateBucketInput{
+			Bucket: aws.String("new-bucket"),
+		})
+		require.NoError(t, err)
+
+		resp := getConfig(t, "new-bucket")
+		require.Len(t, resp.LambdaFunctionConfigurations, 1)
+		assert.Equal(t, "s3local-config", aws.ToString(resp.LambdaFunctionConfigurations[0].Id))
+	})
+	t.Run("Invalid rules are refused before their bucket exists", func(t *testing.T) {
+		rule := func(events []string, prefix string) config.NotificationRule {
+			return config.NotificationRule{
+				Bucket: "future-bucket",
+				Events: events,
+				Prefix: prefix,
+				Destination: config.NotificationDestination{
+					Type: "sqs",
+					ARN:  "arn:aws:sqs:us-east-1:000000000000:future",
+				},
+			}
+		}
+
+		err := SyncConfigNotifications(context.Background(), store, []config.NotificationRule{
+			rule([]string{"s3:ObjectCreated:Upload"}, ""),
+		})
+		assert.ErrorContains(t, err, "notification rules of bucket future-bucket in config.yaml: The event is not supported")
+
+		err = SyncConfigNotifications(context.Background(), store, []config.NotificationRule{
+			rule([]string{"s3:ObjectCreated:*"}, "images/"),
+			rule([]string{"s3:ObjectCreated:Put"}, ""),
+		})
+		assert.ErrorContains(t, err, "Configurations overlap")
+	})
+}
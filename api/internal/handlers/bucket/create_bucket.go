@@ -51,9 +51,19 @@ func CreateBucket(w http.ResponseWriter, r *http.Request) {
 		region = r.Header.Get("x-amz-bucket-region")
 	}
 
-	err := store.Queries.CreateBucket(r.Context(), db.CreateBucketParams{
-		Name:   bucketName,
-		Region: region,
+	err := store.ExecTx(r.Context(), func(q *db.Queries) error {
+		err := q.CreateBucket(r.Context(), db.CreateBucketParams{
+			Name:   bucketName,
+			Region: region,
+		})
+		if err != nil {
+			return err
+		}
+		// Rules of config.yaml apply as soon as their bucket exists
+		if cfg := ctx.GetConfig(r.Context()); cfg != nil {
+			return applyConfigNotifications(r.Context(), q, cfg.Notifications, bucketName)
+		}
+		return nil
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
@@ -41,6 +41,9 @@ func DeleteBucket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Parts of unfinished multipart uploads are removed by ON DELETE CASCADE
+	store.ReleaseBlobs(r.Context())
+
 	w.Header().Set("Content-Type", "application/xml")
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -34,10 +34,16 @@ func GetBucketNotificationConfiguration(w http.ResponseWriter, r *http.Request)
 	// Build notification configuration response
 	config := NotificationConfiguration{}
 
-	// Group notifications by destination type and ARN
-	queueConfigs := make(map[string]*QueueConfiguration)
-	topicConfigs := make(map[string]*TopicConfiguration)
-	lambdaConfigs := make(map[string]*LambdaFunctionConfiguration)
+	// Group notifications by destination, filter and whether config.yaml
+	// manages them; config-managed configurations are marked by their Id
+	type configKey struct {
+		destinationArn string
+		prefix, suffix string
+		managedBy      string
+	}
+	queueConfigs := make(map[configKey]*QueueConfiguration)
+	topicConfigs := make(map[configKey]*TopicConfiguration)
+	lambdaConfigs := make(map[configKey]*LambdaFunctionConfiguration)
 
 	for _, notification := range notifications {
 		if !notification.Enabled {
@@ -65,32 +71,48 @@ func GetBucketNotificationConfiguration(w http.ResponseWriter, r *http.Request)
 			},
 		}
 
+		key := configKey{
+			destinationArn: notification.DestinationArn,
+			prefix:         notification.FilterPrefix.String,
+			suffix:         notification.FilterSuffix.String,
+			managedBy:      notification.ManagedBy,
+		}
+		var id string
+		if notification.ManagedBy == managedByConfig {
+			id = configNotificationID
+		}
+
 		switch notification.DestinationType {
+		case "eventbridge":
+			config.EventBridgeConfiguration = &EventBridgeConfiguration{}
 		case "sqs":
-			if queueConfig, exists := queueConfigs[notification.DestinationArn]; exists {
+			if queueConfig, exists := queueConfigs[key]; exists {
 				queueConfig.Events = append(queueConfig.Events, notification.EventType)
 			} else {
-				queueConfigs[notification.DestinationArn] = &QueueConfiguration{
+				queueConfigs[key] = &QueueConfiguration{
+					Id:     id,
 					Queue:  notification.DestinationArn,
 					Events: []string{notification.EventType},
 					Filter: filter,
 				}
 			}
 		case "sns":
-			if topicConfig, exists := topicConfigs[notification.DestinationArn]; exists {
+			if topicConfig, exists := topicConfigs[key]; exists {
 				topicConfig.Events = append(topicConfig.Events, notification.EventType)
 			} else {
-				topicConfigs[notification.DestinationArn] = &TopicConfiguration{
+				topicConfigs[key] = &TopicConfiguration{
+					Id:     id,
 					Topic:  notification.DestinationArn,
 					Events: []string{notification.EventType},
 					Filter: filter,
 				}
 			}
 		case "lambda":
-			if lambdaConfig, exists := lambdaConfigs[notification.DestinationArn]; exists {
+			if lambdaConfig, exists := lambdaConfigs[key]; exists {
 				lambdaConfig.Events = append(lambdaConfig.Events, notification.EventType)
 			} else {
-				lambdaConfigs[notification.DestinationArn] = &LambdaFunctionConfiguration{
+				lambdaConfigs[key] = &LambdaFunctionConfiguration{
+					Id:                id,
 					LambdaFunctionArn: notification.DestinationArn,
 					Events:            []string{notification.EventType},
 					Filter:            filter,
@@ -0,0 +1,49 @@
+package bucket
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// GetBucketVersioning handles GET /{bucket}?versioning
+func GetBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+
+	// Check if bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	status, err := store.Queries.GetBucketVersioning(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// A bucket that never had versioning enabled returns no Status
+	config := VersioningConfiguration{
+		Xmlns:  "http://s3.amazonaws.com/doc/2006-03-01/",
+		Status: status.String,
+	}
+
+	output, err := xml.MarshalIndent(config, "", "  ")
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	w.Write(output)
+}
@@ -1,12 +1,15 @@
 package bucket
 
 import (
+	"context"
 	"database/sql"
 	"encoding/xml"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/s3error"
 )
@@ -36,7 +39,7 @@ func PutBucketNotificationConfiguration(w http.ResponseWriter, r *http.Request)
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
+		s3error.FromError(err).WriteError(w)
 		return
 	}
 
@@ -46,122 +49,183 @@ func PutBucketNotificationConfiguration(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Delete existing notifications for this bucket
-	// Get existing notifications first
-	existingNotifications, err := store.Queries.ListNotificationsByBucket(r.Context(), bucketName)
-	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
+	rules, s3Err := notificationRules(bucketName, notificationConfig)
+	if s3Err != nil {
+		s3Err.WriteError(w)
 		return
 	}
 
-	// Delete all existing notifications
-	for _, notification := range existingNotifications {
-		if err := store.Queries.DeleteNotification(r.Context(), notification.ID); err != nil {
-			s3error.NewInternalError(err).WriteError(w)
-			return
+	// Replace the notifications of this bucket set through the API. Rules of
+	// config.yaml are kept; the request may repeat them but not overlap them.
+	// Unchanged rules are kept along with their pending jobs.
+	err = store.ExecTx(r.Context(), func(q *db.Queries) error {
+		existingNotifications, err := q.ListNotificationsByBucket(r.Context(), bucketName)
+		if err != nil {
+			return err
+		}
+		var managed []db.CreateNotificationParams
+		kept := make(map[db.CreateNotificationParams]bool)
+		for _, notification := range existingNotifications {
+			params := notificationParams(notification)
+			if notification.ManagedBy == managedByConfig {
+				managed = append(managed, params)
+				continue
+			}
+			if containsRule(rules, params) && !kept[params] {
+				kept[params] = true
+				continue
+			}
+			if err := q.DeleteNotification(r.Context(), notification.ID); err != nil {
+				return err
+			}
 		}
-	}
 
-	// Insert new QueueConfigurations
-	for _, queueConfig := range notificationConfig.QueueConfigurations {
-		for _, event := range queueConfig.Events {
-			var filterPrefix sql.NullString
-			var filterSuffix sql.NullString
-
-			if queueConfig.Filter.S3Key.FilterRules != nil {
-				for _, rule := range queueConfig.Filter.S3Key.FilterRules {
-					if rule.Name == "prefix" {
-						filterPrefix = sql.NullString{String: rule.Value, Valid: true}
-					}
-					if rule.Name == "suffix" {
-						filterSuffix = sql.NullString{String: rule.Value, Valid: true}
-					}
+		var created []db.Notification
+		for _, rule := range rules {
+			if containsRule(managed, rule) || kept[rule] {
+				continue
+			}
+			for _, m := range managed {
+				if rulesOverlap(rule, m) {
+					return s3error.NewInvalidArgumentError("Configurations overlap. The configuration overlaps a rule managed by the S3Local config.yaml.")
 				}
 			}
-
-			_, err := store.Queries.CreateNotification(r.Context(), db.CreateNotificationParams{
-				BucketName:      bucketName,
-				EventType:       event,
-				DestinationType: "sqs",
-				DestinationArn:  queueConfig.Queue,
-				FilterPrefix:    filterPrefix,
-				FilterSuffix:    filterSuffix,
-				Enabled:         true,
-			})
+			notification, err := q.CreateNotification(r.Context(), rule)
 			if err != nil {
-				s3error.NewInternalError(err).WriteError(w)
-				return
+				return err
 			}
+			created = append(created, notification)
 		}
+		return recordTestEvents(r.Context(), q, created)
+	})
+	if err != nil {
+		s3error.FromError(err).WriteError(w)
+		return
 	}
 
-	// Insert new TopicConfigurations
-	for _, topicConfig := range notificationConfig.TopicConfigurations {
-		for _, event := range topicConfig.Events {
-			var filterPrefix sql.NullString
-			var filterSuffix sql.NullString
-
-			if topicConfig.Filter.S3Key.FilterRules != nil {
-				for _, rule := range topicConfig.Filter.S3Key.FilterRules {
-					if rule.Name == "prefix" {
-						filterPrefix = sql.NullString{String: rule.Value, Valid: true}
-					}
-					if rule.Name == "suffix" {
-						filterSuffix = sql.NullString{String: rule.Value, Valid: true}
-					}
-				}
-			}
+	w.WriteHeader(http.StatusOK)
+}
+
+// The rule stored for an EventBridgeConfiguration matches every event type and
+// sends to the default event bus
+const (
+	eventBridgeEventType = "s3:*"
+	eventBridgeBusArn    = "arn:aws:events:us-east-1:000000000000:event-bus/default"
+)
+
+// recordTestEvents queues a test message for every queue and topic among the
+// destinations of notifications, like S3 does when a configuration is saved
+func recordTestEvents(ctx context.Context, q *db.Queries, notifications []db.Notification) error {
+	tested := map[string]bool{}
+	for _, notification := range notifications {
+		isQueueOrTopic := notification.DestinationType == "sqs" || notification.DestinationType == "sns"
+		if !isQueueOrTopic || tested[notification.DestinationArn] {
+			continue
+		}
+		tested[notification.DestinationArn] = true
+		if err := event.RecordTest(ctx, q, notification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-			_, err := store.Queries.CreateNotification(r.Context(), db.CreateNotificationParams{
+// notificationRules flattens a notification configuration into one rule per
+// event type. Like S3 it rejects unsupported event types, invalid filters and
+// rules that could both match the same event.
+func notificationRules(bucketName string, config NotificationConfiguration) ([]db.CreateNotificationParams, *s3error.Error) {
+	var rules []db.CreateNotificationParams
+	add := func(destinationType, destinationArn string, events []string, filter NotificationFilter) *s3error.Error {
+		filterPrefix, filterSuffix, s3Err := parseFilterRules(filter.S3Key.FilterRules)
+		if s3Err != nil {
+			return s3Err
+		}
+		for _, eventType := range events {
+			if !event.Supported(eventType) {
+				return s3error.NewInvalidArgumentError("The event is not supported for notifications")
+			}
+			rules = append(rules, db.CreateNotificationParams{
 				BucketName:      bucketName,
-				EventType:       event,
-				DestinationType: "sns",
-				DestinationArn:  topicConfig.Topic,
+				EventType:       eventType,
+				DestinationType: destinationType,
+				DestinationArn:  destinationArn,
 				FilterPrefix:    filterPrefix,
 				FilterSuffix:    filterSuffix,
 				Enabled:         true,
 			})
-			if err != nil {
-				s3error.NewInternalError(err).WriteError(w)
-				return
-			}
 		}
+		return nil
 	}
 
-	// Insert new LambdaFunctionConfigurations
-	for _, lambdaConfig := range notificationConfig.LambdaFunctionConfigurations {
-		for _, event := range lambdaConfig.Events {
-			var filterPrefix sql.NullString
-			var filterSuffix sql.NullString
-
-			if lambdaConfig.Filter.S3Key.FilterRules != nil {
-				for _, rule := range lambdaConfig.Filter.S3Key.FilterRules {
-					if rule.Name == "prefix" {
-						filterPrefix = sql.NullString{String: rule.Value, Valid: true}
-					}
-					if rule.Name == "suffix" {
-						filterSuffix = sql.NullString{String: rule.Value, Valid: true}
-					}
-				}
+	for _, queueConfig := range config.QueueConfigurations {
+		if s3Err := add("sqs", queueConfig.Queue, queueConfig.Events, queueConfig.Filter); s3Err != nil {
+			return nil, s3Err
+		}
+	}
+	for _, topicConfig := range config.TopicConfigurations {
+		if s3Err := add("sns", topicConfig.Topic, topicConfig.Events, topicConfig.Filter); s3Err != nil {
+			return nil, s3Err
+		}
+	}
+	for _, lambdaConfig := range config.LambdaFunctionConfigurations {
+		if s3Err := add("lambda", lambdaConfig.LambdaFunctionArn, lambdaConfig.Events, lambdaConfig.Filter); s3Err != nil {
+			return nil, s3Err
+		}
+	}
+
+	// EventBridge receives every event, whatever the other rules are
+	if config.EventBridgeConfiguration != nil {
+		rules = append(rules, db.CreateNotificationParams{
+			BucketName:      bucketName,
+			EventType:       eventBridgeEventType,
+			DestinationType: "eventbridge",
+			DestinationArn:  eventBridgeBusArn,
+			Enabled:         true,
+		})
+	}
+
+	for i := range rules {
+		for j := i + 1; j < len(rules); j++ {
+			if rulesOverlap(rules[i], rules[j]) {
+				return nil, s3error.NewInvalidArgumentError("Configurations overlap. Configurations on the same bucket cannot share a common event type.")
 			}
+		}
+	}
+	return rules, nil
+}
 
-			_, err := store.Queries.CreateNotification(r.Context(), db.CreateNotificationParams{
-				BucketName:      bucketName,
-				EventType:       event,
-				DestinationType: "lambda",
-				DestinationArn:  lambdaConfig.LambdaFunctionArn,
-				FilterPrefix:    filterPrefix,
-				FilterSuffix:    filterSuffix,
-				Enabled:         true,
-			})
-			if err != nil {
-				s3error.NewInternalError(err).WriteError(w)
-				return
+// parseFilterRules returns the prefix and suffix of an S3Key filter. Rule
+// names are case-insensitive and each may appear once.
+func parseFilterRules(filterRules []FilterRule) (prefix, suffix sql.NullString, s3Err *s3error.Error) {
+	for _, rule := range filterRules {
+		switch strings.ToLower(rule.Name) {
+		case "prefix":
+			if prefix.Valid {
+				return prefix, suffix, s3error.NewInvalidArgumentError("Cannot specify more than one prefix rule in a filter.")
 			}
+			prefix = sql.NullString{String: rule.Value, Valid: true}
+		case "suffix":
+			if suffix.Valid {
+				return prefix, suffix, s3error.NewInvalidArgumentError("Cannot specify more than one suffix rule in a filter.")
+			}
+			suffix = sql.NullString{String: rule.Value, Valid: true}
+		default:
+			return prefix, suffix, s3error.NewInvalidArgumentError("filter rule name must be either prefix or suffix")
 		}
 	}
+	return prefix, suffix, nil
+}
 
-	w.WriteHeader(http.StatusOK)
+// rulesOverlap reports whether an object event could match both rules. The
+// EventBridge rule overlaps no other rule.
+func rulesOverlap(a, b db.CreateNotificationParams) bool {
+	if a.DestinationType == "eventbridge" || b.DestinationType == "eventbridge" {
+		return false
+	}
+	return event.RulesOverlap(
+		event.Rule{EventType: a.EventType, Prefix: a.FilterPrefix.String, Suffix: a.FilterSuffix.String},
+		event.Rule{EventType: b.EventType, Prefix: b.FilterPrefix.String, Suffix: b.FilterSuffix.String},
+	)
 }
 
 // NotificationConfiguration represents the S3 notification configuration XML structure
@@ -170,8 +234,13 @@ type NotificationConfiguration struct {
 	QueueConfigurations          []QueueConfiguration          `xml:"QueueConfiguration"`
 	TopicConfigurations          []TopicConfiguration          `xml:"TopicConfiguration"`
 	LambdaFunctionConfigurations []LambdaFunctionConfiguration `xml:"CloudFunctionConfiguration"`
+	EventBridgeConfiguration     *EventBridgeConfiguration     `xml:"EventBridgeConfiguration"`
 }
 
+// EventBridgeConfiguration enables sending every event of the bucket to
+// EventBridge
+type EventBridgeConfiguration struct{}
+
 type QueueConfiguration struct {
 	Id     string             `xml:"Id,omitempty"`
 	Queue  string             `xml:"Queue"`
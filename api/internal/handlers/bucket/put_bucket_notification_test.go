@@ -0,0 +1,199 @@
+package bucket
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestPutBucketNotificationConfiguration(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Use(ctx.WithStore(store))
+		r.Put("/", PutBucketNotificationConfiguration)
+		r.Get("/", GetBucketNotificationConfiguration)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	queue := func(arn string, prefix, suffix string, events ...types.Event) types.QueueConfiguration {
+		config := types.QueueConfiguration{QueueArn: aws.String(arn), Events: events}
+		var rules []types.FilterRule
+		if prefix != "" {
+			rules = append(rules, types.FilterRule{Name: types.FilterRuleNamePrefix, Value: aws.String(prefix)})
+		}
+		if suffix != "" {
+			rules = append(rules, types.FilterRule{Name: types.FilterRuleNameSuffix, Value: aws.String(suffix)})
+		}
+		if rules != nil {
+			config.Filter = &types.NotificationConfigurationFilter{Key: &types.S3KeyFilter{FilterRules: rules}}
+		}
+		return config
+	}
+
+	putQueues := func(configs ...types.QueueConfiguration) error {
+		_, err := s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket:                    aws.String("test-bucket"),
+			NotificationConfiguration: &types.NotificationConfiguration{QueueConfigurations: configs},
+		})
+		return err
+	}
+
+	testJobs := func() int {
+		var n int
+		err := store.DB.QueryRow(`SELECT COUNT(*) FROM notification_jobs j
+			JOIN events e ON e.id = j.event_id
+			WHERE e.event_type = 's3:TestEvent'`).Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+
+	errorCode := func(err error) string {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			return apiErr.ErrorCode()
+		}
+		return ""
+	}
+
+	t.Run("Rules with disjoint filters", func(t *testing.T) {
+		err := putQueues(
+			queue("http://localhost/images", "images/", ".jpg", "s3:ObjectCreated:*"),
+			queue("http://localhost/videos", "videos/", "", "s3:ObjectCreated:*"),
+			queue("http://localhost/removed", "images/", "", "s3:ObjectRemoved:*"),
+		)
+		require.NoError(t, err)
+
+		resp, err := s3Client.GetBucketNotificationConfiguration(context.Background(), &s3.GetBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+		})
+		require.NoError(t, err)
+		assert.Len(t, resp.QueueConfigurations, 3)
+	})
+
+	t.Run("Reject overlapping rules", func(t *testing.T) {
+		for name, configs := range map[string][]types.QueueConfiguration{
+			"wildcard and event type": {
+				queue("http://localhost/a", "", "", "s3:ObjectCreated:*"),
+				queue("http://localhost/b", "", "", "s3:ObjectCreated:Put"),
+			},
+			"nested prefixes": {
+				queue("http://localhost/a", "images/", "", "s3:ObjectCreated:Put"),
+				queue("http://localhost/b", "images/2024/", ".png", "s3:ObjectCreated:Put"),
+			},
+			"nested suffixes": {
+				queue("http://localhost/a", "", ".jpg", "s3:ObjectRemoved:Delete"),
+				queue("http://localhost/b", "", "thumb.jpg", "s3:ObjectRemoved:*"),
+			},
+		} {
+			err := putQueues(configs...)
+			require.Error(t, err, name)
+			assert.Equal(t, "InvalidArgument", errorCode(err), name)
+		}
+
+		// The previous configuration is kept
+		resp, err := s3Client.GetBucketNotificationConfiguration(context.Background(), &s3.GetBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+		})
+		require.NoError(t, err)
+		assert.Len(t, resp.QueueConfigurations, 3)
+	})
+
+	t.Run("Reject unsupported events", func(t *testing.T) {
+		err := putQueues(queue("http://localhost/a", "", "", "s3:ObjectCreated:Delete"))
+		require.Error(t, err)
+		assert.Equal(t, "InvalidArgument", errorCode(err))
+	})
+
+	t.Run("EventBridge alongside other rules", func(t *testing.T) {
+		_, err := s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+			NotificationConfiguration: &types.NotificationConfiguration{
+				QueueConfigurations:      []types.QueueConfiguration{queue("http://localhost/a", "", "", "s3:ObjectCreated:*")},
+				EventBridgeConfiguration: &types.EventBridgeConfiguration{},
+			},
+		})
+		require.NoError(t, err)
+
+		resp, err := s3Client.GetBucketNotificationConfiguration(context.Background(), &s3.GetBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+		})
+		require.NoError(t, err)
+		assert.NotNil(t, resp.EventBridgeConfiguration)
+		assert.Len(t, resp.QueueConfigurations, 1)
+
+		// Leaving it out disables EventBridge
+		err = putQueues(queue("http://localhost/a", "", "", "s3:ObjectCreated:*"))
+		require.NoError(t, err)
+		resp, err = s3Client.GetBucketNotificationConfiguration(context.Background(), &s3.GetBucketNotificationConfigurationInput{
+			Bucket: aws.String("test-bucket"),
+		})
+		require.NoError(t, err)
+		assert.Nil(t, resp.EventBridgeConfiguration)
+	})
+
+	t.Run("Queue a test message for every queue and topic", func(t *testing.T) {
+		configuration := &types.NotificationConfiguration{
+			QueueConfigurations: []types.QueueConfiguration{
+				queue("http://localhost/a", "", "", "s3:ObjectCreated:*"),
+				queue("http://localhost/a", "", "", "s3:ObjectRemoved:*"),
+				queue("http://localhost/b", "", "", "s3:ObjectTagging:*"),
+			},
+			TopicConfigurations: []types.TopicConfiguration{{
+				TopicArn: aws.String("arn:aws:sns:us-east-1:000000000000:topic"),
+				Events:   []types.Event{"s3:ObjectRestore:*"},
+			}},
+			LambdaFunctionConfigurations: []types.LambdaFunctionConfiguration{{
+				LambdaFunctionArn: aws.String("arn:aws:lambda:us-east-1:000000000000:function:f"),
+				Events:            []types.Event{"s3:LifecycleExpiration:*"},
+			}},
+		}
+		_, err := s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket:                    aws.String("test-bucket"),
+			NotificationConfiguration: configuration,
+		})
+		require.NoError(t, err)
+		// The queue kept from the previous configuration keeps its test
+		// message, the new queues and topic get one, and functions get none
+		assert.Equal(t, 4, testJobs())
+
+		// Putting the same configuration again keeps every rule and its jobs
+		before, err := store.Queries.ListNotificationsByBucket(context.Background(), "test-bucket")
+		require.NoError(t, err)
+		_, err = s3Client.PutBucketNotificationConfiguration(context.Background(), &s3.PutBucketNotificationConfigurationInput{
+			Bucket:                    aws.String("test-bucket"),
+			NotificationConfiguration: configuration,
+		})
+		require.NoError(t, err)
+		after, err := store.Queries.ListNotificationsByBucket(context.Background(), "test-bucket")
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+		assert.Equal(t, 4, testJobs())
+	})
+}
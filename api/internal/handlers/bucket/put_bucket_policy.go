@@ -35,7 +35,7 @@ func PutBucketPolicy(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
+		s3error.FromError(err).WriteError(w)
 		return
 	}
 
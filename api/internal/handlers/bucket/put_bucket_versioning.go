@@ -0,0 +1,73 @@
+package bucket
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// PutBucketVersioning handles PUT /{bucket}?versioning
+func PutBucketVersioning(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+
+	// Check if bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	// Parse XML body
+	if r.Body == nil || r.ContentLength == 0 {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s3error.FromError(err).WriteError(w)
+		return
+	}
+
+	var config VersioningConfiguration
+	if err := xml.Unmarshal(body, &config); err != nil {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+
+	// Once enabled, versioning can only be suspended, never turned off
+	if config.Status != "Enabled" && config.Status != "Suspended" {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+
+	err = store.Queries.UpdateBucketVersioning(r.Context(), db.UpdateBucketVersioningParams{
+		VersioningStatus: sql.NullString{String: config.Status, Valid: true},
+		Name:             bucketName,
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// VersioningConfiguration represents the S3 bucket versioning configuration
+type VersioningConfiguration struct {
+	XMLName   xml.Name `xml:"VersioningConfiguration"`
+	Xmlns     string   `xml:"xmlns,attr,omitempty"`
+	Status    string   `xml:"Status,omitempty"`
+	MfaDelete string   `xml:"MfaDelete,omitempty"`
+}
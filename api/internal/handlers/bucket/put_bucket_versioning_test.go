@@ -0,0 +1,86 @@
+package bucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestPutBucketVersioning(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	assert.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Use(ctx.WithStore(store))
+		r.Put("/", PutBucketVersioning)
+		r.Get("/", GetBucketVersioning)
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	getStatus := func(t *testing.T) string {
+		resp, err := http.Get(ts.URL + "/test-bucket?versioning")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		body, _ := io.ReadAll(resp.Body)
+		var config VersioningConfiguration
+		require.NoError(t, xml.Unmarshal(body, &config))
+		return config.Status
+	}
+
+	putStatus := func(t *testing.T, bucket, status string) int {
+		body := `<VersioningConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Status>` + status + `</Status></VersioningConfiguration>`
+		req, _ := http.NewRequest("PUT", ts.URL+"/"+bucket+"?versioning", bytes.NewBufferString(body))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		// Buckets start without a versioning state
+		assert.Equal(t, "", getStatus(t))
+
+		assert.Equal(t, http.StatusOK, putStatus(t, "test-bucket", "Enabled"))
+		assert.Equal(t, "Enabled", getStatus(t))
+
+		assert.Equal(t, http.StatusOK, putStatus(t, "test-bucket", "Suspended"))
+		assert.Equal(t, "Suspended", getStatus(t))
+	})
+
+	t.Run("Invalid status", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, putStatus(t, "test-bucket", "Disabled"))
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, putStatus(t, "nonexistent-bucket", "Enabled"))
+
+		resp, err := http.Get(ts.URL + "/nonexistent-bucket?versioning")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
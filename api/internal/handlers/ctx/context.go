@@ -15,6 +15,7 @@ const (
 	// StoreKey is exported for testing purposes
 	StoreKey      ctxKey = "store"
 	cfgKey        ctxKey = "cfg"
+	workerKey     ctxKey = "worker"
 	bucketNameKey ctxKey = "bucketName"
 	objectKeyKey  ctxKey = "objectKey"
 )
@@ -53,6 +54,33 @@ func GetConfig(ctx context.Context) *config.Config {
 	return nil
 }
 
+// Worker is the notification worker as controlled by the admin API
+type Worker interface {
+	// Wake makes the worker look for due jobs now instead of at the next tick
+	Wake()
+	// Tick claims the jobs that are due now and waits until they are sent. It
+	// returns the number of jobs sent.
+	Tick(ctx context.Context) (int, error)
+}
+
+// WithWorker injects the notification worker into request context
+func WithWorker(worker Worker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), workerKey, worker)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GetWorker retrieves the notification worker from context
+func GetWorker(ctx context.Context) Worker {
+	if w, ok := ctx.Value(workerKey).(Worker); ok {
+		return w
+	}
+	return nil
+}
+
 func WithBucketName() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
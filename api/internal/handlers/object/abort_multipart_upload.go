@@ -0,0 +1,41 @@
+package object
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// AbortMultipartUpload handles DELETE /{bucket}/{key}?uploadId={uploadId}
+func AbortMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+	uploadID := r.URL.Query().Get("uploadId")
+
+	_, err := store.Queries.GetMultipartUpload(r.Context(), db.GetMultipartUploadParams{
+		UploadID:   uploadID,
+		BucketName: bucketName,
+		Key:        objectKey,
+	})
+	if err == sql.ErrNoRows {
+		s3error.NewNoSuchUploadError(uploadID).WriteError(w)
+		return
+	}
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Uploaded parts are removed by ON DELETE CASCADE
+	if err := store.Queries.DeleteMultipartUpload(r.Context(), uploadID); err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	store.ReleaseBlobs(r.Context())
+
+	w.WriteHeader(http.StatusNoContent)
+}
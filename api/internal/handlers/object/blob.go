@@ -0,0 +1,97 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"io"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+// openBlob opens the payload of an object or part in the blob store.
+// Rows without a blob, such as delete markers, have an empty payload.
+func openBlob(ctx context.Context, store *db.Store, blobID sql.NullString) (io.ReadSeekCloser, error) {
+	if !blobID.Valid {
+		return nopReadSeekCloser{bytes.NewReader(nil)}, nil
+	}
+	return store.Blobs.Open(ctx, blobID.String)
+}
+
+// nopReadSeekCloser adds a no-op Close to a bytes.Reader
+type nopReadSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopReadSeekCloser) Close() error { return nil }
+
+// sectionReader returns a reader for length bytes of rs starting at offset
+func sectionReader(rs io.ReadSeeker, offset, length int64) (io.Reader, error) {
+	if _, err := rs.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.LimitReader(rs, length), nil
+}
+
+// blobChecksum computes a checksum by streaming a stored blob
+func blobChecksum(ctx context.Context, store *db.Store, blobID string, alg checksum.Algorithm) (string, error) {
+	rc, err := store.Blobs.Open(ctx, blobID)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	h := checksum.New(alg)
+	if _, err := io.Copy(h, rc); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// blobsReader reads several blobs one after another. Each blob is only opened
+// once the previous one has been read, so a single blob is open at a time.
+type blobsReader struct {
+	ctx     context.Context
+	store   *db.Store
+	blobIDs []sql.NullString
+	current io.ReadCloser
+}
+
+func newBlobsReader(ctx context.Context, store *db.Store, blobIDs []sql.NullString) *blobsReader {
+	return &blobsReader{ctx: ctx, store: store, blobIDs: blobIDs}
+}
+
+func (b *blobsReader) Read(p []byte) (int, error) {
+	for {
+		if b.current == nil {
+			if len(b.blobIDs) == 0 {
+				return 0, io.EOF
+			}
+			rc, err := openBlob(b.ctx, b.store, b.blobIDs[0])
+			if err != nil {
+				return 0, err
+			}
+			b.current, b.blobIDs = rc, b.blobIDs[1:]
+		}
+
+		n, err := b.current.Read(p)
+		if err == io.EOF {
+			b.current.Close()
+			b.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+func (b *blobsReader) Close() error {
+	if b.current != nil {
+		return b.current.Close()
+	}
+	return nil
+}
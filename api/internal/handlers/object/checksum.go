@@ -0,0 +1,192 @@
+package object
+
+import (
+	"bytes"
+	"crypto/md5"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// verifyIntegrity checks the body against Content-MD5 and the x-amz-checksum-*
+// header or trailer. It returns the checksum sent by the client, if any.
+// Trailers are only populated once the body has been read.
+func verifyIntegrity(r *http.Request, data []byte) (*checksum.Checksum, *s3error.Error) {
+	sum := md5.Sum(data)
+	return checkIntegrity(r, sum[:], func(alg checksum.Algorithm) (string, error) {
+		return checksum.Compute(alg, data), nil
+	})
+}
+
+// verifyStreamedIntegrity checks a body streamed through d into the blob store
+// against Content-MD5 and the x-amz-checksum-* header or trailer. Checksums d
+// did not compute, such as a trailer that was not announced in x-amz-trailer,
+// are computed by reading the stored blob back.
+func verifyStreamedIntegrity(r *http.Request, d *digestReader, store *db.Store, blobID string) (*checksum.Checksum, *s3error.Error) {
+	return checkIntegrity(r, d.md5.Sum(nil), func(alg checksum.Algorithm) (string, error) {
+		if value, ok := d.checksum(alg); ok {
+			return value, nil
+		}
+		return blobChecksum(r.Context(), store, blobID, alg)
+	})
+}
+
+// checkIntegrity compares the MD5 of the body with Content-MD5 and the value
+// returned by compute with the x-amz-checksum-* header or trailer
+func checkIntegrity(r *http.Request, md5Sum []byte, compute func(checksum.Algorithm) (string, error)) (*checksum.Checksum, *s3error.Error) {
+	if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+		expected, err := base64.StdEncoding.DecodeString(contentMD5)
+		if err != nil || len(expected) != md5.Size {
+			return nil, s3error.NewInvalidDigestError()
+		}
+		if !bytes.Equal(expected, md5Sum) {
+			return nil, s3error.NewBadDigestError()
+		}
+	}
+
+	c, ok := checksum.FromRequest(r)
+	if !ok {
+		return nil, s3error.NewInvalidRequestError("Expecting a single x-amz-checksum- header. Multiple checksum Types are not allowed.")
+	}
+	if c == nil {
+		return nil, nil
+	}
+	value, err := compute(c.Algorithm)
+	if err != nil {
+		return nil, s3error.NewInternalError(err)
+	}
+	if value != c.Value {
+		return nil, s3error.NewXAmzContentChecksumMismatchError(checksum.HeaderName(c.Algorithm))
+	}
+	return c, nil
+}
+
+// requestedAlgorithms returns the checksum algorithms the client announced
+// with x-amz-checksum-* headers, x-amz-trailer or x-amz-sdk-checksum-algorithm,
+// so they can be computed while the body streams
+func requestedAlgorithms(r *http.Request) []checksum.Algorithm {
+	trailer := strings.ToLower(r.Header.Get("x-amz-trailer"))
+	var algorithms []checksum.Algorithm
+	for _, alg := range checksum.Algorithms {
+		name := checksum.HeaderName(alg)
+		if r.Header.Get(name) != "" || strings.Contains(trailer, name) ||
+			strings.EqualFold(r.Header.Get("x-amz-sdk-checksum-algorithm"), string(alg)) {
+			algorithms = append(algorithms, alg)
+		}
+	}
+	return algorithms
+}
+
+// digestReader computes the MD5 and the given additional checksums of the
+// data read through it
+type digestReader struct {
+	r      io.Reader
+	md5    hash.Hash
+	hashes map[checksum.Algorithm]hash.Hash
+}
+
+func newDigestReader(r io.Reader, algorithms ...checksum.Algorithm) *digestReader {
+	d := &digestReader{
+		r:      r,
+		md5:    md5.New(),
+		hashes: make(map[checksum.Algorithm]hash.Hash, len(algorithms)),
+	}
+	for _, alg := range algorithms {
+		d.hashes[alg] = checksum.New(alg)
+	}
+	return d
+}
+
+func (d *digestReader) Read(b []byte) (int, error) {
+	n, err := d.r.Read(b)
+	if n > 0 {
+		d.md5.Write(b[:n])
+		for _, h := range d.hashes {
+			h.Write(b[:n])
+		}
+	}
+	return n, err
+}
+
+// etag returns the hex encoded MD5 of the data read so far
+func (d *digestReader) etag() string {
+	return hex.EncodeToString(d.md5.Sum(nil))
+}
+
+// checksum returns the base64 encoded checksum of the data read so far, or
+// false when the algorithm is not computed
+func (d *digestReader) checksum(alg checksum.Algorithm) (string, bool) {
+	h, ok := d.hashes[alg]
+	if !ok {
+		return "", false
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), true
+}
+
+// checksumModeEnabled reports whether the client asked for checksums with
+// x-amz-checksum-mode: ENABLED
+func checksumModeEnabled(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("x-amz-checksum-mode"), "ENABLED")
+}
+
+// writeChecksumHeaders sets x-amz-checksum-* and x-amz-checksum-type when the
+// object has a checksum
+func writeChecksumHeaders(w http.ResponseWriter, algorithm, value, checksumType sql.NullString) {
+	if !algorithm.Valid || !value.Valid {
+		return
+	}
+	alg, ok := checksum.ParseAlgorithm(algorithm.String)
+	if !ok {
+		return
+	}
+	w.Header().Set(checksum.HeaderName(alg), value.String)
+	if checksumType.Valid {
+		w.Header().Set("x-amz-checksum-type", checksumType.String)
+	}
+}
+
+// writeObjectChecksumHeaders returns the stored checksum of the object, or of
+// the requested part, when x-amz-checksum-mode is ENABLED
+func writeObjectChecksumHeaders(w http.ResponseWriter, r *http.Request, obj db.Object, part *objectPart) {
+	if !checksumModeEnabled(r) {
+		return
+	}
+	if part != nil && part.ChecksumValue.Valid {
+		writeChecksumHeaders(w, obj.ChecksumAlgorithm, part.ChecksumValue, sql.NullString{})
+		return
+	}
+	writeChecksumHeaders(w, obj.ChecksumAlgorithm, obj.ChecksumValue, obj.ChecksumType)
+}
+
+// Checksums holds the Checksum* elements of S3 XML responses
+type Checksums struct {
+	ChecksumCRC32     string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C    string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumCRC64NVME string `xml:"ChecksumCRC64NVME,omitempty"`
+	ChecksumSHA1      string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256    string `xml:"ChecksumSHA256,omitempty"`
+}
+
+// set sets the element matching the algorithm
+func (c *Checksums) set(alg checksum.Algorithm, value string) {
+	switch alg {
+	case checksum.CRC32:
+		c.ChecksumCRC32 = value
+	case checksum.CRC32C:
+		c.ChecksumCRC32C = value
+	case checksum.CRC64NVME:
+		c.ChecksumCRC64NVME = value
+	case checksum.SHA1:
+		c.ChecksumSHA1 = value
+	case checksum.SHA256:
+		c.ChecksumSHA256 = value
+	}
+}
@@ -0,0 +1,322 @@
+package object
+
+import (
+	"bytes"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// CompleteMultipartUpload handles POST /{bucket}/{key}?uploadId={uploadId}
+func CompleteMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+	uploadID := r.URL.Query().Get("uploadId")
+
+	upload, err := store.Queries.GetMultipartUpload(r.Context(), db.GetMultipartUploadParams{
+		UploadID:   uploadID,
+		BucketName: bucketName,
+		Key:        objectKey,
+	})
+	if err == sql.ErrNoRows {
+		s3error.NewNoSuchUploadError(uploadID).WriteError(w)
+		return
+	}
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Parse XML body
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s3error.FromError(err).WriteError(w)
+		return
+	}
+
+	var request CompleteMultipartUploadRequest
+	if err := xml.Unmarshal(body, &request); err != nil || len(request.Parts) == 0 {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+
+	// Parts must be listed in ascending order without duplicates
+	for i := 1; i < len(request.Parts); i++ {
+		if request.Parts[i].PartNumber <= request.Parts[i-1].PartNumber {
+			s3error.NewInvalidPartOrderError().WriteError(w)
+			return
+		}
+	}
+
+	uploaded, err := store.Queries.GetMultipartUploadPartsWithBlobs(r.Context(), uploadID)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	partsByNumber := make(map[int64]db.GetMultipartUploadPartsWithBlobsRow, len(uploaded))
+	for _, part := range uploaded {
+		partsByNumber[part.PartNumber] = part
+	}
+
+	// Validate the requested parts
+	var etags bytes.Buffer
+	parts := make([]db.GetMultipartUploadPartsWithBlobsRow, 0, len(request.Parts))
+	for i, requested := range request.Parts {
+		part, ok := partsByNumber[requested.PartNumber]
+		if !ok || strings.Trim(requested.ETag, `"`) != part.ETag {
+			s3error.NewInvalidPartError().WriteError(w)
+			return
+		}
+		// A part checksum sent by the client must match the uploaded part
+		if value := requested.checksum(); value != "" && value != part.ChecksumValue.String {
+			s3error.NewInvalidPartError().WriteError(w)
+			return
+		}
+		// Every part except the last must be at least 5 MiB
+		if i < len(request.Parts)-1 && part.Size < minPartSize {
+			s3error.NewEntityTooSmallError().WriteError(w)
+			return
+		}
+
+		digest, err := hex.DecodeString(part.ETag)
+		if err != nil {
+			s3error.NewInternalError(err).WriteError(w)
+			return
+		}
+		etags.Write(digest)
+		parts = append(parts, part)
+	}
+
+	// Composite ETag: MD5 of the concatenated part MD5s, suffixed with the part count
+	hash := md5.Sum(etags.Bytes())
+	etag := fmt.Sprintf("%s-%d", hex.EncodeToString(hash[:]), len(parts))
+
+	// Assemble the object by streaming the part blobs into a new blob. The part
+	// blobs are released when the upload is deleted.
+	alg, hasAlgorithm := checksum.ParseAlgorithm(upload.ChecksumAlgorithm.String)
+	fullObject := hasAlgorithm && checksum.Type(upload.ChecksumType.String) == checksum.TypeFullObject
+	blobIDs := make([]sql.NullString, len(parts))
+	for i, part := range parts {
+		blobIDs[i] = part.BlobID
+	}
+	partsReader := newBlobsReader(r.Context(), store, blobIDs)
+	defer partsReader.Close()
+	var content *digestReader
+	if fullObject {
+		content = newDigestReader(partsReader, alg)
+	} else {
+		content = newDigestReader(partsReader)
+	}
+	blobID, size, err := store.PutBlob(r.Context(), content)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Object checksum: over the part checksums (COMPOSITE) or the whole content (FULL_OBJECT)
+	var checksumValue sql.NullString
+	if hasAlgorithm {
+		if fullObject {
+			value, _ := content.checksum(alg)
+			checksumValue = toNullString(value)
+		} else {
+			partChecksums := make([]string, len(parts))
+			for i, part := range parts {
+				partChecksums[i] = part.ChecksumValue.String
+			}
+			value, err := checksum.Composite(alg, partChecksums)
+			if err != nil {
+				store.Blobs.Delete(r.Context(), blobID)
+				s3error.NewInvalidPartError().WriteError(w)
+				return
+			}
+			checksumValue = toNullString(value)
+		}
+
+		// The client may send the expected full object checksum
+		if expected := r.Header.Get(checksum.HeaderName(alg)); expected != "" && expected != checksumValue.String {
+			store.Blobs.Delete(r.Context(), blobID)
+			s3error.NewXAmzContentChecksumMismatchError(checksum.HeaderName(alg)).WriteError(w)
+			return
+		}
+	}
+
+	var metadata map[string]string
+	if err := json.Unmarshal([]byte(upload.Metadata), &metadata); err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(upload.Tags), &tags); err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	var versionID sql.NullString
+	err = store.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		versionID, err = prepareNewVersion(r.Context(), q, bucketName, objectKey)
+		if err != nil {
+			return err
+		}
+
+		objectID, err := q.UpsertObject(r.Context(), db.UpsertObjectParams{
+			BucketName:         bucketName,
+			Key:                objectKey,
+			BlobID:             toNullString(blobID),
+			Size:               size,
+			ETag:               etag,
+			ContentType:        upload.ContentType,
+			ContentEncoding:    upload.ContentEncoding,
+			ContentDisposition: upload.ContentDisposition,
+			CacheControl:       upload.CacheControl,
+			StorageClass:       upload.StorageClass,
+			VersionID:          versionID,
+			ChecksumAlgorithm:  upload.ChecksumAlgorithm,
+			ChecksumValue:      checksumValue,
+			ChecksumType:       upload.ChecksumType,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Replace metadata with the one given at CreateMultipartUpload
+		if err := q.DeleteObjectMetadata(r.Context(), objectID); err != nil {
+			return err
+		}
+		for k, v := range metadata {
+			if err := q.CreateObjectMetadata(r.Context(), db.CreateObjectMetadataParams{
+				ObjectID: objectID,
+				Key:      k,
+				Value:    v,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// An overwritten object keeps none of its tags
+		if err := q.DeleteObjectTags(r.Context(), objectID); err != nil {
+			return err
+		}
+		for k, v := range tags {
+			if err := q.CreateObjectTag(r.Context(), db.CreateObjectTagParams{
+				ObjectID: objectID,
+				Key:      k,
+				Value:    v,
+			}); err != nil {
+				return err
+			}
+		}
+
+		// Record the part layout so GetObject/HeadObject can serve partNumber.
+		// Parts are renumbered 1..N in the order they were assembled.
+		if err := q.DeleteObjectParts(r.Context(), objectID); err != nil {
+			return err
+		}
+		for i, part := range parts {
+			if err := q.CreateObjectPart(r.Context(), db.CreateObjectPartParams{
+				ObjectID:      objectID,
+				PartNumber:    int64(i + 1),
+				Size:          part.Size,
+				ETag:          part.ETag,
+				ChecksumValue: part.ChecksumValue,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if _, err := event.Record(r.Context(), q, db.CreateEventParams{
+			BucketName:      bucketName,
+			ObjectID:        sql.NullInt64{Int64: objectID, Valid: true},
+			EventType:       event.ObjectCreatedCompleteMultipartUpload,
+			ObjectKey:       objectKey,
+			ObjectSize:      size,
+			ObjectETag:      etag,
+			ObjectVersionID: versionID,
+		}); err != nil {
+			return err
+		}
+
+		return q.DeleteMultipartUpload(r.Context(), uploadID)
+	})
+	if err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	result := CompleteMultipartUploadResult{
+		Xmlns:    "http://s3.amazonaws.com/doc/2006-03-01/",
+		Location: fmt.Sprintf("/%s/%s", bucketName, objectKey),
+		Bucket:   bucketName,
+		Key:      objectKey,
+		ETag:     fmt.Sprintf(`"%s"`, etag),
+	}
+
+	if checksumValue.Valid {
+		alg, _ := checksum.ParseAlgorithm(upload.ChecksumAlgorithm.String)
+		result.Checksums.set(alg, checksumValue.String)
+		result.ChecksumType = upload.ChecksumType.String
+	}
+
+	writeVersionHeader(w, versionID)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// CompleteMultipartUploadRequest represents the S3 CompleteMultipartUpload request body
+type CompleteMultipartUploadRequest struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []CompletedPart `xml:"Part"`
+}
+
+// CompletedPart represents a part listed in the CompleteMultipartUpload request
+type CompletedPart struct {
+	PartNumber        int64  `xml:"PartNumber"`
+	ETag              string `xml:"ETag"`
+	ChecksumCRC32     string `xml:"ChecksumCRC32"`
+	ChecksumCRC32C    string `xml:"ChecksumCRC32C"`
+	ChecksumCRC64NVME string `xml:"ChecksumCRC64NVME"`
+	ChecksumSHA1      string `xml:"ChecksumSHA1"`
+	ChecksumSHA256    string `xml:"ChecksumSHA256"`
+}
+
+// checksum returns the part checksum sent by the client, if any
+func (p CompletedPart) checksum() string {
+	for _, value := range []string{p.ChecksumCRC32, p.ChecksumCRC32C, p.ChecksumCRC64NVME, p.ChecksumSHA1, p.ChecksumSHA256} {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// CompleteMultipartUploadResult represents the S3 CompleteMultipartUpload response
+type CompleteMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Location string   `xml:"Location"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	ETag     string   `xml:"ETag"`
+	Checksums
+	ChecksumType string `xml:"ChecksumType,omitempty"`
+}
@@ -0,0 +1,433 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestMultipartUpload(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Get("/", ListMultipartUploads)
+
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Post("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("uploads") {
+					CreateMultipartUpload(w, r)
+					return
+				}
+				CompleteMultipartUpload(w, r)
+			})
+			r.Put("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("uploadId") {
+					UploadPart(w, r)
+					return
+				}
+				PutObject(w, r)
+			})
+			r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("uploadId") {
+					ListParts(w, r)
+					return
+				}
+				GetObject(w, r)
+			})
+			r.Head("/*", HeadObject)
+			r.Delete("/*", AbortMultipartUpload)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	createUpload := func(t *testing.T, key string) string {
+		resp, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket:      aws.String("test-bucket"),
+			Key:         aws.String(key),
+			ContentType: aws.String("text/plain"),
+			Metadata:    map[string]string{"author": "tester"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, aws.ToString(resp.UploadId))
+		return aws.ToString(resp.UploadId)
+	}
+
+	uploadPart := func(t *testing.T, key, uploadID string, partNumber int32, data []byte) types.CompletedPart {
+		resp, err := s3Client.UploadPart(context.Background(), &s3.UploadPartInput{
+			Bucket:     aws.String("test-bucket"),
+			Key:        aws.String(key),
+			UploadId:   aws.String(uploadID),
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(data),
+		})
+		require.NoError(t, err)
+		return types.CompletedPart{ETag: resp.ETag, PartNumber: aws.Int32(partNumber)}
+	}
+
+	errorCode := func(err error) string {
+		var apiErr smithy.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			return apiErr.ErrorCode()
+		}
+		return ""
+	}
+
+	t.Run("Successfully complete a multipart upload", func(t *testing.T) {
+		part1 := bytes.Repeat([]byte("a"), minPartSize)
+		part2 := []byte("last part")
+
+		uploadID := createUpload(t, "large.txt")
+		completed := []types.CompletedPart{
+			uploadPart(t, "large.txt", uploadID, 1, part1),
+			uploadPart(t, "large.txt", uploadID, 2, part2),
+		}
+
+		// ListParts returns the uploaded parts
+		parts, err := s3Client.ListParts(context.Background(), &s3.ListPartsInput{
+			Bucket:   aws.String("test-bucket"),
+			Key:      aws.String("large.txt"),
+			UploadId: aws.String(uploadID),
+		})
+		require.NoError(t, err)
+		require.Len(t, parts.Parts, 2)
+		assert.Equal(t, int64(minPartSize), aws.ToInt64(parts.Parts[0].Size))
+
+		// ListMultipartUploads includes the in-progress upload
+		uploads, err := s3Client.ListMultipartUploads(context.Background(), &s3.ListMultipartUploadsInput{
+			Bucket: aws.String("test-bucket"),
+		})
+		require.NoError(t, err)
+		require.Len(t, uploads.Uploads, 1)
+		assert.Equal(t, uploadID, aws.ToString(uploads.Uploads[0].UploadId))
+
+		resp, err := s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("large.txt"),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.NoError(t, err)
+
+		// ETag is the MD5 of the concatenated part MD5s with the part count
+		md5a := md5.Sum(part1)
+		md5b := md5.Sum(part2)
+		combined := md5.Sum(append(md5a[:], md5b[:]...))
+		expectedETag := fmt.Sprintf(`"%s-2"`, hex.EncodeToString(combined[:]))
+		assert.Equal(t, expectedETag, aws.ToString(resp.ETag))
+
+		obj, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("large.txt"),
+		})
+		require.NoError(t, err)
+		body, _ := io.ReadAll(obj.Body)
+		obj.Body.Close()
+		assert.Equal(t, append(part1, part2...), body)
+		assert.Equal(t, "text/plain", aws.ToString(obj.ContentType))
+		assert.Equal(t, "tester", obj.Metadata["author"])
+
+		// The upload is gone once completed
+		uploads, err = s3Client.ListMultipartUploads(context.Background(), &s3.ListMultipartUploadsInput{
+			Bucket: aws.String("test-bucket"),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, uploads.Uploads)
+	})
+
+	t.Run("Get and head a single part", func(t *testing.T) {
+		obj, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:     aws.String("test-bucket"),
+			Key:        aws.String("large.txt"),
+			PartNumber: aws.Int32(2),
+		})
+		require.NoError(t, err)
+		body, _ := io.ReadAll(obj.Body)
+		obj.Body.Close()
+		assert.Equal(t, "last part", string(body))
+		assert.Equal(t, int32(2), aws.ToInt32(obj.PartsCount))
+		assert.Equal(t, fmt.Sprintf("bytes %d-%d/%d", minPartSize, minPartSize+8, minPartSize+9), aws.ToString(obj.ContentRange))
+
+		head, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:     aws.String("test-bucket"),
+			Key:        aws.String("large.txt"),
+			PartNumber: aws.Int32(1),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(minPartSize), aws.ToInt64(head.ContentLength))
+		assert.Equal(t, int32(2), aws.ToInt32(head.PartsCount))
+
+		_, err = s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:     aws.String("test-bucket"),
+			Key:        aws.String("large.txt"),
+			PartNumber: aws.Int32(3),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidPartNumber", errorCode(err))
+	})
+
+	t.Run("Reject undersized parts", func(t *testing.T) {
+		uploadID := createUpload(t, "small.txt")
+		completed := []types.CompletedPart{
+			uploadPart(t, "small.txt", uploadID, 1, []byte("too small")),
+			uploadPart(t, "small.txt", uploadID, 2, []byte("last")),
+		}
+
+		_, err := s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("small.txt"),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.Error(t, err)
+		assert.Equal(t, "EntityTooSmall", errorCode(err))
+	})
+
+	t.Run("Reject parts out of order", func(t *testing.T) {
+		uploadID := createUpload(t, "order.txt")
+		completed := []types.CompletedPart{
+			uploadPart(t, "order.txt", uploadID, 2, []byte("second")),
+			uploadPart(t, "order.txt", uploadID, 1, []byte("first")),
+		}
+
+		_, err := s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("order.txt"),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidPartOrder", errorCode(err))
+	})
+
+	t.Run("Reject unknown part ETag", func(t *testing.T) {
+		uploadID := createUpload(t, "etag.txt")
+		uploadPart(t, "etag.txt", uploadID, 1, []byte("content"))
+
+		_, err := s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:   aws.String("test-bucket"),
+			Key:      aws.String("etag.txt"),
+			UploadId: aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: []types.CompletedPart{
+				{ETag: aws.String(`"00000000000000000000000000000000"`), PartNumber: aws.Int32(1)},
+			}},
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidPart", errorCode(err))
+	})
+
+	t.Run("Composite checksum", func(t *testing.T) {
+		part1 := bytes.Repeat([]byte("b"), minPartSize)
+		part2 := []byte("checksummed")
+
+		created, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("composite.txt"),
+			ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, types.ChecksumTypeComposite, created.ChecksumType)
+
+		var completed []types.CompletedPart
+		for i, data := range [][]byte{part1, part2} {
+			resp, err := s3Client.UploadPart(context.Background(), &s3.UploadPartInput{
+				Bucket:            aws.String("test-bucket"),
+				Key:               aws.String("composite.txt"),
+				UploadId:          created.UploadId,
+				PartNumber:        aws.Int32(int32(i + 1)),
+				Body:              bytes.NewReader(data),
+				ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, checksum.Compute(checksum.CRC32, data), aws.ToString(resp.ChecksumCRC32))
+			completed = append(completed, types.CompletedPart{
+				ETag:          resp.ETag,
+				PartNumber:    aws.Int32(int32(i + 1)),
+				ChecksumCRC32: resp.ChecksumCRC32,
+			})
+		}
+
+		resp, err := s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("composite.txt"),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.NoError(t, err)
+
+		expected, err := checksum.Composite(checksum.CRC32, []string{
+			checksum.Compute(checksum.CRC32, part1),
+			checksum.Compute(checksum.CRC32, part2),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, aws.ToString(resp.ChecksumCRC32))
+
+		head, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:       aws.String("test-bucket"),
+			Key:          aws.String("composite.txt"),
+			ChecksumMode: types.ChecksumModeEnabled,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expected, aws.ToString(head.ChecksumCRC32))
+		assert.Equal(t, types.ChecksumTypeComposite, head.ChecksumType)
+
+		// A single part returns the part checksum
+		head, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:       aws.String("test-bucket"),
+			Key:          aws.String("composite.txt"),
+			PartNumber:   aws.Int32(2),
+			ChecksumMode: types.ChecksumModeEnabled,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, checksum.Compute(checksum.CRC32, part2), aws.ToString(head.ChecksumCRC32))
+	})
+
+	t.Run("Full object checksum", func(t *testing.T) {
+		created, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("full-object.txt"),
+			ChecksumAlgorithm: types.ChecksumAlgorithmCrc64nvme,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, types.ChecksumTypeFullObject, created.ChecksumType)
+
+		// Parts must use the algorithm of the upload
+		part, err := s3Client.UploadPart(context.Background(), &s3.UploadPartInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("full-object.txt"),
+			UploadId:          created.UploadId,
+			PartNumber:        aws.Int32(1),
+			Body:              bytes.NewReader([]byte("single part")),
+			ChecksumAlgorithm: types.ChecksumAlgorithmCrc64nvme,
+		})
+		require.NoError(t, err)
+
+		completed := []types.CompletedPart{{ETag: part.ETag, PartNumber: aws.Int32(1)}}
+		resp, err := s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("full-object.txt"),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, checksum.Compute(checksum.CRC64NVME, []byte("single part")), aws.ToString(resp.ChecksumCRC64NVME))
+		assert.Equal(t, types.ChecksumTypeFullObject, resp.ChecksumType)
+	})
+
+	t.Run("Reject unsupported checksum type", func(t *testing.T) {
+		_, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("unsupported.txt"),
+			ChecksumAlgorithm: types.ChecksumAlgorithmSha256,
+			ChecksumType:      types.ChecksumTypeFullObject,
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidRequest", errorCode(err))
+	})
+
+	t.Run("Overwrite replaces the tags", func(t *testing.T) {
+		_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("tagged.txt"),
+			Body:   bytes.NewReader([]byte("old")),
+		})
+		require.NoError(t, err)
+		objectID, err := store.Queries.GetObjectID(context.Background(), db.GetObjectIDParams{
+			BucketName: "test-bucket",
+			Key:        "tagged.txt",
+		})
+		require.NoError(t, err)
+		require.NoError(t, store.Queries.CreateObjectTag(context.Background(), db.CreateObjectTagParams{
+			ObjectID: objectID,
+			Key:      "old",
+			Value:    "true",
+		}))
+
+		resp, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket:  aws.String("test-bucket"),
+			Key:     aws.String("tagged.txt"),
+			Tagging: aws.String("project=s3local"),
+		})
+		require.NoError(t, err)
+		uploadID := aws.ToString(resp.UploadId)
+		completed := []types.CompletedPart{uploadPart(t, "tagged.txt", uploadID, 1, []byte("new"))}
+		_, err = s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("tagged.txt"),
+			UploadId:        aws.String(uploadID),
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.NoError(t, err)
+
+		objectID, err = store.Queries.GetObjectID(context.Background(), db.GetObjectIDParams{
+			BucketName: "test-bucket",
+			Key:        "tagged.txt",
+		})
+		require.NoError(t, err)
+		tags, err := store.Queries.GetObjectTags(context.Background(), objectID)
+		require.NoError(t, err)
+		assert.Equal(t, []db.GetObjectTagsRow{{Key: "project", Value: "s3local"}}, tags)
+	})
+
+	t.Run("Reject invalid tagging", func(t *testing.T) {
+		_, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket:  aws.String("test-bucket"),
+			Key:     aws.String("tagged.txt"),
+			Tagging: aws.String("a=1&a=2"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidArgument", errorCode(err))
+	})
+
+	t.Run("Abort a multipart upload", func(t *testing.T) {
+		uploadID := createUpload(t, "aborted.txt")
+		uploadPart(t, "aborted.txt", uploadID, 1, []byte("content"))
+
+		_, err := s3Client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String("test-bucket"),
+			Key:      aws.String("aborted.txt"),
+			UploadId: aws.String(uploadID),
+		})
+		require.NoError(t, err)
+
+		_, err = s3Client.ListParts(context.Background(), &s3.ListPartsInput{
+			Bucket:   aws.String("test-bucket"),
+			Key:      aws.String("aborted.txt"),
+			UploadId: aws.String(uploadID),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchUpload", errorCode(err))
+	})
+}
@@ -0,0 +1,168 @@
+package object
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// conditions holds the conditional headers of a request
+type conditions struct {
+	IfMatch           string
+	IfNoneMatch       string
+	IfModifiedSince   string
+	IfUnmodifiedSince string
+}
+
+// requestConditions returns the If-* headers of a GetObject/HeadObject request
+func requestConditions(r *http.Request) conditions {
+	return conditions{
+		IfMatch:           r.Header.Get("If-Match"),
+		IfNoneMatch:       r.Header.Get("If-None-Match"),
+		IfModifiedSince:   r.Header.Get("If-Modified-Since"),
+		IfUnmodifiedSince: r.Header.Get("If-Unmodified-Since"),
+	}
+}
+
+// copySourceConditions returns the x-amz-copy-source-if-* headers of a copy request
+func copySourceConditions(r *http.Request) conditions {
+	return conditions{
+		IfMatch:           r.Header.Get("x-amz-copy-source-if-match"),
+		IfNoneMatch:       r.Header.Get("x-amz-copy-source-if-none-match"),
+		IfModifiedSince:   r.Header.Get("x-amz-copy-source-if-modified-since"),
+		IfUnmodifiedSince: r.Header.Get("x-amz-copy-source-if-unmodified-since"),
+	}
+}
+
+// check evaluates the conditions the way S3 does. It returns notModified=true
+// when the client should receive 304 Not Modified, or a PreconditionFailed
+// error.
+//
+// If-Match takes precedence over If-Unmodified-Since and If-None-Match takes
+// precedence over If-Modified-Since.
+func (c conditions) check(etag string, lastModified time.Time) (notModified bool, s3Err *s3error.Error) {
+	// HTTP dates have second precision
+	lastModified = lastModified.Truncate(time.Second)
+
+	if c.IfMatch != "" {
+		if !etagMatches(c.IfMatch, etag) {
+			return false, s3error.NewPreconditionFailedError("If-Match")
+		}
+	} else if c.IfUnmodifiedSince != "" {
+		if t, err := http.ParseTime(c.IfUnmodifiedSince); err == nil && lastModified.After(t) {
+			return false, s3error.NewPreconditionFailedError("If-Unmodified-Since")
+		}
+	}
+
+	if c.IfNoneMatch != "" {
+		return etagMatches(c.IfNoneMatch, etag), nil
+	}
+	if c.IfModifiedSince != "" {
+		if t, err := http.ParseTime(c.IfModifiedSince); err == nil && !lastModified.After(t) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkCopySource evaluates the x-amz-copy-source-if-* conditions. Unlike
+// GetObject, a copy fails with PreconditionFailed instead of 304 Not Modified.
+func checkCopySource(r *http.Request, etag string, lastModified time.Time) *s3error.Error {
+	c := copySourceConditions(r)
+	notModified, s3Err := c.check(etag, lastModified)
+	if s3Err != nil {
+		s3Err.Condition = "x-amz-copy-source-" + strings.ToLower(s3Err.Condition)
+		return s3Err
+	}
+	if notModified {
+		if c.IfNoneMatch != "" {
+			return s3error.NewPreconditionFailedError("x-amz-copy-source-if-none-match")
+		}
+		return s3error.NewPreconditionFailedError("x-amz-copy-source-if-modified-since")
+	}
+	return nil
+}
+
+// writeNotModified responds with 304 Not Modified and the validators of the object
+func writeNotModified(w http.ResponseWriter, etag string, lastModified time.Time) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, etag))
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// etagMatches reports whether the comma separated list of entity tags in a
+// conditional header contains etag. "*" matches any object.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		candidate = strings.TrimPrefix(candidate, "W/")
+		if strings.Trim(candidate, `"`) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// byteRange is a satisfiable byte range of an object
+type byteRange struct {
+	Start int64
+	Size  int64
+}
+
+// parseRange parses a Range header. Like S3 only a single range is supported;
+// a header that is malformed or specifies several ranges is ignored and nil is
+// returned so the whole object is served. A range that cannot be satisfied
+// returns an InvalidRange error.
+func parseRange(header string, objectSize int64) (*byteRange, *s3error.Error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, nil
+	}
+
+	// Suffix range: bytes=-N returns the last N bytes
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return nil, nil
+		}
+		if n == 0 || objectSize == 0 {
+			return nil, s3error.NewInvalidRangeError(header, objectSize)
+		}
+		n = min(n, objectSize)
+		return &byteRange{Start: objectSize - n, Size: n}, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return nil, nil
+	}
+	end := objectSize - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return nil, nil
+		}
+	}
+	if start >= objectSize {
+		return nil, s3error.NewInvalidRangeError(header, objectSize)
+	}
+	end = min(end, objectSize-1)
+	return &byteRange{Start: start, Size: end - start + 1}, nil
+}
+
+// writeHeaders sets Content-Length and Content-Range for the range
+func (b *byteRange) writeHeaders(w http.ResponseWriter, objectSize int64) {
+	w.Header().Set("Content-Length", strconv.FormatInt(b.Size, 10))
+	w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", b.Start, b.Start+b.Size-1, objectSize))
+}
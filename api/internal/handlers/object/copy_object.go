@@ -0,0 +1,397 @@
+package object
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// CopyObject handles PUT /{bucket}/{key} with x-amz-copy-source
+func CopyObject(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+
+	// Check if destination bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	src, s3Err := loadCopySource(r, store)
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	metadataDirective, s3Err := parseDirective(r.Header.Get("x-amz-metadata-directive"), "x-amz-metadata-directive")
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+	taggingDirective, s3Err := parseDirective(r.Header.Get("x-amz-tagging-directive"), "x-amz-tagging-directive")
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	storageClass := r.Header.Get("x-amz-storage-class")
+	// Copying the current version onto itself must change something. Copying an
+	// older version over the current one restores it.
+	if src.BucketName == bucketName && src.Key == objectKey && src.IsLatest && metadataDirective != "REPLACE" && storageClass == "" {
+		s3error.NewInvalidRequestError("This copy request is illegal because it is trying to copy an object to itself without changing the object's metadata, storage class, website redirect location or encryption attributes.").WriteError(w)
+		return
+	}
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+
+	// Content headers and user metadata come from the source unless replaced
+	contentType := src.ContentType
+	contentEncoding := src.ContentEncoding
+	contentDisposition := src.ContentDisposition
+	cacheControl := src.CacheControl
+	var metadata map[string]string
+	if metadataDirective == "REPLACE" {
+		contentType = r.Header.Get("Content-Type")
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		contentEncoding = toNullString(r.Header.Get("Content-Encoding"))
+		contentDisposition = toNullString(r.Header.Get("Content-Disposition"))
+		cacheControl = toNullString(r.Header.Get("Cache-Control"))
+		metadata = extractMetadata(r.Header)
+	} else {
+		metadataRows, err := store.Queries.GetObjectMetadataByObjectID(r.Context(), src.ID)
+		if err != nil {
+			s3error.NewInternalError(err).WriteError(w)
+			return
+		}
+		metadata = make(map[string]string, len(metadataRows))
+		for _, meta := range metadataRows {
+			metadata[meta.Key] = meta.Value
+		}
+	}
+
+	// Tags come from the source unless replaced by x-amz-tagging
+	var tags []db.GetObjectTagsRow
+	if taggingDirective == "REPLACE" {
+		tags, s3Err = parseTaggingHeader(r.Header.Get("x-amz-tagging"))
+		if s3Err != nil {
+			s3Err.WriteError(w)
+			return
+		}
+	} else {
+		tags, err = store.Queries.GetObjectTags(r.Context(), src.ID)
+		if err != nil {
+			s3error.NewInternalError(err).WriteError(w)
+			return
+		}
+	}
+
+	requested, s3Err := copyChecksumAlgorithm(r)
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	source, err := openBlob(r.Context(), store, src.BlobID)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	defer source.Close()
+
+	// The copy gets its own blob so either object can be deleted independently
+	var content *digestReader
+	if requested != "" {
+		content = newDigestReader(source, requested)
+	} else {
+		content = newDigestReader(source)
+	}
+	blobID, _, err := store.PutBlob(r.Context(), content)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// The copy is a single part object, so its ETag is the MD5 of the content
+	etag := content.etag()
+	checksumAlgorithm, checksumValue, checksumType := copyChecksum(src, content, requested)
+
+	var lastModified sql.NullTime
+	var versionID sql.NullString
+	err = store.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		versionID, err = prepareNewVersion(r.Context(), q, bucketName, objectKey)
+		if err != nil {
+			return err
+		}
+
+		objectID, err := q.UpsertObject(r.Context(), db.UpsertObjectParams{
+			BucketName:         bucketName,
+			Key:                objectKey,
+			BlobID:             toNullString(blobID),
+			Size:               src.Size,
+			ETag:               etag,
+			ContentType:        contentType,
+			ContentEncoding:    contentEncoding,
+			ContentDisposition: contentDisposition,
+			CacheControl:       cacheControl,
+			StorageClass:       storageClass,
+			VersionID:          versionID,
+			ChecksumAlgorithm:  checksumAlgorithm,
+			ChecksumValue:      checksumValue,
+			ChecksumType:       checksumType,
+		})
+		if err != nil {
+			return err
+		}
+
+		obj, err := q.GetObjectByID(r.Context(), objectID)
+		if err != nil {
+			return err
+		}
+		lastModified = sql.NullTime{Time: obj.Object.UpdatedAt, Valid: true}
+
+		if err := q.DeleteObjectParts(r.Context(), objectID); err != nil {
+			return err
+		}
+
+		if err := q.DeleteObjectMetadata(r.Context(), objectID); err != nil {
+			return err
+		}
+		for k, v := range metadata {
+			if err := q.CreateObjectMetadata(r.Context(), db.CreateObjectMetadataParams{
+				ObjectID: objectID,
+				Key:      k,
+				Value:    v,
+			}); err != nil {
+				return err
+			}
+		}
+
+		if err := q.DeleteObjectTags(r.Context(), objectID); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if err := q.CreateObjectTag(r.Context(), db.CreateObjectTagParams{
+				ObjectID: objectID,
+				Key:      tag.Key,
+				Value:    tag.Value,
+			}); err != nil {
+				return err
+			}
+		}
+
+		_, err = event.Record(r.Context(), q, db.CreateEventParams{
+			BucketName:      bucketName,
+			ObjectID:        sql.NullInt64{Int64: objectID, Valid: true},
+			EventType:       event.ObjectCreatedCopy,
+			ObjectKey:       objectKey,
+			ObjectSize:      src.Size,
+			ObjectETag:      etag,
+			ObjectVersionID: versionID,
+		})
+		return err
+	})
+	if err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	result := CopyObjectResult{
+		Xmlns:        "http://s3.amazonaws.com/doc/2006-03-01/",
+		ETag:         fmt.Sprintf(`"%s"`, etag),
+		LastModified: lastModified.Time.Format("2006-01-02T15:04:05.000Z"),
+	}
+	if checksumValue.Valid {
+		alg, _ := checksum.ParseAlgorithm(checksumAlgorithm.String)
+		result.Checksums.set(alg, checksumValue.String)
+		result.ChecksumType = checksumType.String
+	}
+
+	if src.VersionID.Valid {
+		w.Header().Set("x-amz-copy-source-version-id", src.VersionID.String)
+	}
+	writeVersionHeader(w, versionID)
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// CopyObjectResult represents the S3 CopyObject response
+type CopyObjectResult struct {
+	XMLName      xml.Name `xml:"CopyObjectResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	ETag         string   `xml:"ETag"`
+	LastModified string   `xml:"LastModified"`
+	Checksums
+	ChecksumType string `xml:"ChecksumType,omitempty"`
+}
+
+// CopyObjectRequestHeaders represents request headers for CopyObject
+type CopyObjectRequestHeaders struct {
+	CopySource                  string // x-amz-copy-source
+	CopySourceIfMatch           string // x-amz-copy-source-if-match
+	CopySourceIfModifiedSince   string // x-amz-copy-source-if-modified-since
+	CopySourceIfNoneMatch       string // x-amz-copy-source-if-none-match
+	CopySourceIfUnmodifiedSince string // x-amz-copy-source-if-unmodified-since
+	MetadataDirective           string // x-amz-metadata-directive
+	TaggingDirective            string // x-amz-tagging-directive
+	Tagging                     string // x-amz-tagging
+	StorageClass                string // x-amz-storage-class
+	ChecksumAlgorithm           string // x-amz-checksum-algorithm
+}
+
+// CopyObjectResponseHeaders represents response headers for CopyObject
+type CopyObjectResponseHeaders struct {
+	CopySourceVersionId string // x-amz-copy-source-version-id
+	VersionId           string // x-amz-version-id
+}
+
+// loadCopySource resolves x-amz-copy-source and evaluates the
+// x-amz-copy-source-if-* conditions against the source object
+func loadCopySource(r *http.Request, store *db.Store) (db.Object, *s3error.Error) {
+	srcBucket, srcKey, srcVersionID, ok := parseCopySource(r.Header.Get("x-amz-copy-source"))
+	if !ok {
+		return db.Object{}, s3error.NewInvalidArgumentError("Copy Source must mention the source bucket and key: sourcebucket/sourcekey")
+	}
+	if srcVersionID != "" && !validVersionID(srcVersionID) {
+		return db.Object{}, s3error.NewInvalidArgumentError("Invalid version id specified")
+	}
+
+	exists, err := store.Queries.BucketExists(r.Context(), srcBucket)
+	if err != nil {
+		return db.Object{}, s3error.NewInternalError(err)
+	}
+	if !exists {
+		return db.Object{}, s3error.NewNoSuchBucketError(srcBucket)
+	}
+
+	var src db.Object
+	if srcVersionID != "" {
+		src, err = store.Queries.GetObjectVersion(r.Context(), db.GetObjectVersionParams{
+			BucketName: srcBucket,
+			Key:        srcKey,
+			VersionID:  srcVersionID,
+		})
+		if err == sql.ErrNoRows {
+			return db.Object{}, s3error.NewNoSuchVersionError(srcKey, srcVersionID)
+		}
+		if err == nil && src.IsDeleteMarker {
+			return db.Object{}, s3error.NewInvalidRequestError("The source of a copy request may not specifically refer to a delete marker by version id.")
+		}
+	} else {
+		src, err = store.Queries.GetObject(r.Context(), db.GetObjectParams{
+			BucketName: srcBucket,
+			Key:        srcKey,
+		})
+		if err == sql.ErrNoRows {
+			return db.Object{}, s3error.NewNoSuchKeyError(srcKey)
+		}
+	}
+	if err != nil {
+		return db.Object{}, s3error.NewInternalError(err)
+	}
+
+	if s3Err := checkCopySource(r, src.ETag, src.UpdatedAt); s3Err != nil {
+		return db.Object{}, s3Err
+	}
+	return src, nil
+}
+
+// parseCopySource parses x-amz-copy-source, e.g. /bucket/key%20name?versionId=abc
+func parseCopySource(value string) (bucket, key, versionID string, ok bool) {
+	value, query, _ := strings.Cut(value, "?")
+	if query != "" {
+		values, err := url.ParseQuery(query)
+		if err != nil {
+			return "", "", "", false
+		}
+		versionID = values.Get("versionId")
+	}
+	value, err := url.PathUnescape(strings.TrimPrefix(value, "/"))
+	if err != nil {
+		return "", "", "", false
+	}
+	bucket, key, ok = strings.Cut(value, "/")
+	if !ok || bucket == "" || key == "" {
+		return "", "", "", false
+	}
+	return bucket, key, versionID, true
+}
+
+// parseDirective validates a COPY/REPLACE directive header, defaulting to COPY
+func parseDirective(value, header string) (string, *s3error.Error) {
+	switch strings.ToUpper(value) {
+	case "", "COPY":
+		return "COPY", nil
+	case "REPLACE":
+		return "REPLACE", nil
+	}
+	return "", s3error.NewInvalidArgumentError(fmt.Sprintf("Unknown %s value: %s", header, value))
+}
+
+// parseTaggingHeader parses the URL encoded x-amz-tagging header
+func parseTaggingHeader(value string) ([]db.GetObjectTagsRow, *s3error.Error) {
+	values, err := url.ParseQuery(value)
+	if err != nil {
+		return nil, s3error.NewInvalidArgumentError("The header 'x-amz-tagging' shall be encoded as UTF-8 then URLEncoded URL query parameters without tag name duplicates.")
+	}
+	if len(values) > 10 {
+		return nil, s3error.NewInvalidTagError("Object tags cannot be greater than 10")
+	}
+	tags := make([]db.GetObjectTagsRow, 0, len(values))
+	for k, v := range values {
+		if len(v) > 1 {
+			return nil, s3error.NewInvalidArgumentError("The header 'x-amz-tagging' shall be encoded as UTF-8 then URLEncoded URL query parameters without tag name duplicates.")
+		}
+		tags = append(tags, db.GetObjectTagsRow{Key: k, Value: v[0]})
+	}
+	return tags, nil
+}
+
+// copyChecksumAlgorithm returns the algorithm requested by
+// x-amz-checksum-algorithm, or an empty algorithm when none is requested
+func copyChecksumAlgorithm(r *http.Request) (checksum.Algorithm, *s3error.Error) {
+	name := r.Header.Get("x-amz-checksum-algorithm")
+	if name == "" {
+		return "", nil
+	}
+	alg, ok := checksum.ParseAlgorithm(name)
+	if !ok {
+		return "", s3error.NewInvalidRequestError("Checksum algorithm provided is unsupported. Please try again with any of the valid types: [CRC32, CRC32C, CRC64NVME, SHA1, SHA256]")
+	}
+	return alg, nil
+}
+
+// copyChecksum returns the checksum of the copy. A requested algorithm is
+// computed over the copied content; otherwise a full object checksum of the
+// source is kept.
+func copyChecksum(src db.Object, content *digestReader, requested checksum.Algorithm) (algorithm, value, checksumType sql.NullString) {
+	if requested != "" {
+		sum, _ := content.checksum(requested)
+		return toNullString(string(requested)), toNullString(sum), toNullString(string(checksum.TypeFullObject))
+	}
+	if src.ChecksumType.String == string(checksum.TypeFullObject) {
+		return src.ChecksumAlgorithm, src.ChecksumValue, src.ChecksumType
+	}
+	return algorithm, value, checksumType
+}
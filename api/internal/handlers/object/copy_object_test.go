@@ -0,0 +1,331 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestCopyObject(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Post("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("uploads") {
+					CreateMultipartUpload(w, r)
+					return
+				}
+				CompleteMultipartUpload(w, r)
+			})
+			r.Put("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("tagging") {
+					PutObjectTagging(w, r)
+					return
+				}
+				if r.URL.Query().Has("uploadId") {
+					UploadPartCopy(w, r)
+					return
+				}
+				if r.Header.Get("x-amz-copy-source") != "" {
+					CopyObject(w, r)
+					return
+				}
+				PutObject(w, r)
+			})
+			r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("tagging") {
+					GetObjectTagging(w, r)
+					return
+				}
+				GetObject(w, r)
+			})
+			r.Head("/*", HeadObject)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	for _, name := range []string{"src-bucket", "dst-bucket"} {
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   name,
+			Region: "us-east-1",
+		})
+		require.NoError(t, err)
+	}
+
+	_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket:      aws.String("src-bucket"),
+		Key:         aws.String("source.txt"),
+		Body:        bytes.NewReader([]byte("source content")),
+		ContentType: aws.String("text/plain"),
+		Metadata:    map[string]string{"origin": "source"},
+	})
+	require.NoError(t, err)
+
+	_, err = s3Client.PutObjectTagging(context.Background(), &s3.PutObjectTaggingInput{
+		Bucket: aws.String("src-bucket"),
+		Key:    aws.String("source.txt"),
+		Tagging: &types.Tagging{TagSet: []types.Tag{
+			{Key: aws.String("team"), Value: aws.String("storage")},
+		}},
+	})
+	require.NoError(t, err)
+
+	errorCode := func(err error) string {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			return apiErr.ErrorCode()
+		}
+		return ""
+	}
+
+	t.Run("Copy metadata and tags across buckets", func(t *testing.T) {
+		resp, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:     aws.String("dst-bucket"),
+			Key:        aws.String("copy.txt"),
+			CopySource: aws.String("src-bucket/source.txt"),
+		})
+		require.NoError(t, err)
+		hash := md5.Sum([]byte("source content"))
+		assert.Equal(t, fmt.Sprintf(`"%s"`, hex.EncodeToString(hash[:])), *resp.CopyObjectResult.ETag)
+		assert.NotNil(t, resp.CopyObjectResult.LastModified)
+
+		getResp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("dst-bucket"),
+			Key:    aws.String("copy.txt"),
+		})
+		require.NoError(t, err)
+		defer getResp.Body.Close()
+		body, _ := io.ReadAll(getResp.Body)
+		assert.Equal(t, "source content", string(body))
+		assert.Equal(t, "text/plain", *getResp.ContentType)
+		assert.Equal(t, "source", getResp.Metadata["origin"])
+
+		tagResp, err := s3Client.GetObjectTagging(context.Background(), &s3.GetObjectTaggingInput{
+			Bucket: aws.String("dst-bucket"),
+			Key:    aws.String("copy.txt"),
+		})
+		require.NoError(t, err)
+		require.Len(t, tagResp.TagSet, 1)
+		assert.Equal(t, "team", *tagResp.TagSet[0].Key)
+		assert.Equal(t, "storage", *tagResp.TagSet[0].Value)
+	})
+
+	t.Run("Concurrent copies to the same key", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+					Bucket:     aws.String("dst-bucket"),
+					Key:        aws.String("concurrent.txt"),
+					CopySource: aws.String("src-bucket/source.txt"),
+				})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		obj, err := store.Queries.GetObject(context.Background(), db.GetObjectParams{
+			BucketName: "dst-bucket",
+			Key:        "concurrent.txt",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "source content", string(testutil.ReadBlob(t, store, obj.BlobID)))
+
+		// Every copy recorded its event
+		var events int
+		err = store.DB.QueryRow("SELECT COUNT(*) FROM events WHERE bucket_name = 'dst-bucket' AND object_key = 'concurrent.txt'").Scan(&events)
+		require.NoError(t, err)
+		assert.Equal(t, 8, events)
+	})
+
+	t.Run("Replace metadata and tags", func(t *testing.T) {
+		_, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:            aws.String("dst-bucket"),
+			Key:               aws.String("replaced.txt"),
+			CopySource:        aws.String("src-bucket/source.txt"),
+			MetadataDirective: types.MetadataDirectiveReplace,
+			ContentType:       aws.String("application/json"),
+			Metadata:          map[string]string{"origin": "copy"},
+			TaggingDirective:  types.TaggingDirectiveReplace,
+			Tagging:           aws.String("team=copy&env=test"),
+		})
+		require.NoError(t, err)
+
+		headResp, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket: aws.String("dst-bucket"),
+			Key:    aws.String("replaced.txt"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", *headResp.ContentType)
+		assert.Equal(t, "copy", headResp.Metadata["origin"])
+
+		tagResp, err := s3Client.GetObjectTagging(context.Background(), &s3.GetObjectTaggingInput{
+			Bucket: aws.String("dst-bucket"),
+			Key:    aws.String("replaced.txt"),
+		})
+		require.NoError(t, err)
+		assert.Len(t, tagResp.TagSet, 2)
+	})
+
+	t.Run("Copy source conditions", func(t *testing.T) {
+		_, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:            aws.String("dst-bucket"),
+			Key:               aws.String("conditional.txt"),
+			CopySource:        aws.String("src-bucket/source.txt"),
+			CopySourceIfMatch: aws.String(`"00000000000000000000000000000000"`),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "PreconditionFailed", errorCode(err))
+
+		_, err = s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:                    aws.String("dst-bucket"),
+			Key:                       aws.String("conditional.txt"),
+			CopySource:                aws.String("src-bucket/source.txt"),
+			CopySourceIfModifiedSince: aws.Time(time.Now().Add(time.Hour)),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "PreconditionFailed", errorCode(err))
+
+		_, err = s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:                      aws.String("dst-bucket"),
+			Key:                         aws.String("conditional.txt"),
+			CopySource:                  aws.String("src-bucket/source.txt"),
+			CopySourceIfUnmodifiedSince: aws.Time(time.Now().Add(time.Hour)),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Reject copy to itself without changes", func(t *testing.T) {
+		_, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:     aws.String("src-bucket"),
+			Key:        aws.String("source.txt"),
+			CopySource: aws.String("src-bucket/source.txt"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidRequest", errorCode(err))
+
+		_, err = s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:            aws.String("src-bucket"),
+			Key:               aws.String("source.txt"),
+			CopySource:        aws.String("src-bucket/source.txt"),
+			MetadataDirective: types.MetadataDirectiveReplace,
+			Metadata:          map[string]string{"origin": "source"},
+			ContentType:       aws.String("text/plain"),
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("Missing source", func(t *testing.T) {
+		_, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:     aws.String("dst-bucket"),
+			Key:        aws.String("missing.txt"),
+			CopySource: aws.String("src-bucket/missing.txt"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchKey", errorCode(err))
+
+		_, err = s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:     aws.String("dst-bucket"),
+			Key:        aws.String("missing.txt"),
+			CopySource: aws.String("no-bucket/source.txt"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchBucket", errorCode(err))
+	})
+
+	t.Run("Upload part copy", func(t *testing.T) {
+		large := bytes.Repeat([]byte("c"), minPartSize+10)
+		_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String("src-bucket"),
+			Key:    aws.String("large.bin"),
+			Body:   bytes.NewReader(large),
+		})
+		require.NoError(t, err)
+
+		created, err := s3Client.CreateMultipartUpload(context.Background(), &s3.CreateMultipartUploadInput{
+			Bucket: aws.String("dst-bucket"),
+			Key:    aws.String("assembled.bin"),
+		})
+		require.NoError(t, err)
+
+		var completed []types.CompletedPart
+		for i, rng := range []string{
+			fmt.Sprintf("bytes=0-%d", minPartSize-1),
+			fmt.Sprintf("bytes=%d-%d", minPartSize, minPartSize+9),
+		} {
+			partResp, err := s3Client.UploadPartCopy(context.Background(), &s3.UploadPartCopyInput{
+				Bucket:          aws.String("dst-bucket"),
+				Key:             aws.String("assembled.bin"),
+				UploadId:        created.UploadId,
+				PartNumber:      aws.Int32(int32(i + 1)),
+				CopySource:      aws.String("src-bucket/large.bin"),
+				CopySourceRange: aws.String(rng),
+			})
+			require.NoError(t, err)
+			completed = append(completed, types.CompletedPart{
+				ETag:       partResp.CopyPartResult.ETag,
+				PartNumber: aws.Int32(int32(i + 1)),
+			})
+		}
+
+		_, err = s3Client.CompleteMultipartUpload(context.Background(), &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String("dst-bucket"),
+			Key:             aws.String("assembled.bin"),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+		})
+		require.NoError(t, err)
+
+		getResp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("dst-bucket"),
+			Key:    aws.String("assembled.bin"),
+		})
+		require.NoError(t, err)
+		defer getResp.Body.Close()
+		body, _ := io.ReadAll(getResp.Body)
+		assert.Equal(t, large, body)
+
+		_, err = s3Client.UploadPartCopy(context.Background(), &s3.UploadPartCopyInput{
+			Bucket:          aws.String("dst-bucket"),
+			Key:             aws.String("assembled.bin"),
+			UploadId:        aws.String("no-such-upload"),
+			PartNumber:      aws.Int32(1),
+			CopySource:      aws.String("src-bucket/large.bin"),
+			CopySourceRange: aws.String("bytes=0-1"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchUpload", errorCode(err))
+	})
+}
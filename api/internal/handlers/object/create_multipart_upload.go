@@ -0,0 +1,163 @@
+package object
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// CreateMultipartUpload handles POST /{bucket}/{key}?uploads
+func CreateMultipartUpload(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+
+	// Check if bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	uploadID, err := newUploadID()
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	storageClass := r.Header.Get("x-amz-storage-class")
+	if storageClass == "" {
+		storageClass = "STANDARD"
+	}
+
+	// Checksum algorithm used for the parts and the completed object
+	var checksumAlgorithm, checksumType sql.NullString
+	if name := r.Header.Get("x-amz-checksum-algorithm"); name != "" {
+		alg, ok := checksum.ParseAlgorithm(name)
+		if !ok {
+			s3error.NewInvalidRequestError("Checksum algorithm provided is unsupported. Please try again with any of the valid types: [CRC32, CRC32C, CRC64NVME, SHA1, SHA256]").WriteError(w)
+			return
+		}
+		t := checksum.DefaultType(alg)
+		if value := r.Header.Get("x-amz-checksum-type"); value != "" {
+			t = checksum.Type(strings.ToUpper(value))
+		}
+		if !checksum.SupportsType(alg, t) {
+			s3error.NewInvalidRequestError(fmt.Sprintf("The %s checksum type cannot be used with the %s checksum algorithm.", t, alg)).WriteError(w)
+			return
+		}
+		checksumAlgorithm = toNullString(string(alg))
+		checksumType = toNullString(string(t))
+	} else if r.Header.Get("x-amz-checksum-type") != "" {
+		s3error.NewInvalidRequestError("The x-amz-checksum-type header can only be used with the x-amz-checksum-algorithm header.").WriteError(w)
+		return
+	}
+
+	// User metadata is applied to the object when the upload completes
+	metadata, err := json.Marshal(extractMetadata(r.Header))
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Like the metadata, tags are applied when the upload completes
+	tagList, s3Err := parseTaggingHeader(r.Header.Get("x-amz-tagging"))
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+	tagMap := make(map[string]string, len(tagList))
+	for _, tag := range tagList {
+		tagMap[tag.Key] = tag.Value
+	}
+	tags, err := json.Marshal(tagMap)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	err = store.Queries.CreateMultipartUpload(r.Context(), db.CreateMultipartUploadParams{
+		UploadID:           uploadID,
+		BucketName:         bucketName,
+		Key:                objectKey,
+		ContentType:        contentType,
+		ContentEncoding:    toNullString(r.Header.Get("Content-Encoding")),
+		ContentDisposition: toNullString(r.Header.Get("Content-Disposition")),
+		CacheControl:       toNullString(r.Header.Get("Cache-Control")),
+		StorageClass:       storageClass,
+		Metadata:           string(metadata),
+		ChecksumAlgorithm:  checksumAlgorithm,
+		ChecksumType:       checksumType,
+		Tags:               string(tags),
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	result := InitiateMultipartUploadResult{
+		Xmlns:    "http://s3.amazonaws.com/doc/2006-03-01/",
+		Bucket:   bucketName,
+		Key:      objectKey,
+		UploadID: uploadID,
+	}
+
+	if checksumAlgorithm.Valid {
+		w.Header().Set("x-amz-checksum-algorithm", checksumAlgorithm.String)
+		w.Header().Set("x-amz-checksum-type", checksumType.String)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// InitiateMultipartUploadResult represents the S3 CreateMultipartUpload response
+type InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+// CreateMultipartUploadRequestHeaders represents request headers for CreateMultipartUpload
+type CreateMultipartUploadRequestHeaders struct {
+	CacheControl         string // Cache-Control
+	ContentDisposition   string // Content-Disposition
+	ContentEncoding      string // Content-Encoding
+	ContentType          string // Content-Type
+	Expires              string // Expires
+	ServerSideEncryption string // x-amz-server-side-encryption
+	StorageClass         string // x-amz-storage-class
+	ChecksumAlgorithm    string // x-amz-checksum-algorithm
+	ChecksumType         string // x-amz-checksum-type
+	Tagging              string // x-amz-tagging
+}
+
+// newUploadID returns a random, URL-safe multipart upload ID
+func newUploadID() (string, error) {
+	b := make([]byte, 48)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
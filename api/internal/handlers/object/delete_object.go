@@ -14,15 +14,30 @@ func DeleteObject(w http.ResponseWriter, r *http.Request) {
 	bucketName := ctx.GetBucketName(r.Context())
 	objectKey := ctx.GetObjectKey(r.Context())
 
-	err := store.Queries.DeleteObject(r.Context(), db.DeleteObjectParams{
-		BucketName: bucketName,
-		Key:        objectKey,
+	versionID := r.URL.Query().Get("versionId")
+	if r.URL.Query().Has("versionId") && !validVersionID(versionID) {
+		s3error.NewInvalidArgumentError("Invalid version id specified").WriteError(w)
+		return
+	}
+
+	var deleted deletion
+	err := store.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		deleted, err = deleteObject(r.Context(), q, bucketName, objectKey, versionID)
+		return err
 	})
 	if err != nil {
 		s3error.NewInternalError(err).WriteError(w)
 		return
 	}
 
+	if deleted.VersionID != "" {
+		w.Header().Set("x-amz-version-id", deleted.VersionID)
+	}
+	if deleted.DeleteMarker {
+		w.Header().Set("x-amz-delete-marker", "true")
+	}
+
 	// S3 returns 204 No Content even if the object didn't exist
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -43,3 +58,9 @@ type DeleteObjectRequestHeaders struct {
 	VersionId                 string // versionId (query parameter)
 	BypassGovernanceRetention string // x-amz-bypass-governance-retention
 }
+
+// DeleteObjectResponseHeaders represents response headers for DeleteObject
+type DeleteObjectResponseHeaders struct {
+	DeleteMarker string // x-amz-delete-marker
+	VersionId    string // x-amz-version-id
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/s3error"
 )
@@ -15,17 +16,21 @@ func DeleteObjectTagging(w http.ResponseWriter, r *http.Request) {
 	objectKey := ctx.GetObjectKey(r.Context())
 
 	// Check if object exists and get object ID
-	objectID, err := store.Queries.GetObjectID(r.Context(), db.GetObjectIDParams{
-		BucketName: bucketName,
-		Key:        objectKey,
-	})
-	if err != nil {
-		s3error.NewNoSuchKeyError(objectKey).WriteError(w)
+	objectID, versionID, s3Err := resolveObjectID(w, r, store.Queries, bucketName, objectKey)
+	if s3Err != nil {
+		s3Err.WriteError(w)
 		return
 	}
+	writeVersionHeader(w, versionID)
 
 	// Delete all tags
-	if err := store.Queries.DeleteObjectTags(r.Context(), objectID); err != nil {
+	err := store.ExecTx(r.Context(), func(q *db.Queries) error {
+		if err := q.DeleteObjectTags(r.Context(), objectID); err != nil {
+			return err
+		}
+		return createObjectEvent(r.Context(), q, objectID, event.ObjectTaggingDelete)
+	})
+	if err != nil {
 		s3error.NewInternalError(err).WriteError(w)
 		return
 	}
@@ -48,7 +48,7 @@ func TestDeleteObject(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket",
 			Key:          "test-key",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "test-etag",
 			ContentType:  "text/plain",
@@ -69,6 +69,20 @@ func TestDeleteObject(t *testing.T) {
 			Key:        "test-key",
 		})
 		assert.Error(t, err)
+
+		// The event outlives the deleted object
+		events, err := store.Queries.ListEventsByBucket(context.Background(), db.ListEventsByBucketParams{
+			BucketName: "test-bucket",
+			Limit:      10,
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, "s3:ObjectRemoved:Delete", events[0].EventType)
+			assert.Equal(t, "test-key", events[0].ObjectKey)
+			assert.Equal(t, int64(len(testData)), events[0].ObjectSize)
+			assert.Equal(t, "test-etag", events[0].ObjectETag)
+			assert.False(t, events[0].ObjectID.Valid)
+		}
 	})
 
 	t.Run("Not Found", func(t *testing.T) {
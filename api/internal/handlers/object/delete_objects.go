@@ -0,0 +1,162 @@
+package object
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// maxDeleteObjects is the maximum number of keys in a single DeleteObjects request
+const maxDeleteObjects = 1000
+
+// DeleteObjects handles POST /{bucket}?delete
+func DeleteObjects(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+
+	// Check if bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	if r.Body == nil {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+	defer r.Body.Close()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		s3error.FromError(err).WriteError(w)
+		return
+	}
+
+	// The request must be protected by Content-MD5 or an additional checksum
+	sum, s3Err := verifyIntegrity(r, body)
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+	if sum == nil && r.Header.Get("Content-MD5") == "" {
+		s3error.NewInvalidRequestError("Missing required header for this request: Content-MD5").WriteError(w)
+		return
+	}
+
+	var request DeleteObjectsRequest
+	if err := xml.Unmarshal(body, &request); err != nil {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+	if len(request.Objects) == 0 || len(request.Objects) > maxDeleteObjects {
+		s3error.NewMalformedXMLError().WriteError(w)
+		return
+	}
+
+	result := DeleteResult{
+		Xmlns:   "http://s3.amazonaws.com/doc/2006-03-01/",
+		Deleted: []DeletedObject{},
+		Errors:  []DeleteError{},
+	}
+	err = store.ExecTx(r.Context(), func(q *db.Queries) error {
+		for _, obj := range request.Objects {
+			if obj.Key == "" {
+				result.Errors = append(result.Errors, DeleteError{
+					Key:     obj.Key,
+					Code:    string(s3error.ErrCodeInvalidArgument),
+					Message: "Key must not be empty.",
+				})
+				continue
+			}
+			if obj.VersionId != "" && !validVersionID(obj.VersionId) {
+				s3Err := s3error.NewNoSuchVersionError(obj.Key, obj.VersionId)
+				result.Errors = append(result.Errors, DeleteError{
+					Key:       obj.Key,
+					VersionId: obj.VersionId,
+					Code:      s3Err.Code,
+					Message:   s3Err.Message,
+				})
+				continue
+			}
+
+			deleted, err := deleteObject(r.Context(), q, bucketName, obj.Key, obj.VersionId)
+			if err != nil {
+				return err
+			}
+			if !request.Quiet {
+				entry := DeletedObject{
+					Key:       obj.Key,
+					VersionId: obj.VersionId,
+				}
+				if deleted.DeleteMarker {
+					entry.DeleteMarker = true
+					entry.DeleteMarkerVersionId = deleted.VersionID
+				}
+				result.Deleted = append(result.Deleted, entry)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// DeleteObjectsRequest represents the S3 DeleteObjects request body
+type DeleteObjectsRequest struct {
+	XMLName xml.Name           `xml:"Delete"`
+	Quiet   bool               `xml:"Quiet"`
+	Objects []ObjectIdentifier `xml:"Object"`
+}
+
+// ObjectIdentifier identifies an object to delete
+type ObjectIdentifier struct {
+	Key       string `xml:"Key"`
+	VersionId string `xml:"VersionId,omitempty"`
+}
+
+// DeleteObjectsRequestHeaders represents request headers for DeleteObjects
+type DeleteObjectsRequestHeaders struct {
+	ContentMD5                string // Content-MD5
+	ChecksumAlgorithm         string // x-amz-sdk-checksum-algorithm
+	MFA                       string // x-amz-mfa
+	BypassGovernanceRetention string // x-amz-bypass-governance-retention
+}
+
+// DeleteResult represents the S3 DeleteObjects response
+type DeleteResult struct {
+	XMLName xml.Name        `xml:"DeleteResult"`
+	Xmlns   string          `xml:"xmlns,attr"`
+	Deleted []DeletedObject `xml:"Deleted"`
+	Errors  []DeleteError   `xml:"Error"`
+}
+
+// DeletedObject is a successfully deleted object
+type DeletedObject struct {
+	Key                   string `xml:"Key"`
+	VersionId             string `xml:"VersionId,omitempty"`
+	DeleteMarker          bool   `xml:"DeleteMarker,omitempty"`
+	DeleteMarkerVersionId string `xml:"DeleteMarkerVersionId,omitempty"`
+}
+
+// DeleteError is an object that could not be deleted
+type DeleteError struct {
+	Key       string `xml:"Key"`
+	VersionId string `xml:"VersionId,omitempty"`
+	Code      string `xml:"Code"`
+	Message   string `xml:"Message"`
+}
@@ -0,0 +1,149 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestDeleteObjects(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Post("/", DeleteObjects)
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Put("/*", PutObject)
+			r.Head("/*", HeadObject)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	putObject := func(t *testing.T, key string) {
+		_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader([]byte("content of " + key)),
+		})
+		require.NoError(t, err)
+	}
+
+	removedKeys := func(t *testing.T) []string {
+		events, err := store.Queries.ListEventsByBucket(context.Background(), db.ListEventsByBucketParams{
+			BucketName: "test-bucket",
+			Limit:      100,
+		})
+		require.NoError(t, err)
+		var keys []string
+		for _, event := range events {
+			if event.EventType == "s3:ObjectRemoved:Delete" {
+				keys = append(keys, event.ObjectKey)
+			}
+		}
+		return keys
+	}
+
+	t.Run("Delete multiple objects", func(t *testing.T) {
+		putObject(t, "a.txt")
+		putObject(t, "b.txt")
+
+		resp, err := s3Client.DeleteObjects(context.Background(), &s3.DeleteObjectsInput{
+			Bucket: aws.String("test-bucket"),
+			Delete: &types.Delete{Objects: []types.ObjectIdentifier{
+				{Key: aws.String("a.txt")},
+				{Key: aws.String("b.txt")},
+				{Key: aws.String("missing.txt")},
+			}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Deleted, 3)
+		assert.Equal(t, "a.txt", *resp.Deleted[0].Key)
+		assert.Empty(t, resp.Errors)
+
+		_, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("a.txt"),
+		})
+		require.Error(t, err)
+
+		// Only keys that actually existed produce events
+		assert.ElementsMatch(t, []string{"a.txt", "b.txt"}, removedKeys(t))
+	})
+
+	t.Run("Quiet mode only reports errors", func(t *testing.T) {
+		putObject(t, "quiet.txt")
+
+		resp, err := s3Client.DeleteObjects(context.Background(), &s3.DeleteObjectsInput{
+			Bucket: aws.String("test-bucket"),
+			Delete: &types.Delete{
+				Quiet: aws.Bool(true),
+				Objects: []types.ObjectIdentifier{
+					{Key: aws.String("quiet.txt")},
+					{Key: aws.String("quiet.txt"), VersionId: aws.String("3HL4kqtJlcpXroDTDmJ")},
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Deleted)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "NoSuchVersion", *resp.Errors[0].Code)
+		assert.Equal(t, "3HL4kqtJlcpXroDTDmJ", *resp.Errors[0].VersionId)
+	})
+
+	t.Run("Reject requests without Content-MD5 or checksum", func(t *testing.T) {
+		body := `<Delete><Object><Key>a.txt</Key></Object></Delete>`
+		resp, err := http.Post(ts.URL+"/test-bucket?delete", "application/xml", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/test-bucket?delete", bytes.NewReader([]byte(body)))
+		require.NoError(t, err)
+		req.Header.Set("Content-MD5", "1B2M2Y8AsgTpgAmY7PhCfg==")
+		resp, err = http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("Bucket not found", func(t *testing.T) {
+		_, err := s3Client.DeleteObjects(context.Background(), &s3.DeleteObjectsInput{
+			Bucket: aws.String("no-such-bucket"),
+			Delete: &types.Delete{Objects: []types.ObjectIdentifier{
+				{Key: aws.String("a.txt")},
+			}},
+		})
+		require.Error(t, err)
+		var apiErr smithy.APIError
+		require.True(t, errors.As(err, &apiErr))
+		assert.Equal(t, "NoSuchBucket", apiErr.ErrorCode())
+	})
+}
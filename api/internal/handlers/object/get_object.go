@@ -3,10 +3,10 @@ package object
 import (
 	"database/sql"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
-	"github.com/tkasuz/s3local/internal/db"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/s3error"
 )
@@ -17,16 +17,20 @@ func GetObject(w http.ResponseWriter, r *http.Request) {
 	bucketName := ctx.GetBucketName(r.Context())
 	objectKey := ctx.GetObjectKey(r.Context())
 
-	obj, err := store.Queries.GetObject(r.Context(), db.GetObjectParams{
-		BucketName: bucketName,
-		Key:        objectKey,
-	})
-	if err == sql.ErrNoRows {
-		s3error.NewNoSuchKeyError(objectKey).WriteError(w)
+	obj, s3Err := loadObject(w, r, store.Queries, bucketName, objectKey)
+	if s3Err != nil {
+		s3Err.WriteError(w)
 		return
 	}
-	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
+
+	// Conditional requests are evaluated before the range
+	notModified, s3Err := requestConditions(r).check(obj.ETag, obj.UpdatedAt)
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+	if notModified {
+		writeNotModified(w, obj.ETag, obj.UpdatedAt)
 		return
 	}
 
@@ -37,12 +41,56 @@ func GetObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Resolve the byte range of a single part when partNumber is given
+	var part *objectPart
+	if r.URL.Query().Has("partNumber") {
+		part, s3Err = resolveObjectPart(r, store, obj.ID, obj.Size)
+		if s3Err != nil {
+			s3Err.WriteError(w)
+			return
+		}
+	}
+
+	// Resolve a single byte range when Range is given
+	var rng *byteRange
+	if rangeHeader := r.Header.Get("Range"); rangeHeader != "" {
+		if part != nil {
+			s3error.NewInvalidRequestError("Cannot specify both Range header and partNumber query parameter").WriteError(w)
+			return
+		}
+		rng, s3Err = parseRange(rangeHeader, obj.Size)
+		if s3Err != nil {
+			s3Err.WriteError(w)
+			return
+		}
+	}
+
+	content, err := openBlob(r.Context(), store, obj.BlobID)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	defer content.Close()
+
+	// Position the content at the requested range or part before any header is written
+	body := io.Reader(content)
+	if rng != nil {
+		body, err = sectionReader(content, rng.Start, rng.Size)
+	} else if part != nil {
+		body, err = sectionReader(content, part.Start, part.Size)
+	}
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
 	// Set response headers
 	w.Header().Set("Content-Type", obj.ContentType)
 	w.Header().Set("Content-Length", strconv.FormatInt(obj.Size, 10))
 	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, obj.ETag))
 	w.Header().Set("Last-Modified", obj.UpdatedAt.Format(http.TimeFormat))
 	w.Header().Set("Accept-Ranges", "bytes")
+	writeVersionHeader(w, obj.VersionID)
 
 	if obj.ContentEncoding.Valid {
 		w.Header().Set("Content-Encoding", obj.ContentEncoding.String)
@@ -59,8 +107,24 @@ func GetObject(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("x-amz-meta-"+meta.Key, meta.Value)
 	}
 
+	writeObjectChecksumHeaders(w, r, obj, part)
+
+	if rng != nil {
+		rng.writeHeaders(w, obj.Size)
+		w.WriteHeader(http.StatusPartialContent)
+		io.Copy(w, body)
+		return
+	}
+
+	if part != nil {
+		part.writeHeaders(w, obj.Size)
+		w.WriteHeader(part.status())
+		io.Copy(w, body)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
-	w.Write(obj.Data)
+	io.Copy(w, body)
 }
 
 // GetObjectRequest represents the S3 GetObject request
@@ -80,9 +144,12 @@ type GetObjectRequestHeaders struct {
 	IfNoneMatch          string // If-None-Match
 	IfUnmodifiedSince    string // If-Unmodified-Since
 	Range                string // Range
+	PartNumber           string // partNumber (query parameter)
+	VersionId            string // versionId (query parameter)
 	SSECustomerAlgorithm string // x-amz-server-side-encryption-customer-algorithm
 	SSECustomerKey       string // x-amz-server-side-encryption-customer-key
 	SSECustomerKeyMD5    string // x-amz-server-side-encryption-customer-key-MD5
+	ChecksumMode         string // x-amz-checksum-mode
 }
 
 // GetObjectResponseHeaders represents response headers for GetObject
@@ -95,4 +162,6 @@ type GetObjectResponseHeaders struct {
 	ContentEncoding    string // Content-Encoding
 	ContentDisposition string // Content-Disposition
 	CacheControl       string // Cache-Control
+	VersionId          string // x-amz-version-id
+	DeleteMarker       string // x-amz-delete-marker
 }
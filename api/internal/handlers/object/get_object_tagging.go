@@ -4,7 +4,6 @@ import (
 	"encoding/xml"
 	"net/http"
 
-	"github.com/tkasuz/s3local/internal/db"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/s3error"
 )
@@ -16,14 +15,12 @@ func GetObjectTagging(w http.ResponseWriter, r *http.Request) {
 	objectKey := ctx.GetObjectKey(r.Context())
 
 	// Check if object exists and get object ID
-	objectID, err := store.Queries.GetObjectID(r.Context(), db.GetObjectIDParams{
-		BucketName: bucketName,
-		Key:        objectKey,
-	})
-	if err != nil {
-		s3error.NewNoSuchKeyError(objectKey).WriteError(w)
+	objectID, versionID, s3Err := resolveObjectID(w, r, store.Queries, bucketName, objectKey)
+	if s3Err != nil {
+		s3Err.WriteError(w)
 		return
 	}
+	writeVersionHeader(w, versionID)
 
 	// Get tags
 	tags, err := store.Queries.GetObjectTags(r.Context(), objectID)
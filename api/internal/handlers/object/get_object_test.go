@@ -0,0 +1,218 @@
+package object
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestGetObject_RangeAndConditional(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Get("/*", GetObject)
+			r.Head("/*", HeadObject)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+
+	testData := []byte("0123456789")
+	_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
+		BucketName:   "test-bucket",
+		Key:          "digits.txt",
+		BlobID:       testutil.PutBlob(t, store, testData),
+		Size:         int64(len(testData)),
+		ETag:         "781e5e245d69b566979b86e28d23f2c7",
+		ContentType:  "text/plain",
+		StorageClass: "STANDARD",
+	})
+	require.NoError(t, err)
+
+	getRange := func(t *testing.T, rangeHeader string) (*s3.GetObjectOutput, string) {
+		resp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("digits.txt"),
+			Range:  aws.String(rangeHeader),
+		})
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp, string(body)
+	}
+
+	statusCode := func(err error) int {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) {
+			return respErr.HTTPStatusCode()
+		}
+		return 0
+	}
+
+	errorCode := func(err error) string {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			return apiErr.ErrorCode()
+		}
+		return ""
+	}
+
+	t.Run("Byte range", func(t *testing.T) {
+		resp, body := getRange(t, "bytes=2-5")
+		assert.Equal(t, "2345", body)
+		assert.Equal(t, "bytes 2-5/10", aws.ToString(resp.ContentRange))
+		assert.Equal(t, int64(4), aws.ToInt64(resp.ContentLength))
+	})
+
+	t.Run("Open ended range", func(t *testing.T) {
+		resp, body := getRange(t, "bytes=7-")
+		assert.Equal(t, "789", body)
+		assert.Equal(t, "bytes 7-9/10", aws.ToString(resp.ContentRange))
+	})
+
+	t.Run("Suffix range", func(t *testing.T) {
+		resp, body := getRange(t, "bytes=-3")
+		assert.Equal(t, "789", body)
+		assert.Equal(t, "bytes 7-9/10", aws.ToString(resp.ContentRange))
+	})
+
+	t.Run("Range past the end is truncated", func(t *testing.T) {
+		resp, body := getRange(t, "bytes=8-100")
+		assert.Equal(t, "89", body)
+		assert.Equal(t, "bytes 8-9/10", aws.ToString(resp.ContentRange))
+	})
+
+	t.Run("Unsatisfiable range", func(t *testing.T) {
+		_, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("digits.txt"),
+			Range:  aws.String("bytes=10-20"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "InvalidRange", errorCode(err))
+		assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, statusCode(err))
+	})
+
+	t.Run("Multiple ranges return the whole object", func(t *testing.T) {
+		resp, body := getRange(t, "bytes=0-1,4-5")
+		assert.Equal(t, "0123456789", body)
+		assert.Nil(t, resp.ContentRange)
+	})
+
+	t.Run("If-Match", func(t *testing.T) {
+		resp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:  aws.String("test-bucket"),
+			Key:     aws.String("digits.txt"),
+			IfMatch: aws.String(`"781e5e245d69b566979b86e28d23f2c7"`),
+		})
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		_, err = s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:  aws.String("test-bucket"),
+			Key:     aws.String("digits.txt"),
+			IfMatch: aws.String(`"other"`),
+		})
+		require.Error(t, err)
+		assert.Equal(t, "PreconditionFailed", errorCode(err))
+		assert.Equal(t, http.StatusPreconditionFailed, statusCode(err))
+	})
+
+	t.Run("If-None-Match", func(t *testing.T) {
+		_, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:      aws.String("test-bucket"),
+			Key:         aws.String("digits.txt"),
+			IfNoneMatch: aws.String(`"781e5e245d69b566979b86e28d23f2c7"`),
+		})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusNotModified, statusCode(err))
+
+		_, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:      aws.String("test-bucket"),
+			Key:         aws.String("digits.txt"),
+			IfNoneMatch: aws.String("*"),
+		})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusNotModified, statusCode(err))
+	})
+
+	t.Run("If-Modified-Since and If-Unmodified-Since", func(t *testing.T) {
+		future := time.Now().Add(time.Hour)
+		past := time.Now().Add(-24 * time.Hour)
+
+		_, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("digits.txt"),
+			IfModifiedSince: aws.Time(future),
+		})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusNotModified, statusCode(err))
+
+		resp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:          aws.String("test-bucket"),
+			Key:             aws.String("digits.txt"),
+			IfModifiedSince: aws.Time(past),
+		})
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		_, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("digits.txt"),
+			IfUnmodifiedSince: aws.Time(past),
+		})
+		require.Error(t, err)
+		assert.Equal(t, http.StatusPreconditionFailed, statusCode(err))
+
+		// If-Match takes precedence over If-Unmodified-Since
+		head, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("digits.txt"),
+			IfMatch:           aws.String(`"781e5e245d69b566979b86e28d23f2c7"`),
+			IfUnmodifiedSince: aws.Time(past),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), aws.ToInt64(head.ContentLength))
+	})
+
+	t.Run("Head with range", func(t *testing.T) {
+		head, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("digits.txt"),
+			Range:  aws.String("bytes=0-3"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), aws.ToInt64(head.ContentLength))
+		assert.Equal(t, "bytes 0-3/10", aws.ToString(head.ContentRange))
+	})
+}
@@ -49,7 +49,7 @@ func TestHeadObject(t *testing.T) {
 		obj, err := store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket",
 			Key:          "test-key",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "098f6bcd4621d373cade4e832627b4f6",
 			ContentType:  "text/plain",
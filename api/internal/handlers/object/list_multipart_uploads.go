@@ -0,0 +1,116 @@
+package object
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// ListMultipartUploadsResult represents the S3 ListMultipartUploads response
+type ListMultipartUploadsResult struct {
+	XMLName            xml.Name `xml:"ListMultipartUploadsResult"`
+	Xmlns              string   `xml:"xmlns,attr"`
+	Bucket             string   `xml:"Bucket"`
+	KeyMarker          string   `xml:"KeyMarker"`
+	UploadIDMarker     string   `xml:"UploadIdMarker"`
+	NextKeyMarker      string   `xml:"NextKeyMarker,omitempty"`
+	NextUploadIDMarker string   `xml:"NextUploadIdMarker,omitempty"`
+	Prefix             string   `xml:"Prefix"`
+	MaxUploads         int64    `xml:"MaxUploads"`
+	IsTruncated        bool     `xml:"IsTruncated"`
+	Uploads            []Upload `xml:"Upload"`
+}
+
+// Upload represents an in-progress multipart upload in the list response
+type Upload struct {
+	Key          string `xml:"Key"`
+	UploadID     string `xml:"UploadId"`
+	Initiated    string `xml:"Initiated"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+// ListMultipartUploads handles GET /{bucket}?uploads
+func ListMultipartUploads(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+
+	query := r.URL.Query()
+
+	maxUploads, _ := strconv.ParseInt(query.Get("max-uploads"), 10, 64)
+	if maxUploads <= 0 || maxUploads > 1000 {
+		maxUploads = 1000
+	}
+	prefix := query.Get("prefix")
+	keyMarker := query.Get("key-marker")
+	uploadIDMarker := query.Get("upload-id-marker")
+
+	// Check if bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	uploads, err := store.Queries.ListMultipartUploads(r.Context(), db.ListMultipartUploadsParams{
+		BucketName:     bucketName,
+		Prefix:         prefix,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		Limit:          maxUploads + 1, // Fetch one extra to check if truncated
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	isTruncated := int64(len(uploads)) > maxUploads
+	if isTruncated {
+		uploads = uploads[:maxUploads]
+	}
+
+	result := ListMultipartUploadsResult{
+		Xmlns:          "http://s3.amazonaws.com/doc/2006-03-01/",
+		Bucket:         bucketName,
+		KeyMarker:      keyMarker,
+		UploadIDMarker: uploadIDMarker,
+		Prefix:         prefix,
+		MaxUploads:     maxUploads,
+		IsTruncated:    isTruncated,
+		Uploads:        make([]Upload, 0, len(uploads)),
+	}
+	for _, upload := range uploads {
+		result.Uploads = append(result.Uploads, Upload{
+			Key:          upload.Key,
+			UploadID:     upload.UploadID,
+			Initiated:    upload.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			StorageClass: upload.StorageClass,
+		})
+	}
+	if isTruncated && len(uploads) > 0 {
+		last := uploads[len(uploads)-1]
+		result.NextKeyMarker = last.Key
+		result.NextUploadIDMarker = last.UploadID
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	xml.NewEncoder(w).Encode(result)
+}
+
+// ListMultipartUploadsQueryParams represents query parameters for ListMultipartUploads
+type ListMultipartUploadsQueryParams struct {
+	Delimiter      string // delimiter
+	EncodingType   string // encoding-type
+	KeyMarker      string // key-marker
+	MaxUploads     int64  // max-uploads
+	Prefix         string // prefix
+	UploadIDMarker string // upload-id-marker
+}
@@ -0,0 +1,175 @@
+package object
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// ListObjectVersions handles GET /{bucket}?versions
+func ListObjectVersions(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+
+	// Check if bucket exists
+	exists, err := store.Queries.BucketExists(r.Context(), bucketName)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	if !exists {
+		s3error.NewNoSuchBucketError(bucketName).WriteError(w)
+		return
+	}
+
+	query := r.URL.Query()
+
+	maxKeys, _ := strconv.ParseInt(query.Get("max-keys"), 10, 64)
+	if maxKeys <= 0 || maxKeys > 1000 {
+		maxKeys = 1000
+	}
+
+	prefix := query.Get("prefix")
+	delimiter := query.Get("delimiter")
+	keyMarker := query.Get("key-marker")
+	versionIDMarker := query.Get("version-id-marker")
+
+	if versionIDMarker != "" && keyMarker == "" {
+		s3error.NewInvalidArgumentError("A version-id marker cannot be specified without a key marker.").WriteError(w)
+		return
+	}
+
+	rows, err := store.Queries.ListObjectVersions(r.Context(), db.ListObjectVersionsParams{
+		BucketName: bucketName,
+		Prefix:     prefix,
+		KeyMarker:  keyMarker,
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	result := ListVersionsResult{
+		Xmlns:           "http://s3.amazonaws.com/doc/2006-03-01/",
+		Name:            bucketName,
+		Prefix:          prefix,
+		Delimiter:       delimiter,
+		KeyMarker:       keyMarker,
+		VersionIdMarker: versionIDMarker,
+		MaxKeys:         maxKeys,
+		Entries:         make([]ObjectVersionEntry, 0),
+		CommonPrefixes:  make([]CommonPrefix, 0),
+	}
+
+	// Versions of the key marker are skipped up to and including the version
+	// marker, or entirely when no version marker is given
+	pastVersionMarker := false
+	processedPrefixes := make(map[string]bool)
+	var count int64
+	var lastKey, lastVersionID string
+	for _, row := range rows {
+		if row.Key == keyMarker && !pastVersionMarker {
+			pastVersionMarker = versionIDString(row.VersionID) == versionIDMarker
+			continue
+		}
+
+		// Keys containing the delimiter after the prefix roll up into a common prefix
+		if delimiter != "" {
+			keyAfterPrefix := row.Key[len(prefix):]
+			if idx := strings.Index(keyAfterPrefix, delimiter); idx >= 0 {
+				commonPrefix := prefix + keyAfterPrefix[:idx+len(delimiter)]
+				if commonPrefix == keyMarker || processedPrefixes[commonPrefix] {
+					continue
+				}
+				if count == maxKeys {
+					result.IsTruncated = true
+					break
+				}
+				processedPrefixes[commonPrefix] = true
+				result.CommonPrefixes = append(result.CommonPrefixes, CommonPrefix{Prefix: commonPrefix})
+				count++
+				lastKey, lastVersionID = commonPrefix, ""
+				continue
+			}
+		}
+
+		if count == maxKeys {
+			result.IsTruncated = true
+			break
+		}
+
+		entry := ObjectVersionEntry{
+			XMLName:      xml.Name{Local: "Version"},
+			Key:          row.Key,
+			VersionId:    versionIDString(row.VersionID),
+			IsLatest:     row.IsLatest,
+			LastModified: row.UpdatedAt.Format("2006-01-02T15:04:05.000Z"),
+		}
+		if row.IsDeleteMarker {
+			entry.XMLName.Local = "DeleteMarker"
+		} else {
+			size := row.Size
+			entry.ETag = fmt.Sprintf(`"%s"`, row.ETag)
+			entry.Size = &size
+			entry.StorageClass = row.StorageClass
+		}
+		result.Entries = append(result.Entries, entry)
+		count++
+		lastKey, lastVersionID = row.Key, entry.VersionId
+	}
+
+	if result.IsTruncated {
+		result.NextKeyMarker = lastKey
+		result.NextVersionIdMarker = lastVersionID
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// ListVersionsResult represents the S3 ListObjectVersions response
+type ListVersionsResult struct {
+	XMLName             xml.Name             `xml:"ListVersionsResult"`
+	Xmlns               string               `xml:"xmlns,attr"`
+	Name                string               `xml:"Name"`
+	Prefix              string               `xml:"Prefix"`
+	Delimiter           string               `xml:"Delimiter,omitempty"`
+	KeyMarker           string               `xml:"KeyMarker"`
+	VersionIdMarker     string               `xml:"VersionIdMarker"`
+	NextKeyMarker       string               `xml:"NextKeyMarker,omitempty"`
+	NextVersionIdMarker string               `xml:"NextVersionIdMarker,omitempty"`
+	MaxKeys             int64                `xml:"MaxKeys"`
+	IsTruncated         bool                 `xml:"IsTruncated"`
+	Entries             []ObjectVersionEntry // Version and DeleteMarker elements in key order
+	CommonPrefixes      []CommonPrefix       `xml:"CommonPrefixes"`
+}
+
+// ObjectVersionEntry represents a Version or DeleteMarker in the list response
+type ObjectVersionEntry struct {
+	XMLName      xml.Name
+	Key          string `xml:"Key"`
+	VersionId    string `xml:"VersionId"`
+	IsLatest     bool   `xml:"IsLatest"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag,omitempty"`
+	Size         *int64 `xml:"Size,omitempty"`
+	StorageClass string `xml:"StorageClass,omitempty"`
+}
+
+// ListObjectVersionsQueryParams represents query parameters for ListObjectVersions
+type ListObjectVersionsQueryParams struct {
+	Delimiter       string // delimiter
+	EncodingType    string // encoding-type
+	KeyMarker       string // key-marker
+	MaxKeys         int64  // max-keys
+	Prefix          string // prefix
+	VersionIdMarker string // version-id-marker
+}
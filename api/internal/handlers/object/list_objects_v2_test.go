@@ -45,7 +45,7 @@ func TestListObjectsV2(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket",
 			Key:          "object1",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "etag1",
 			ContentType:  "text/plain",
@@ -56,7 +56,7 @@ func TestListObjectsV2(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket",
 			Key:          "object2",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "etag2",
 			ContentType:  "text/plain",
@@ -104,7 +104,7 @@ func TestListObjectsV2(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   bucketName,
 			Key:          "prefix1/object1",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "etag1",
 			ContentType:  "text/plain",
@@ -115,7 +115,7 @@ func TestListObjectsV2(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   bucketName,
 			Key:          "prefix2/object2",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "etag2",
 			ContentType:  "text/plain",
@@ -156,7 +156,7 @@ func TestListObjectsV2(t *testing.T) {
 			_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 				BucketName:   bucketNmae,
 				Key:          key,
-				Data:         testData,
+				BlobID:       testutil.PutBlob(t, store, testData),
 				Size:         int64(len(testData)),
 				ETag:         "etag" + string(rune(i)),
 				ContentType:  "text/plain",
@@ -224,7 +224,7 @@ func TestListObjectsV2(t *testing.T) {
 			_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 				BucketName:   bucketName,
 				Key:          key,
-				Data:         testData,
+				BlobID:       testutil.PutBlob(t, store, testData),
 				Size:         0,
 				ETag:         "etag" + string(rune(i)),
 				ContentType:  "application/x-directory",
@@ -238,7 +238,7 @@ func TestListObjectsV2(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   bucketName,
 			Key:          "file1.txt",
-			Data:         fileData,
+			BlobID:       testutil.PutBlob(t, store, fileData),
 			Size:         int64(len(fileData)),
 			ETag:         "etag-file1",
 			ContentType:  "text/plain",
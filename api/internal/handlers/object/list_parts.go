@@ -0,0 +1,126 @@
+package object
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// ListPartsResult represents the S3 ListParts response
+type ListPartsResult struct {
+	XMLName              xml.Name `xml:"ListPartsResult"`
+	Xmlns                string   `xml:"xmlns,attr"`
+	Bucket               string   `xml:"Bucket"`
+	Key                  string   `xml:"Key"`
+	UploadID             string   `xml:"UploadId"`
+	StorageClass         string   `xml:"StorageClass"`
+	PartNumberMarker     int64    `xml:"PartNumberMarker"`
+	NextPartNumberMarker int64    `xml:"NextPartNumberMarker,omitempty"`
+	MaxParts             int64    `xml:"MaxParts"`
+	IsTruncated          bool     `xml:"IsTruncated"`
+	ChecksumAlgorithm    string   `xml:"ChecksumAlgorithm,omitempty"`
+	ChecksumType         string   `xml:"ChecksumType,omitempty"`
+	Parts                []Part   `xml:"Part"`
+}
+
+// Part represents an uploaded part in the ListParts response
+type Part struct {
+	PartNumber   int64  `xml:"PartNumber"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	Checksums
+}
+
+// ListParts handles GET /{bucket}/{key}?uploadId={uploadId}
+func ListParts(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+
+	query := r.URL.Query()
+	uploadID := query.Get("uploadId")
+
+	maxParts, _ := strconv.ParseInt(query.Get("max-parts"), 10, 64)
+	if maxParts <= 0 || maxParts > 1000 {
+		maxParts = 1000
+	}
+	partNumberMarker, _ := strconv.ParseInt(query.Get("part-number-marker"), 10, 64)
+
+	upload, err := store.Queries.GetMultipartUpload(r.Context(), db.GetMultipartUploadParams{
+		UploadID:   uploadID,
+		BucketName: bucketName,
+		Key:        objectKey,
+	})
+	if err == sql.ErrNoRows {
+		s3error.NewNoSuchUploadError(uploadID).WriteError(w)
+		return
+	}
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	parts, err := store.Queries.ListMultipartUploadParts(r.Context(), db.ListMultipartUploadPartsParams{
+		UploadID:         uploadID,
+		PartNumberMarker: partNumberMarker,
+		Limit:            maxParts + 1, // Fetch one extra to check if truncated
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	isTruncated := int64(len(parts)) > maxParts
+	if isTruncated {
+		parts = parts[:maxParts]
+	}
+
+	result := ListPartsResult{
+		Xmlns:             "http://s3.amazonaws.com/doc/2006-03-01/",
+		Bucket:            bucketName,
+		Key:               objectKey,
+		UploadID:          uploadID,
+		StorageClass:      upload.StorageClass,
+		PartNumberMarker:  partNumberMarker,
+		MaxParts:          maxParts,
+		IsTruncated:       isTruncated,
+		ChecksumAlgorithm: upload.ChecksumAlgorithm.String,
+		ChecksumType:      upload.ChecksumType.String,
+		Parts:             make([]Part, 0, len(parts)),
+	}
+	alg, _ := checksum.ParseAlgorithm(upload.ChecksumAlgorithm.String)
+	for _, part := range parts {
+		p := Part{
+			PartNumber:   part.PartNumber,
+			LastModified: part.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+			ETag:         fmt.Sprintf(`"%s"`, part.ETag),
+			Size:         part.Size,
+		}
+		if part.ChecksumValue.Valid {
+			p.Checksums.set(alg, part.ChecksumValue.String)
+		}
+		result.Parts = append(result.Parts, p)
+	}
+	if isTruncated && len(parts) > 0 {
+		result.NextPartNumberMarker = parts[len(parts)-1].PartNumber
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	xml.NewEncoder(w).Encode(result)
+}
+
+// ListPartsQueryParams represents query parameters for ListParts
+type ListPartsQueryParams struct {
+	UploadID         string // uploadId
+	MaxParts         int64  // max-parts
+	PartNumberMarker int64  // part-number-marker
+}
@@ -0,0 +1,68 @@
+package object
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// objectPart is the byte range of one part of an object
+type objectPart struct {
+	Start         int64
+	Size          int64
+	PartsCount    int
+	ChecksumValue sql.NullString
+}
+
+// resolveObjectPart looks up the part requested with the partNumber query
+// parameter. Objects that were not created by a multipart upload consist of a
+// single part spanning the whole object.
+func resolveObjectPart(r *http.Request, store *db.Store, objectID, objectSize int64) (*objectPart, *s3error.Error) {
+	partNumber, s3Err := parsePartNumber(r.URL.Query().Get("partNumber"))
+	if s3Err != nil {
+		return nil, s3Err
+	}
+
+	parts, err := store.Queries.ListObjectParts(r.Context(), objectID)
+	if err != nil {
+		return nil, s3error.NewInternalError(err)
+	}
+	if len(parts) == 0 {
+		parts = []db.ListObjectPartsRow{{PartNumber: 1, Size: objectSize}}
+	}
+	if partNumber > int64(len(parts)) {
+		return nil, s3error.NewInvalidPartNumberError()
+	}
+
+	var start int64
+	for _, part := range parts[:partNumber-1] {
+		start += part.Size
+	}
+	return &objectPart{
+		Start:         start,
+		Size:          parts[partNumber-1].Size,
+		PartsCount:    len(parts),
+		ChecksumValue: parts[partNumber-1].ChecksumValue,
+	}, nil
+}
+
+// writeHeaders sets the headers describing the part on the response
+func (p *objectPart) writeHeaders(w http.ResponseWriter, objectSize int64) {
+	w.Header().Set("Content-Length", strconv.FormatInt(p.Size, 10))
+	w.Header().Set("x-amz-mp-parts-count", strconv.Itoa(p.PartsCount))
+	if p.Size > 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", p.Start, p.Start+p.Size-1, objectSize))
+	}
+}
+
+// status returns 206 Partial Content unless the part is empty
+func (p *objectPart) status() int {
+	if p.Size == 0 {
+		return http.StatusOK
+	}
+	return http.StatusPartialContent
+}
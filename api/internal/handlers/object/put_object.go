@@ -1,16 +1,14 @@
 package object
 
 import (
-	"bytes"
-	"crypto/md5"
 	"database/sql"
-	"encoding/hex"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
 
+	"github.com/tkasuz/s3local/internal/checksum"
 	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/s3error"
 )
@@ -21,98 +19,129 @@ func PutObject(w http.ResponseWriter, r *http.Request) {
 	bucketName := ctx.GetBucketName(r.Context())
 	objectKey := ctx.GetObjectKey(r.Context())
 
-	// Read the body into memory
-	var buf bytes.Buffer
-	_, err := io.Copy(&buf, r.Body)
+	// The object gets only the tags of x-amz-tagging, even when it overwrites
+	// a tagged one
+	tags, s3Err := parseTaggingHeader(r.Header.Get("x-amz-tagging"))
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	// Stream the body to the blob store while computing its MD5 and checksums;
+	// SQLite only keeps the metadata
+	body := newDigestReader(r.Body, requestedAlgorithms(r)...)
+	blobID, size, err := store.PutBlob(r.Context(), body)
 	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
+		s3error.FromError(err).WriteError(w)
+		return
+	}
+
+	// Verify Content-MD5 and x-amz-checksum-*
+	sum, s3Err := verifyStreamedIntegrity(r, body, store, blobID)
+	if s3Err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3Err.WriteError(w)
 		return
 	}
-	data := buf.Bytes()
+	var checksumAlgorithm, checksumValue, checksumType sql.NullString
+	if sum != nil {
+		checksumAlgorithm = toNullString(string(sum.Algorithm))
+		checksumValue = toNullString(sum.Value)
+		checksumType = toNullString(string(checksum.TypeFullObject))
+	}
 
-	// Calculate ETag (MD5 hash)
-	hash := md5.Sum(data)
-	etag := hex.EncodeToString(hash[:])
+	etag := body.etag()
 
 	contentType := r.Header.Get("Content-Type")
 	if contentType == "" {
 		contentType = "application/octet-stream"
 	}
 
-	// Check if object exists
-	exists, err := store.Queries.ObjectExists(r.Context(), db.ObjectExistsParams{
-		BucketName: bucketName,
-		Key:        objectKey,
-	})
-	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
-		return
-	}
+	metadata := extractMetadata(r.Header)
 
-	if exists {
-		// Update existing object
-		err = store.Queries.UpdateObject(r.Context(), db.UpdateObjectParams{
-			BucketName:         bucketName,
-			Key:                objectKey,
-			Data:               data,
-			Size:               int64(len(data)),
-			ETag:               etag,
-			ContentType:        contentType,
-			ContentEncoding:    toNullString(r.Header.Get("Content-Encoding")),
-			ContentDisposition: toNullString(r.Header.Get("Content-Disposition")),
-			CacheControl:       toNullString(r.Header.Get("Cache-Control")),
-			StorageClass:       "STANDARD",
-		})
-	} else {
-		// Create new object
-		_, err = store.Queries.CreateObject(r.Context(), db.CreateObjectParams{
+	// Write the object, its metadata and its event in one transaction so that
+	// concurrent PUTs of the key do not interleave
+	var versionID sql.NullString
+	err = store.ExecTx(r.Context(), func(q *db.Queries) error {
+		var err error
+		// Versioned buckets keep the current version as a noncurrent one
+		versionID, err = prepareNewVersion(r.Context(), q, bucketName, objectKey)
+		if err != nil {
+			return err
+		}
+
+		objectID, err := q.UpsertObject(r.Context(), db.UpsertObjectParams{
 			BucketName:         bucketName,
 			Key:                objectKey,
-			Data:               data,
-			Size:               int64(len(data)),
+			BlobID:             toNullString(blobID),
+			Size:               size,
 			ETag:               etag,
 			ContentType:        contentType,
 			ContentEncoding:    toNullString(r.Header.Get("Content-Encoding")),
 			ContentDisposition: toNullString(r.Header.Get("Content-Disposition")),
 			CacheControl:       toNullString(r.Header.Get("Cache-Control")),
 			StorageClass:       "STANDARD",
+			VersionID:          versionID,
+			ChecksumAlgorithm:  checksumAlgorithm,
+			ChecksumValue:      checksumValue,
+			ChecksumType:       checksumType,
 		})
-	}
-
-	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
-		return
-	}
+		if err != nil {
+			return err
+		}
 
-	objectID, err := store.Queries.GetObjectID(r.Context(), db.GetObjectIDParams{
-		BucketName: bucketName,
-		Key:        objectKey,
-	})
-	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
-		return
-	}
+		// A plain PutObject replaces any part layout left by a multipart upload
+		if err := q.DeleteObjectParts(r.Context(), objectID); err != nil {
+			return err
+		}
 
-	// Handle metadata
-	metadata := extractMetadata(r.Header)
-	if len(metadata) > 0 {
-		// Delete existing metadata and insert new
-		_ = store.Queries.DeleteObjectMetadata(r.Context(), objectID)
+		// Replace the metadata of an overwritten object
+		if err := q.DeleteObjectMetadata(r.Context(), objectID); err != nil {
+			return err
+		}
 		for k, v := range metadata {
-			_ = store.Queries.CreateObjectMetadata(r.Context(), db.CreateObjectMetadataParams{
+			if err := q.CreateObjectMetadata(r.Context(), db.CreateObjectMetadataParams{
 				ObjectID: objectID,
 				Key:      k,
 				Value:    v,
-			})
+			}); err != nil {
+				return err
+			}
 		}
-	}
 
-	store.Queries.CreateEvent(r.Context(), db.CreateEventParams{
-		BucketName: bucketName,
-		ObjectID:   objectID,
+		if err := q.DeleteObjectTags(r.Context(), objectID); err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if err := q.CreateObjectTag(r.Context(), db.CreateObjectTagParams{
+				ObjectID: objectID,
+				Key:      tag.Key,
+				Value:    tag.Value,
+			}); err != nil {
+				return err
+			}
+		}
+
+		_, err = event.Record(r.Context(), q, db.CreateEventParams{
+			BucketName:      bucketName,
+			ObjectID:        sql.NullInt64{Int64: objectID, Valid: true},
+			EventType:       event.ObjectCreatedPut,
+			ObjectKey:       objectKey,
+			ObjectSize:      size,
+			ObjectETag:      etag,
+			ObjectVersionID: versionID,
+		})
+		return err
 	})
+	if err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.FromError(err).WriteError(w)
+		return
+	}
 
 	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, etag))
+	writeVersionHeader(w, versionID)
+	writeChecksumHeaders(w, checksumAlgorithm, checksumValue, checksumType)
 	w.WriteHeader(http.StatusOK)
 }
 
@@ -134,7 +163,14 @@ type PutObjectRequestHeaders struct {
 	CacheControl              string // Cache-Control
 	ContentDisposition        string // Content-Disposition
 	ContentEncoding           string // Content-Encoding
+	ContentMD5                string // Content-MD5
 	ContentType               string // Content-Type
+	ChecksumAlgorithm         string // x-amz-sdk-checksum-algorithm
+	ChecksumCRC32             string // x-amz-checksum-crc32
+	ChecksumCRC32C            string // x-amz-checksum-crc32c
+	ChecksumCRC64NVME         string // x-amz-checksum-crc64nvme
+	ChecksumSHA1              string // x-amz-checksum-sha1
+	ChecksumSHA256            string // x-amz-checksum-sha256
 	Expires                   string // Expires
 	ACL                       string // x-amz-acl
 	GrantFullControl          string // x-amz-grant-full-control
@@ -151,11 +187,15 @@ type PutObjectRequestHeaders struct {
 	ObjectLockMode            string // x-amz-object-lock-mode
 	ObjectLockRetainUntilDate string // x-amz-object-lock-retain-until-date
 	ObjectLockLegalHoldStatus string // x-amz-object-lock-legal-hold-status
+	Tagging                   string // x-amz-tagging
 }
 
 // PutObjectResponseHeaders represents response headers for PutObject
 type PutObjectResponseHeaders struct {
-	ETag string // ETag
+	ETag         string // ETag
+	Checksum     string // x-amz-checksum-*
+	ChecksumType string // x-amz-checksum-type
+	VersionId    string // x-amz-version-id
 }
 
 func toNullString(s string) sql.NullString {
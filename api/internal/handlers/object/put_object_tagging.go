@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 	"github.com/tkasuz/s3local/internal/handlers/s3error"
 )
@@ -17,14 +18,12 @@ func PutObjectTagging(w http.ResponseWriter, r *http.Request) {
 	objectKey := ctx.GetObjectKey(r.Context())
 
 	// Check if object exists and get object ID
-	objectID, err := store.Queries.GetObjectID(r.Context(), db.GetObjectIDParams{
-		BucketName: bucketName,
-		Key:        objectKey,
-	})
-	if err != nil {
-		s3error.NewNoSuchKeyError(objectKey).WriteError(w)
+	objectID, versionID, s3Err := resolveObjectID(w, r, store.Queries, bucketName, objectKey)
+	if s3Err != nil {
+		s3Err.WriteError(w)
 		return
 	}
+	writeVersionHeader(w, versionID)
 
 	// Parse XML body
 	if r.Body == nil || r.ContentLength == 0 {
@@ -35,7 +34,7 @@ func PutObjectTagging(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		s3error.NewInternalError(err).WriteError(w)
+		s3error.FromError(err).WriteError(w)
 		return
 	}
 
@@ -51,13 +50,6 @@ func PutObjectTagging(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Delete existing tags
-	if err := store.Queries.DeleteObjectTags(r.Context(), objectID); err != nil {
-		s3error.NewInternalError(err).WriteError(w)
-		return
-	}
-
-	// Insert new tags
 	for _, tag := range tagging.TagSet.Tag {
 		// Validate tag key and value
 		if tag.Key == "" {
@@ -72,16 +64,27 @@ func PutObjectTagging(w http.ResponseWriter, r *http.Request) {
 			s3error.NewInvalidTagError("Tag value cannot be longer than 256 characters").WriteError(w)
 			return
 		}
+	}
 
-		err := store.Queries.CreateObjectTag(r.Context(), db.CreateObjectTagParams{
-			ObjectID: objectID,
-			Key:      tag.Key,
-			Value:    tag.Value,
-		})
-		if err != nil {
-			s3error.NewInternalError(err).WriteError(w)
-			return
+	err = store.ExecTx(r.Context(), func(q *db.Queries) error {
+		// Replace existing tags
+		if err := q.DeleteObjectTags(r.Context(), objectID); err != nil {
+			return err
 		}
+		for _, tag := range tagging.TagSet.Tag {
+			if err := q.CreateObjectTag(r.Context(), db.CreateObjectTagParams{
+				ObjectID: objectID,
+				Key:      tag.Key,
+				Value:    tag.Value,
+			}); err != nil {
+				return err
+			}
+		}
+		return createObjectEvent(r.Context(), q, objectID, event.ObjectTaggingPut)
+	})
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
 	}
 
 	w.WriteHeader(http.StatusOK)
@@ -62,7 +62,7 @@ func TestPutObjectTagging(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket",
 			Key:          "test-key",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "test-etag",
 			ContentType:  "text/plain",
@@ -123,6 +123,20 @@ func TestPutObjectTagging(t *testing.T) {
 		tags, err = store.Queries.GetObjectTags(context.Background(), objectID)
 		assert.NoError(t, err)
 		assert.Len(t, tags, 0)
+
+		// Both changes are recorded as events
+		events, err := store.Queries.ListEventsByBucket(context.Background(), db.ListEventsByBucketParams{
+			BucketName: "test-bucket",
+			Limit:      10,
+		})
+		require.NoError(t, err)
+		var eventTypes []string
+		for _, event := range events {
+			assert.Equal(t, "test-key", event.ObjectKey)
+			assert.Equal(t, "test-etag", event.ObjectETag)
+			eventTypes = append(eventTypes, event.EventType)
+		}
+		assert.ElementsMatch(t, []string{"s3:ObjectTagging:Put", "s3:ObjectTagging:Delete"}, eventTypes)
 	})
 
 	t.Run("Object Not Found", func(t *testing.T) {
@@ -154,7 +168,7 @@ func TestPutObjectTagging(t *testing.T) {
 		obj, err := store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket-update",
 			Key:          "test-key",
-			Data:         testData,
+			BlobID:       testutil.PutBlob(t, store, testData),
 			Size:         int64(len(testData)),
 			ETag:         "test-etag",
 			ContentType:  "text/plain",
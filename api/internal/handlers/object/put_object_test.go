@@ -3,12 +3,19 @@ package object
 import (
 	"bytes"
 	"context"
+	"database/sql"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/tkasuz/s3local/internal/db"
@@ -61,7 +68,20 @@ func TestPutObject(t *testing.T) {
 		})
 		assert.NoError(t, err)
 		assert.Equal(t, "test-key", obj.Key)
-		assert.Equal(t, testData, obj.Data)
+		assert.Equal(t, testData, testutil.ReadBlob(t, store, obj.BlobID))
+
+		// Verify the s3:ObjectCreated:Put event
+		events, err := store.Queries.ListEventsByBucket(context.Background(), db.ListEventsByBucketParams{
+			BucketName: "test-bucket",
+			Limit:      10,
+		})
+		assert.NoError(t, err)
+		if assert.Len(t, events, 1) {
+			assert.Equal(t, "s3:ObjectCreated:Put", events[0].EventType)
+			assert.Equal(t, "test-key", events[0].ObjectKey)
+			assert.Equal(t, int64(len(testData)), events[0].ObjectSize)
+			assert.Equal(t, obj.ETag, events[0].ObjectETag)
+		}
 	})
 
 	t.Run("Successfully upload with the same object key", func(t *testing.T) {
@@ -77,7 +97,7 @@ func TestPutObject(t *testing.T) {
 		_, err = store.Queries.CreateObject(context.Background(), db.CreateObjectParams{
 			BucketName:   "test-bucket-update",
 			Key:          "test-key",
-			Data:         initialData,
+			BlobID:       testutil.PutBlob(t, store, initialData),
 			Size:         int64(len(initialData)),
 			ETag:         "initial-etag",
 			ContentType:  "text/plain",
@@ -100,7 +120,149 @@ func TestPutObject(t *testing.T) {
 			Key:        "test-key",
 		})
 		assert.NoError(t, err)
-		assert.Equal(t, updatedData, obj.Data)
+		assert.Equal(t, updatedData, testutil.ReadBlob(t, store, obj.BlobID))
+	})
+
+	t.Run("Overwrite replaces the tags", func(t *testing.T) {
+		bucketName := "test-bucket-tags"
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   bucketName,
+			Region: "us-east-1",
+		})
+		assert.NoError(t, err)
+
+		tags := func(t *testing.T) []db.GetObjectTagsRow {
+			objectID, err := store.Queries.GetObjectID(context.Background(), db.GetObjectIDParams{
+				BucketName: bucketName,
+				Key:        "tagged.txt",
+			})
+			assert.NoError(t, err)
+			tags, err := store.Queries.GetObjectTags(context.Background(), objectID)
+			assert.NoError(t, err)
+			return tags
+		}
+
+		_, err = s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket:  aws.String(bucketName),
+			Key:     aws.String("tagged.txt"),
+			Body:    strings.NewReader("old"),
+			Tagging: aws.String("old=true"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []db.GetObjectTagsRow{{Key: "old", Value: "true"}}, tags(t))
+
+		_, err = s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket:  aws.String(bucketName),
+			Key:     aws.String("tagged.txt"),
+			Body:    strings.NewReader("new"),
+			Tagging: aws.String("project=s3local"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []db.GetObjectTagsRow{{Key: "project", Value: "s3local"}}, tags(t))
+
+		_, err = s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String("tagged.txt"),
+			Body:   strings.NewReader("untagged"),
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, tags(t))
+
+		_, err = s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket:  aws.String(bucketName),
+			Key:     aws.String("tagged.txt"),
+			Body:    strings.NewReader("invalid"),
+			Tagging: aws.String("a=1&a=2"),
+		})
+		var apiErr smithy.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, "InvalidArgument", apiErr.ErrorCode())
+		}
+	})
+
+	t.Run("Concurrent uploads to the same key", func(t *testing.T) {
+		bucketName := "test-bucket-concurrent"
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   bucketName,
+			Region: "us-east-1",
+		})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				writer := strconv.Itoa(i)
+				_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+					Bucket:   aws.String(bucketName),
+					Key:      aws.String("test-key"),
+					Body:     strings.NewReader("content " + writer),
+					Metadata: map[string]string{"writer-" + writer: writer},
+				})
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		// The object keeps the content and metadata of a single upload
+		obj, err := store.Queries.GetObject(context.Background(), db.GetObjectParams{
+			BucketName: bucketName,
+			Key:        "test-key",
+		})
+		assert.NoError(t, err)
+		writer := strings.TrimPrefix(string(testutil.ReadBlob(t, store, obj.BlobID)), "content ")
+		metadata, err := store.Queries.GetObjectMetadataByObjectID(context.Background(), obj.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, metadata, 1) {
+			assert.Equal(t, writer, metadata[0].Value)
+		}
+
+		// Every upload recorded its event
+		var events int
+		err = store.DB.QueryRow("SELECT COUNT(*) FROM events WHERE bucket_name = ?", bucketName).Scan(&events)
+		assert.NoError(t, err)
+		assert.Equal(t, 8, events)
+	})
+
+	t.Run("Concurrent uploads succeed without client retries", func(t *testing.T) {
+		bucketName := "test-bucket-concurrent-versioned"
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   bucketName,
+			Region: "us-east-1",
+		})
+		assert.NoError(t, err)
+		// Versioned PUTs read the current version before writing the new one
+		err = store.Queries.UpdateBucketVersioning(context.Background(), db.UpdateBucketVersioningParams{
+			VersioningStatus: sql.NullString{String: "Enabled", Valid: true},
+			Name:             bucketName,
+		})
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := range 64 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req, err := http.NewRequest(http.MethodPut, ts.URL+"/"+bucketName+"/test-key", strings.NewReader("content "+strconv.Itoa(i)))
+				if !assert.NoError(t, err) {
+					return
+				}
+				resp, err := http.DefaultClient.Do(req)
+				if !assert.NoError(t, err) {
+					return
+				}
+				defer resp.Body.Close()
+				body, _ := io.ReadAll(resp.Body)
+				assert.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+			}()
+		}
+		wg.Wait()
+
+		var versions int
+		err = store.DB.QueryRow("SELECT COUNT(*) FROM objects WHERE bucket_name = ?", bucketName).Scan(&versions)
+		assert.NoError(t, err)
+		assert.Equal(t, 64, versions)
 	})
 
 	t.Run("Successfully upload object with trailing slash (folder marker)", func(t *testing.T) {
@@ -158,3 +320,106 @@ func TestPutObject(t *testing.T) {
 		assert.Equal(t, "parent/child/subfolder/", obj.Key, "Key should include the full path with trailing slash")
 	})
 }
+
+func TestPutObject_Checksum(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Put("/*", PutObject)
+			r.Get("/*", GetObject)
+			r.Head("/*", HeadObject)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	assert.NoError(t, err)
+
+	t.Run("Store and return the checksum", func(t *testing.T) {
+		_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket:            aws.String("test-bucket"),
+			Key:               aws.String("checksum.txt"),
+			Body:              bytes.NewReader([]byte("hello world")),
+			ChecksumAlgorithm: types.ChecksumAlgorithmCrc32c,
+		})
+		assert.NoError(t, err)
+
+		obj, err := store.Queries.GetObject(context.Background(), db.GetObjectParams{
+			BucketName: "test-bucket",
+			Key:        "checksum.txt",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "CRC32C", obj.ChecksumAlgorithm.String)
+		assert.Equal(t, "yZRlqg==", obj.ChecksumValue.String)
+
+		// Checksums are only returned with x-amz-checksum-mode: ENABLED
+		head, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("checksum.txt"),
+		})
+		assert.NoError(t, err)
+		assert.Nil(t, head.ChecksumCRC32C)
+
+		head, err = s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:       aws.String("test-bucket"),
+			Key:          aws.String("checksum.txt"),
+			ChecksumMode: types.ChecksumModeEnabled,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "yZRlqg==", aws.ToString(head.ChecksumCRC32C))
+		assert.Equal(t, types.ChecksumTypeFullObject, head.ChecksumType)
+
+		resp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket:       aws.String("test-bucket"),
+			Key:          aws.String("checksum.txt"),
+			ChecksumMode: types.ChecksumModeEnabled,
+		})
+		assert.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, "yZRlqg==", aws.ToString(resp.ChecksumCRC32C))
+	})
+
+	t.Run("Reject checksum mismatch", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPut, ts.URL+"/test-bucket/mismatch.txt", strings.NewReader("hello world"))
+		req.Header.Set("x-amz-checksum-sha256", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+		resp, err := http.DefaultClient.Do(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, string(body), "<Code>XAmzContentChecksumMismatch</Code>")
+	})
+
+	t.Run("Reject Content-MD5 mismatch", func(t *testing.T) {
+		_, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket:     aws.String("test-bucket"),
+			Key:        aws.String("bad-md5.txt"),
+			Body:       bytes.NewReader([]byte("hello world")),
+			ContentMD5: aws.String("AAAAAAAAAAAAAAAAAAAAAA=="),
+		})
+		var apiErr smithy.APIError
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, "BadDigest", apiErr.ErrorCode())
+		}
+
+		exists, err := store.Queries.ObjectExists(context.Background(), db.ObjectExistsParams{
+			BucketName: "test-bucket",
+			Key:        "bad-md5.txt",
+		})
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
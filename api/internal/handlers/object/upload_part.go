@@ -0,0 +1,123 @@
+package object
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+const (
+	// minPartSize is the minimum size of every part except the last one (5 MiB)
+	minPartSize = 5 * 1024 * 1024
+	// maxPartNumber is the highest part number S3 accepts
+	maxPartNumber = 10000
+)
+
+// UploadPart handles PUT /{bucket}/{key}?partNumber={partNumber}&uploadId={uploadId}
+func UploadPart(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+	uploadID := r.URL.Query().Get("uploadId")
+
+	partNumber, s3Err := parsePartNumber(r.URL.Query().Get("partNumber"))
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	upload, err := store.Queries.GetMultipartUpload(r.Context(), db.GetMultipartUploadParams{
+		UploadID:   uploadID,
+		BucketName: bucketName,
+		Key:        objectKey,
+	})
+	if err == sql.ErrNoRows {
+		s3error.NewNoSuchUploadError(uploadID).WriteError(w)
+		return
+	}
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Parts are checksummed with the algorithm chosen at CreateMultipartUpload
+	algorithms := requestedAlgorithms(r)
+	uploadAlgorithm, hasUploadAlgorithm := checksum.ParseAlgorithm(upload.ChecksumAlgorithm.String)
+	if hasUploadAlgorithm {
+		algorithms = append(algorithms, uploadAlgorithm)
+	}
+
+	// Stream the body to the blob store while computing its MD5 and checksums
+	body := newDigestReader(r.Body, algorithms...)
+	blobID, size, err := store.PutBlob(r.Context(), body)
+	if err != nil {
+		s3error.FromError(err).WriteError(w)
+		return
+	}
+
+	// Verify Content-MD5 and x-amz-checksum-*
+	sum, s3Err := verifyStreamedIntegrity(r, body, store, blobID)
+	if s3Err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3Err.WriteError(w)
+		return
+	}
+
+	var checksumValue sql.NullString
+	if hasUploadAlgorithm {
+		if sum != nil && sum.Algorithm != uploadAlgorithm {
+			store.Blobs.Delete(r.Context(), blobID)
+			s3error.NewInvalidRequestError(fmt.Sprintf("Checksum Type mismatch occurred, expected checksum Type: %s, actual checksum Type: %s", uploadAlgorithm, sum.Algorithm)).WriteError(w)
+			return
+		}
+		value, _ := body.checksum(uploadAlgorithm)
+		checksumValue = toNullString(value)
+	}
+
+	etag := body.etag()
+
+	err = store.Queries.PutMultipartUploadPart(r.Context(), db.PutMultipartUploadPartParams{
+		UploadID:      uploadID,
+		PartNumber:    partNumber,
+		BlobID:        toNullString(blobID),
+		Size:          size,
+		ETag:          etag,
+		ChecksumValue: checksumValue,
+	})
+	if err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Delete the payload of a part uploaded again under the same number
+	store.ReleaseBlobs(r.Context())
+
+	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, etag))
+	writeChecksumHeaders(w, upload.ChecksumAlgorithm, checksumValue, sql.NullString{})
+	w.WriteHeader(http.StatusOK)
+}
+
+// UploadPartRequestHeaders represents request headers for UploadPart
+type UploadPartRequestHeaders struct {
+	ContentLength string // Content-Length
+	ContentMD5    string // Content-MD5
+	Checksum      string // x-amz-checksum-*
+	PartNumber    int64  // partNumber (query parameter)
+	UploadID      string // uploadId (query parameter)
+}
+
+// parsePartNumber validates a partNumber query parameter
+func parsePartNumber(value string) (int64, *s3error.Error) {
+	partNumber, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || partNumber < 1 || partNumber > maxPartNumber {
+		return 0, s3error.NewInvalidArgumentError(fmt.Sprintf("Part number must be an integer between 1 and %d, inclusive", maxPartNumber))
+	}
+	return partNumber, nil
+}
@@ -0,0 +1,147 @@
+package object
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/checksum"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+// UploadPartCopy handles PUT /{bucket}/{key}?partNumber={partNumber}&uploadId={uploadId}
+// with x-amz-copy-source
+func UploadPartCopy(w http.ResponseWriter, r *http.Request) {
+	store := ctx.GetStore(r.Context())
+	bucketName := ctx.GetBucketName(r.Context())
+	objectKey := ctx.GetObjectKey(r.Context())
+	uploadID := r.URL.Query().Get("uploadId")
+
+	partNumber, s3Err := parsePartNumber(r.URL.Query().Get("partNumber"))
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	upload, err := store.Queries.GetMultipartUpload(r.Context(), db.GetMultipartUploadParams{
+		UploadID:   uploadID,
+		BucketName: bucketName,
+		Key:        objectKey,
+	})
+	if err == sql.ErrNoRows {
+		s3error.NewNoSuchUploadError(uploadID).WriteError(w)
+		return
+	}
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	src, s3Err := loadCopySource(r, store)
+	if s3Err != nil {
+		s3Err.WriteError(w)
+		return
+	}
+
+	source, err := openBlob(r.Context(), store, src.BlobID)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	defer source.Close()
+
+	// Copy the whole source or the x-amz-copy-source-range
+	content := io.Reader(source)
+	if rangeHeader := r.Header.Get("x-amz-copy-source-range"); rangeHeader != "" {
+		rng, s3Err := parseRange(rangeHeader, src.Size)
+		if s3Err != nil || rng == nil || rng.Start+rng.Size > src.Size {
+			s3error.NewInvalidArgumentError(fmt.Sprintf("Range specified is not valid for source object of size: %d", src.Size)).WriteError(w)
+			return
+		}
+		content, err = sectionReader(source, rng.Start, rng.Size)
+		if err != nil {
+			s3error.NewInternalError(err).WriteError(w)
+			return
+		}
+	}
+
+	alg, hasAlgorithm := checksum.ParseAlgorithm(upload.ChecksumAlgorithm.String)
+	var body *digestReader
+	if hasAlgorithm {
+		body = newDigestReader(content, alg)
+	} else {
+		body = newDigestReader(content)
+	}
+	blobID, size, err := store.PutBlob(r.Context(), body)
+	if err != nil {
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+	etag := body.etag()
+
+	var checksumValue sql.NullString
+	if hasAlgorithm {
+		value, _ := body.checksum(alg)
+		checksumValue = toNullString(value)
+	}
+
+	err = store.Queries.PutMultipartUploadPart(r.Context(), db.PutMultipartUploadPartParams{
+		UploadID:      uploadID,
+		PartNumber:    partNumber,
+		BlobID:        toNullString(blobID),
+		Size:          size,
+		ETag:          etag,
+		ChecksumValue: checksumValue,
+	})
+	if err != nil {
+		store.Blobs.Delete(r.Context(), blobID)
+		s3error.NewInternalError(err).WriteError(w)
+		return
+	}
+
+	// Delete the payload of a part uploaded again under the same number
+	store.ReleaseBlobs(r.Context())
+
+	result := CopyPartResult{
+		Xmlns:        "http://s3.amazonaws.com/doc/2006-03-01/",
+		ETag:         fmt.Sprintf(`"%s"`, etag),
+		LastModified: time.Now().UTC().Format("2006-01-02T15:04:05.000Z"),
+	}
+	if checksumValue.Valid {
+		result.Checksums.set(alg, checksumValue.String)
+	}
+
+	if src.VersionID.Valid {
+		w.Header().Set("x-amz-copy-source-version-id", src.VersionID.String)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(result)
+}
+
+// CopyPartResult represents the S3 UploadPartCopy response
+type CopyPartResult struct {
+	XMLName      xml.Name `xml:"CopyPartResult"`
+	Xmlns        string   `xml:"xmlns,attr"`
+	ETag         string   `xml:"ETag"`
+	LastModified string   `xml:"LastModified"`
+	Checksums
+}
+
+// UploadPartCopyRequestHeaders represents request headers for UploadPartCopy
+type UploadPartCopyRequestHeaders struct {
+	CopySource                  string // x-amz-copy-source
+	CopySourceRange             string // x-amz-copy-source-range
+	CopySourceIfMatch           string // x-amz-copy-source-if-match
+	CopySourceIfModifiedSince   string // x-amz-copy-source-if-modified-since
+	CopySourceIfNoneMatch       string // x-amz-copy-source-if-none-match
+	CopySourceIfUnmodifiedSince string // x-amz-copy-source-if-unmodified-since
+	PartNumber                  int64  // partNumber (query parameter)
+	UploadID                    string // uploadId (query parameter)
+}
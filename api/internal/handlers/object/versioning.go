@@ -0,0 +1,317 @@
+package object
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"net/http"
+
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/s3error"
+)
+
+const (
+	// versioningEnabled and versioningSuspended are the bucket versioning states.
+	// A bucket that never had versioning enabled has no state.
+	versioningEnabled   = "Enabled"
+	versioningSuspended = "Suspended"
+
+	// nullVersionID addresses the version stored without a version ID
+	nullVersionID = "null"
+
+	// versionIDLength is the length of generated version IDs
+	versionIDLength = 32
+)
+
+// newVersionID returns a random version ID
+func newVersionID() (string, error) {
+	b := make([]byte, versionIDLength*3/4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validVersionID reports whether id is "null" or a version ID generated by newVersionID
+func validVersionID(id string) bool {
+	if id == nullVersionID {
+		return true
+	}
+	if len(id) != versionIDLength {
+		return false
+	}
+	_, err := base64.RawURLEncoding.DecodeString(id)
+	return err == nil
+}
+
+// versionIDString returns the version ID as reported by S3, "null" for the null version
+func versionIDString(versionID sql.NullString) string {
+	if !versionID.Valid {
+		return nullVersionID
+	}
+	return versionID.String
+}
+
+// writeVersionHeader sets x-amz-version-id for objects stored with a version ID
+func writeVersionHeader(w http.ResponseWriter, versionID sql.NullString) {
+	if versionID.Valid {
+		w.Header().Set("x-amz-version-id", versionID.String)
+	}
+}
+
+// prepareNewVersion makes room for a new current version of key and returns
+// its version ID.
+//
+// With versioning Enabled the current version becomes noncurrent and a new
+// version ID is returned. With versioning Suspended the null version is
+// replaced and any other current version becomes noncurrent. In both cases the
+// caller then creates the object. Unversioned buckets are left untouched so the
+// caller overwrites the object in place.
+//
+// Like an overwrite in an unversioned bucket, replacing the null version
+// records no s3:ObjectRemoved event: S3 only reports the new object.
+func prepareNewVersion(ctx context.Context, q *db.Queries, bucketName, key string) (sql.NullString, error) {
+	status, err := q.GetBucketVersioning(ctx, bucketName)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+
+	switch status.String {
+	case versioningEnabled:
+		versionID, err := newVersionID()
+		if err != nil {
+			return sql.NullString{}, err
+		}
+		if err := q.DemoteLatestObjectVersion(ctx, db.DemoteLatestObjectVersionParams{
+			BucketName: bucketName,
+			Key:        key,
+		}); err != nil {
+			return sql.NullString{}, err
+		}
+		return toNullString(versionID), nil
+	case versioningSuspended:
+		if err := q.DeleteObject(ctx, db.DeleteObjectParams{
+			BucketName: bucketName,
+			Key:        key,
+		}); err != nil {
+			return sql.NullString{}, err
+		}
+		if err := q.DemoteLatestObjectVersion(ctx, db.DemoteLatestObjectVersionParams{
+			BucketName: bucketName,
+			Key:        key,
+		}); err != nil {
+			return sql.NullString{}, err
+		}
+	}
+	return sql.NullString{}, nil
+}
+
+// loadObject returns the object version addressed by the versionId query
+// parameter, or the current version. When the version is a delete marker
+// x-amz-delete-marker is set on w before the error is returned.
+func loadObject(w http.ResponseWriter, r *http.Request, q *db.Queries, bucketName, key string) (db.Object, *s3error.Error) {
+	if !r.URL.Query().Has("versionId") {
+		obj, err := q.GetObject(r.Context(), db.GetObjectParams{
+			BucketName: bucketName,
+			Key:        key,
+		})
+		if err == sql.ErrNoRows {
+			// The current version may be a delete marker
+			latest, err := q.GetLatestObjectVersion(r.Context(), db.GetLatestObjectVersionParams{
+				BucketName: bucketName,
+				Key:        key,
+			})
+			if err == nil && latest.IsDeleteMarker {
+				w.Header().Set("x-amz-delete-marker", "true")
+				w.Header().Set("x-amz-version-id", versionIDString(latest.VersionID))
+			}
+			return db.Object{}, s3error.NewNoSuchKeyError(key)
+		}
+		if err != nil {
+			return db.Object{}, s3error.NewInternalError(err)
+		}
+		return obj, nil
+	}
+
+	versionID := r.URL.Query().Get("versionId")
+	if !validVersionID(versionID) {
+		return db.Object{}, s3error.NewInvalidArgumentError("Invalid version id specified")
+	}
+	obj, err := q.GetObjectVersion(r.Context(), db.GetObjectVersionParams{
+		BucketName: bucketName,
+		Key:        key,
+		VersionID:  versionID,
+	})
+	if err == sql.ErrNoRows {
+		return db.Object{}, s3error.NewNoSuchVersionError(key, versionID)
+	}
+	if err != nil {
+		return db.Object{}, s3error.NewInternalError(err)
+	}
+	if obj.IsDeleteMarker {
+		w.Header().Set("x-amz-delete-marker", "true")
+		w.Header().Set("x-amz-version-id", versionID)
+		return db.Object{}, s3error.NewMethodNotAllowedError(key)
+	}
+	return obj, nil
+}
+
+// resolveObjectID returns the ID of the object version addressed by the
+// versionId query parameter, or of the current version
+func resolveObjectID(w http.ResponseWriter, r *http.Request, q *db.Queries, bucketName, key string) (int64, sql.NullString, *s3error.Error) {
+	if !r.URL.Query().Has("versionId") {
+		obj, err := q.GetObjectMetadata(r.Context(), db.GetObjectMetadataParams{
+			BucketName: bucketName,
+			Key:        key,
+		})
+		if err == sql.ErrNoRows {
+			return 0, sql.NullString{}, s3error.NewNoSuchKeyError(key)
+		}
+		if err != nil {
+			return 0, sql.NullString{}, s3error.NewInternalError(err)
+		}
+		return obj.ID, obj.VersionID, nil
+	}
+
+	obj, s3Err := loadObject(w, r, q, bucketName, key)
+	if s3Err != nil {
+		return 0, sql.NullString{}, s3Err
+	}
+	return obj.ID, obj.VersionID, nil
+}
+
+// createObjectEvent records an event of eventType for the object version with
+// the given ID, taking a snapshot of its key, size, ETag and version
+func createObjectEvent(ctx context.Context, q *db.Queries, objectID int64, eventType string) error {
+	row, err := q.GetObjectByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	_, err = event.Record(ctx, q, db.CreateEventParams{
+		BucketName:      row.Object.BucketName,
+		ObjectID:        sql.NullInt64{Int64: objectID, Valid: true},
+		EventType:       eventType,
+		ObjectKey:       row.Object.Key,
+		ObjectSize:      row.Object.Size,
+		ObjectETag:      row.Object.ETag,
+		ObjectVersionID: row.Object.VersionID,
+	})
+	return err
+}
+
+// deletion is the outcome of deleteObject
+type deletion struct {
+	// VersionID is the deleted version, or the delete marker that was created
+	VersionID    string
+	DeleteMarker bool
+}
+
+// deleteObject deletes key according to the bucket's versioning state and
+// records the matching s3:ObjectRemoved event.
+//
+// A versionID permanently removes that version; when it was the current
+// version the most recent remaining one becomes current. Without a versionID
+// versioned buckets get a delete marker, while unversioned buckets delete the
+// object. Deleting something that does not exist succeeds without an event.
+func deleteObject(ctx context.Context, q *db.Queries, bucketName, key, versionID string) (deletion, error) {
+	if versionID != "" {
+		obj, err := q.GetObjectVersion(ctx, db.GetObjectVersionParams{
+			BucketName: bucketName,
+			Key:        key,
+			VersionID:  versionID,
+		})
+		if err == sql.ErrNoRows {
+			return deletion{VersionID: versionID}, nil
+		}
+		if err != nil {
+			return deletion{}, err
+		}
+
+		if err := q.DeleteObjectVersionByID(ctx, obj.ID); err != nil {
+			return deletion{}, err
+		}
+		if obj.IsLatest {
+			if err := q.PromoteLatestObjectVersion(ctx, db.PromoteLatestObjectVersionParams{
+				BucketName: bucketName,
+				Key:        key,
+			}); err != nil {
+				return deletion{}, err
+			}
+		}
+		if _, err := event.Record(ctx, q, db.CreateEventParams{
+			BucketName:      bucketName,
+			EventType:       event.ObjectRemovedDelete,
+			ObjectKey:       obj.Key,
+			ObjectSize:      obj.Size,
+			ObjectETag:      obj.ETag,
+			ObjectVersionID: obj.VersionID,
+		}); err != nil {
+			return deletion{}, err
+		}
+		return deletion{VersionID: versionID, DeleteMarker: obj.IsDeleteMarker}, nil
+	}
+
+	status, err := q.GetBucketVersioning(ctx, bucketName)
+	if err != nil {
+		return deletion{}, err
+	}
+	if !status.Valid {
+		return deletion{}, deleteUnversionedObject(ctx, q, bucketName, key)
+	}
+
+	// Versioned buckets keep the data and add a delete marker as the current version
+	markerVersionID, err := prepareNewVersion(ctx, q, bucketName, key)
+	if err != nil {
+		return deletion{}, err
+	}
+	markerID, err := q.CreateDeleteMarker(ctx, db.CreateDeleteMarkerParams{
+		BucketName: bucketName,
+		Key:        key,
+		VersionID:  markerVersionID,
+	})
+	if err != nil {
+		return deletion{}, err
+	}
+	if _, err := event.Record(ctx, q, db.CreateEventParams{
+		BucketName:      bucketName,
+		ObjectID:        sql.NullInt64{Int64: markerID, Valid: true},
+		EventType:       event.ObjectRemovedDeleteMarkerCreated,
+		ObjectKey:       key,
+		ObjectVersionID: markerVersionID,
+	}); err != nil {
+		return deletion{}, err
+	}
+	return deletion{VersionID: versionIDString(markerVersionID), DeleteMarker: true}, nil
+}
+
+// deleteUnversionedObject deletes an object of an unversioned bucket
+func deleteUnversionedObject(ctx context.Context, q *db.Queries, bucketName, key string) error {
+	obj, err := q.GetObjectMetadata(ctx, db.GetObjectMetadataParams{
+		BucketName: bucketName,
+		Key:        key,
+	})
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := q.DeleteObject(ctx, db.DeleteObjectParams{
+		BucketName: bucketName,
+		Key:        key,
+	}); err != nil {
+		return err
+	}
+
+	_, err = event.Record(ctx, q, db.CreateEventParams{
+		BucketName: bucketName,
+		EventType:  event.ObjectRemovedDelete,
+		ObjectKey:  obj.Key,
+		ObjectSize: obj.Size,
+		ObjectETag: obj.ETag,
+	})
+	return err
+}
@@ -0,0 +1,396 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestObjectVersioning(t *testing.T) {
+	t.Parallel()
+	testCtx := testutil.SetupTestDB(t)
+	store := ctx.GetStore(testCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Route("/{bucket}", func(r chi.Router) {
+		r.Use(ctx.WithBucketName())
+		r.Get("/", ListObjectVersions)
+		r.Post("/", DeleteObjects)
+		r.With(ctx.WithObjectKey()).Group(func(r chi.Router) {
+			r.Put("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("tagging") {
+					PutObjectTagging(w, r)
+					return
+				}
+				if r.Header.Get("x-amz-copy-source") != "" {
+					CopyObject(w, r)
+					return
+				}
+				PutObject(w, r)
+			})
+			r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Has("tagging") {
+					GetObjectTagging(w, r)
+					return
+				}
+				GetObject(w, r)
+			})
+			r.Head("/*", HeadObject)
+			r.Delete("/*", DeleteObject)
+		})
+	})
+
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	s3Client := testutil.CreateNewS3Client(ts)
+
+	for _, name := range []string{"versioned-bucket", "plain-bucket"} {
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   name,
+			Region: "us-east-1",
+		})
+		require.NoError(t, err)
+	}
+	err := store.Queries.UpdateBucketVersioning(context.Background(), db.UpdateBucketVersioningParams{
+		VersioningStatus: sql.NullString{String: "Enabled", Valid: true},
+		Name:             "versioned-bucket",
+	})
+	require.NoError(t, err)
+
+	errorCode := func(err error) string {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			return apiErr.ErrorCode()
+		}
+		return ""
+	}
+
+	putObject := func(t *testing.T, bucket, key, content string) string {
+		resp, err := s3Client.PutObject(context.Background(), &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader([]byte(content)),
+		})
+		require.NoError(t, err)
+		return aws.ToString(resp.VersionId)
+	}
+
+	getObject := func(t *testing.T, key, versionID string) (string, error) {
+		input := &s3.GetObjectInput{
+			Bucket: aws.String("versioned-bucket"),
+			Key:    aws.String(key),
+		}
+		if versionID != "" {
+			input.VersionId = aws.String(versionID)
+		}
+		resp, err := s3Client.GetObject(context.Background(), input)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), nil
+	}
+
+	t.Run("Overwrite keeps previous versions", func(t *testing.T) {
+		v1 := putObject(t, "versioned-bucket", "doc.txt", "first")
+		v2 := putObject(t, "versioned-bucket", "doc.txt", "second")
+		require.NotEmpty(t, v1)
+		require.NotEmpty(t, v2)
+		assert.NotEqual(t, v1, v2)
+
+		body, err := getObject(t, "doc.txt", "")
+		require.NoError(t, err)
+		assert.Equal(t, "second", body)
+
+		body, err = getObject(t, "doc.txt", v1)
+		require.NoError(t, err)
+		assert.Equal(t, "first", body)
+
+		headResp, err := s3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+			Bucket:    aws.String("versioned-bucket"),
+			Key:       aws.String("doc.txt"),
+			VersionId: aws.String(v1),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, v1, aws.ToString(headResp.VersionId))
+
+		_, err = getObject(t, "doc.txt", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchVersion", errorCode(err))
+	})
+
+	t.Run("Delete creates a delete marker", func(t *testing.T) {
+		v1 := putObject(t, "versioned-bucket", "removed.txt", "content")
+
+		delResp, err := s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket: aws.String("versioned-bucket"),
+			Key:    aws.String("removed.txt"),
+		})
+		require.NoError(t, err)
+		assert.True(t, aws.ToBool(delResp.DeleteMarker))
+		markerID := aws.ToString(delResp.VersionId)
+		require.NotEmpty(t, markerID)
+
+		_, err = getObject(t, "removed.txt", "")
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchKey", errorCode(err))
+
+		// The data is still reachable by version
+		body, err := getObject(t, "removed.txt", v1)
+		require.NoError(t, err)
+		assert.Equal(t, "content", body)
+
+		events, err := store.Queries.ListEventsByBucket(context.Background(), db.ListEventsByBucketParams{
+			BucketName: "versioned-bucket",
+			Limit:      100,
+		})
+		require.NoError(t, err)
+		var markerEvents int
+		for _, event := range events {
+			if event.EventType == "s3:ObjectRemoved:DeleteMarkerCreated" && event.ObjectKey == "removed.txt" {
+				markerEvents++
+				assert.Equal(t, markerID, event.ObjectVersionID.String)
+			}
+		}
+		assert.Equal(t, 1, markerEvents)
+
+		// Deleting the marker restores the previous version
+		delResp, err = s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket:    aws.String("versioned-bucket"),
+			Key:       aws.String("removed.txt"),
+			VersionId: aws.String(markerID),
+		})
+		require.NoError(t, err)
+		assert.True(t, aws.ToBool(delResp.DeleteMarker))
+
+		body, err = getObject(t, "removed.txt", "")
+		require.NoError(t, err)
+		assert.Equal(t, "content", body)
+	})
+
+	t.Run("Delete a specific version", func(t *testing.T) {
+		v1 := putObject(t, "versioned-bucket", "specific.txt", "old")
+		v2 := putObject(t, "versioned-bucket", "specific.txt", "new")
+
+		_, err := s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket:    aws.String("versioned-bucket"),
+			Key:       aws.String("specific.txt"),
+			VersionId: aws.String(v2),
+		})
+		require.NoError(t, err)
+
+		body, err := getObject(t, "specific.txt", "")
+		require.NoError(t, err)
+		assert.Equal(t, "old", body)
+
+		_, err = getObject(t, "specific.txt", v2)
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchVersion", errorCode(err))
+
+		resp, err := s3Client.DeleteObjects(context.Background(), &s3.DeleteObjectsInput{
+			Bucket: aws.String("versioned-bucket"),
+			Delete: &types.Delete{Objects: []types.ObjectIdentifier{
+				{Key: aws.String("specific.txt"), VersionId: aws.String(v1)},
+			}},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Deleted, 1)
+		assert.Equal(t, v1, aws.ToString(resp.Deleted[0].VersionId))
+
+		_, err = getObject(t, "specific.txt", "")
+		require.Error(t, err)
+		assert.Equal(t, "NoSuchKey", errorCode(err))
+	})
+
+	t.Run("Copy and tag a specific version", func(t *testing.T) {
+		v1 := putObject(t, "versioned-bucket", "source.txt", "original")
+		putObject(t, "versioned-bucket", "source.txt", "updated")
+
+		copyResp, err := s3Client.CopyObject(context.Background(), &s3.CopyObjectInput{
+			Bucket:     aws.String("plain-bucket"),
+			Key:        aws.String("copy.txt"),
+			CopySource: aws.String(fmt.Sprintf("versioned-bucket/source.txt?versionId=%s", v1)),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, v1, aws.ToString(copyResp.CopySourceVersionId))
+
+		getResp, err := s3Client.GetObject(context.Background(), &s3.GetObjectInput{
+			Bucket: aws.String("plain-bucket"),
+			Key:    aws.String("copy.txt"),
+		})
+		require.NoError(t, err)
+		defer getResp.Body.Close()
+		body, _ := io.ReadAll(getResp.Body)
+		assert.Equal(t, "original", string(body))
+		assert.Nil(t, getResp.VersionId)
+
+		_, err = s3Client.PutObjectTagging(context.Background(), &s3.PutObjectTaggingInput{
+			Bucket:    aws.String("versioned-bucket"),
+			Key:       aws.String("source.txt"),
+			VersionId: aws.String(v1),
+			Tagging: &types.Tagging{TagSet: []types.Tag{
+				{Key: aws.String("state"), Value: aws.String("archived")},
+			}},
+		})
+		require.NoError(t, err)
+
+		tagResp, err := s3Client.GetObjectTagging(context.Background(), &s3.GetObjectTaggingInput{
+			Bucket:    aws.String("versioned-bucket"),
+			Key:       aws.String("source.txt"),
+			VersionId: aws.String(v1),
+		})
+		require.NoError(t, err)
+		assert.Len(t, tagResp.TagSet, 1)
+
+		// The current version is not affected
+		tagResp, err = s3Client.GetObjectTagging(context.Background(), &s3.GetObjectTaggingInput{
+			Bucket: aws.String("versioned-bucket"),
+			Key:    aws.String("source.txt"),
+		})
+		require.NoError(t, err)
+		assert.Empty(t, tagResp.TagSet)
+	})
+
+	t.Run("List object versions", func(t *testing.T) {
+		a1 := putObject(t, "versioned-bucket", "list/a.txt", "a1")
+		a2 := putObject(t, "versioned-bucket", "list/a.txt", "a2")
+		putObject(t, "versioned-bucket", "list/b.txt", "b1")
+		delResp, err := s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket: aws.String("versioned-bucket"),
+			Key:    aws.String("list/b.txt"),
+		})
+		require.NoError(t, err)
+
+		resp, err := s3Client.ListObjectVersions(context.Background(), &s3.ListObjectVersionsInput{
+			Bucket: aws.String("versioned-bucket"),
+			Prefix: aws.String("list/"),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Versions, 3)
+		assert.Equal(t, a2, aws.ToString(resp.Versions[0].VersionId))
+		assert.True(t, aws.ToBool(resp.Versions[0].IsLatest))
+		assert.Equal(t, a1, aws.ToString(resp.Versions[1].VersionId))
+		assert.False(t, aws.ToBool(resp.Versions[1].IsLatest))
+		require.Len(t, resp.DeleteMarkers, 1)
+		assert.Equal(t, aws.ToString(delResp.VersionId), aws.ToString(resp.DeleteMarkers[0].VersionId))
+		assert.True(t, aws.ToBool(resp.DeleteMarkers[0].IsLatest))
+
+		// Page through one entry at a time
+		var versionIDs []string
+		input := &s3.ListObjectVersionsInput{
+			Bucket:  aws.String("versioned-bucket"),
+			Prefix:  aws.String("list/"),
+			MaxKeys: aws.Int32(1),
+		}
+		for {
+			page, err := s3Client.ListObjectVersions(context.Background(), input)
+			require.NoError(t, err)
+			for _, v := range page.Versions {
+				versionIDs = append(versionIDs, aws.ToString(v.VersionId))
+			}
+			for _, m := range page.DeleteMarkers {
+				versionIDs = append(versionIDs, aws.ToString(m.VersionId))
+			}
+			if !aws.ToBool(page.IsTruncated) {
+				break
+			}
+			input.KeyMarker = page.NextKeyMarker
+			input.VersionIdMarker = page.NextVersionIdMarker
+		}
+		assert.Len(t, versionIDs, 4)
+		assert.Equal(t, []string{a2, a1}, versionIDs[:2])
+
+		resp, err = s3Client.ListObjectVersions(context.Background(), &s3.ListObjectVersionsInput{
+			Bucket:    aws.String("versioned-bucket"),
+			Delimiter: aws.String("/"),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.CommonPrefixes, 1)
+		assert.Equal(t, "list/", aws.ToString(resp.CommonPrefixes[0].Prefix))
+	})
+
+	t.Run("Suspended versioning replaces the null version", func(t *testing.T) {
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   "suspended-bucket",
+			Region: "us-east-1",
+		})
+		require.NoError(t, err)
+		setVersioning := func(status string) {
+			err := store.Queries.UpdateBucketVersioning(context.Background(), db.UpdateBucketVersioningParams{
+				VersioningStatus: sql.NullString{String: status, Valid: true},
+				Name:             "suspended-bucket",
+			})
+			require.NoError(t, err)
+		}
+
+		setVersioning("Enabled")
+		v1 := putObject(t, "suspended-bucket", "doc.txt", "versioned")
+		setVersioning("Suspended")
+		putObject(t, "suspended-bucket", "doc.txt", "null one")
+		putObject(t, "suspended-bucket", "doc.txt", "null two")
+
+		// The null version is replaced in place; the versioned one is kept
+		resp, err := s3Client.ListObjectVersions(context.Background(), &s3.ListObjectVersionsInput{
+			Bucket: aws.String("suspended-bucket"),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Versions, 2)
+		assert.Equal(t, "null", aws.ToString(resp.Versions[0].VersionId))
+		assert.True(t, aws.ToBool(resp.Versions[0].IsLatest))
+		assert.Equal(t, int64(len("null two")), aws.ToInt64(resp.Versions[0].Size))
+		assert.Equal(t, v1, aws.ToString(resp.Versions[1].VersionId))
+		assert.False(t, aws.ToBool(resp.Versions[1].IsLatest))
+
+		// Like an overwrite in an unversioned bucket, replacing the null
+		// version records the new object only, as S3 does
+		events, err := store.Queries.ListEventsByBucket(context.Background(), db.ListEventsByBucketParams{
+			BucketName: "suspended-bucket",
+			Limit:      100,
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+		for _, event := range events {
+			assert.Equal(t, "s3:ObjectCreated:Put", event.EventType)
+		}
+	})
+
+	t.Run("Unversioned buckets report the null version", func(t *testing.T) {
+		assert.Empty(t, putObject(t, "plain-bucket", "plain.txt", "one"))
+		putObject(t, "plain-bucket", "plain.txt", "two")
+
+		resp, err := s3Client.ListObjectVersions(context.Background(), &s3.ListObjectVersionsInput{
+			Bucket: aws.String("plain-bucket"),
+			Prefix: aws.String("plain.txt"),
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Versions, 1)
+		assert.Equal(t, "null", aws.ToString(resp.Versions[0].VersionId))
+
+		delResp, err := s3Client.DeleteObject(context.Background(), &s3.DeleteObjectInput{
+			Bucket: aws.String("plain-bucket"),
+			Key:    aws.String("plain.txt"),
+		})
+		require.NoError(t, err)
+		assert.Nil(t, delResp.DeleteMarker)
+	})
+}
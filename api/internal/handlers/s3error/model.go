@@ -2,8 +2,10 @@ package s3error
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 )
 
 type ErrorCode string
@@ -16,18 +18,45 @@ const (
 	ErrCodeNoSuchBucket            ErrorCode = "NoSuchBucket"
 
 	// Object
-	ErrCodeNoSuchKey ErrorCode = "NoSuchKey"
+	ErrCodeNoSuchKey          ErrorCode = "NoSuchKey"
+	ErrCodeNoSuchVersion      ErrorCode = "NoSuchVersion"
+	ErrCodeInvalidRange       ErrorCode = "InvalidRange"
+	ErrCodePreconditionFailed ErrorCode = "PreconditionFailed"
+
+	// Integrity
+	ErrCodeBadDigest                   ErrorCode = "BadDigest"
+	ErrCodeInvalidDigest               ErrorCode = "InvalidDigest"
+	ErrCodeXAmzContentChecksumMismatch ErrorCode = "XAmzContentChecksumMismatch"
+
+	// Multipart upload
+	ErrCodeEntityTooSmall    ErrorCode = "EntityTooSmall"
+	ErrCodeInvalidPart       ErrorCode = "InvalidPart"
+	ErrCodeInvalidPartNumber ErrorCode = "InvalidPartNumber"
+	ErrCodeInvalidPartOrder  ErrorCode = "InvalidPartOrder"
+	ErrCodeNoSuchUpload      ErrorCode = "NoSuchUpload"
 
 	// Tagging
-	ErrCodeInvalidTag     ErrorCode = "InvalidTag"
-	ErrCodeMalformedXML   ErrorCode = "MalformedXML"
+	ErrCodeInvalidTag   ErrorCode = "InvalidTag"
+	ErrCodeMalformedXML ErrorCode = "MalformedXML"
 
 	// Policy
 	ErrCodeNoSuchBucketPolicy ErrorCode = "NoSuchBucketPolicy"
 	ErrCodeMalformedPolicy    ErrorCode = "MalformedPolicy"
 
+	// Authentication
+	ErrCodeAccessDenied                 ErrorCode = "AccessDenied"
+	ErrCodeAuthorizationHeaderMalformed ErrorCode = "AuthorizationHeaderMalformed"
+	ErrCodeInvalidAccessKeyId           ErrorCode = "InvalidAccessKeyId"
+	ErrCodeRequestTimeTooSkewed         ErrorCode = "RequestTimeTooSkewed"
+	ErrCodeSignatureDoesNotMatch        ErrorCode = "SignatureDoesNotMatch"
+	ErrCodeXAmzContentSHA256Mismatch    ErrorCode = "XAmzContentSHA256Mismatch"
+
 	// General
-	ErrCodeInternalError ErrorCode = "InternalError"
+	ErrCodeIncompleteBody   ErrorCode = "IncompleteBody"
+	ErrCodeInternalError    ErrorCode = "InternalError"
+	ErrCodeInvalidArgument  ErrorCode = "InvalidArgument"
+	ErrCodeInvalidRequest   ErrorCode = "InvalidRequest"
+	ErrCodeMethodNotAllowed ErrorCode = "MethodNotAllowed"
 )
 
 // Error represents the S3 error response
@@ -37,6 +66,12 @@ type Error struct {
 	Message   string   `xml:"Message"`
 	Resource  string   `xml:"Resource,omitempty"`
 	RequestId string   `xml:"RequestId,omitempty"`
+
+	// Additional elements returned by some errors
+	Condition        string `xml:"Condition,omitempty"`
+	RangeRequested   string `xml:"RangeRequested,omitempty"`
+	ActualObjectSize string `xml:"ActualObjectSize,omitempty"`
+	VersionId        string `xml:"VersionId,omitempty"`
 }
 
 func (e *Error) Error() string {
@@ -46,18 +81,36 @@ func (e *Error) Error() string {
 func (e *Error) WriteError(w http.ResponseWriter) {
 	w.Header().Set("Content-Type", "application/xml")
 	switch e.Code {
-	case string(ErrCodeNoSuchKey):
+	case string(ErrCodeNoSuchKey), string(ErrCodeNoSuchVersion):
 		w.WriteHeader(http.StatusNotFound)
 	case string(ErrCodeNoSuchBucket):
 		w.WriteHeader(http.StatusNotFound)
 	case string(ErrCodeNoSuchBucketPolicy):
 		w.WriteHeader(http.StatusNotFound)
+	case string(ErrCodeNoSuchUpload):
+		w.WriteHeader(http.StatusNotFound)
 	case string(ErrCodeBucketAlreadyExists), string(ErrCodeBucketAlreadyOwnedByYou):
 		w.WriteHeader(http.StatusConflict)
 	case string(ErrCodeBucketNotEmpty):
 		w.WriteHeader(http.StatusConflict)
 	case string(ErrCodeInvalidTag), string(ErrCodeMalformedXML), string(ErrCodeMalformedPolicy):
 		w.WriteHeader(http.StatusBadRequest)
+	case string(ErrCodeAuthorizationHeaderMalformed), string(ErrCodeXAmzContentSHA256Mismatch):
+		w.WriteHeader(http.StatusBadRequest)
+	case string(ErrCodeEntityTooSmall), string(ErrCodeInvalidPart), string(ErrCodeInvalidPartOrder), string(ErrCodeInvalidArgument):
+		w.WriteHeader(http.StatusBadRequest)
+	case string(ErrCodeBadDigest), string(ErrCodeInvalidDigest), string(ErrCodeXAmzContentChecksumMismatch), string(ErrCodeInvalidRequest):
+		w.WriteHeader(http.StatusBadRequest)
+	case string(ErrCodeIncompleteBody):
+		w.WriteHeader(http.StatusBadRequest)
+	case string(ErrCodeInvalidPartNumber), string(ErrCodeInvalidRange):
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	case string(ErrCodePreconditionFailed):
+		w.WriteHeader(http.StatusPreconditionFailed)
+	case string(ErrCodeMethodNotAllowed):
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	case string(ErrCodeAccessDenied), string(ErrCodeInvalidAccessKeyId), string(ErrCodeRequestTimeTooSkewed), string(ErrCodeSignatureDoesNotMatch):
+		w.WriteHeader(http.StatusForbidden)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -109,6 +162,136 @@ func NewNoSuchKeyError(key string) *Error {
 	}
 }
 
+// NewNoSuchVersionError creates a NoSuchVersion error with resource
+func NewNoSuchVersionError(key, versionID string) *Error {
+	return &Error{
+		Code:      string(ErrCodeNoSuchVersion),
+		Message:   "The specified version does not exist.",
+		Resource:  key,
+		VersionId: versionID,
+	}
+}
+
+// NewInvalidRangeError creates an InvalidRange error
+func NewInvalidRangeError(rangeRequested string, objectSize int64) *Error {
+	return &Error{
+		Code:             string(ErrCodeInvalidRange),
+		Message:          "The requested range is not satisfiable",
+		RangeRequested:   rangeRequested,
+		ActualObjectSize: strconv.FormatInt(objectSize, 10),
+	}
+}
+
+// NewPreconditionFailedError creates a PreconditionFailed error
+func NewPreconditionFailedError(condition string) *Error {
+	return &Error{
+		Code:      string(ErrCodePreconditionFailed),
+		Message:   "At least one of the pre-conditions you specified did not hold",
+		Condition: condition,
+	}
+}
+
+// NewNoSuchUploadError creates a NoSuchUpload error with resource
+func NewNoSuchUploadError(uploadID string) *Error {
+	return &Error{
+		Code:     string(ErrCodeNoSuchUpload),
+		Message:  "The specified upload does not exist. The upload ID may be invalid, or the upload may have been aborted or completed.",
+		Resource: uploadID,
+	}
+}
+
+// NewBadDigestError creates a BadDigest error
+func NewBadDigestError() *Error {
+	return &Error{
+		Code:    string(ErrCodeBadDigest),
+		Message: "The Content-MD5 you specified did not match what we received.",
+	}
+}
+
+// NewInvalidDigestError creates an InvalidDigest error
+func NewInvalidDigestError() *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidDigest),
+		Message: "The Content-MD5 you specified is not valid.",
+	}
+}
+
+// NewXAmzContentChecksumMismatchError creates a XAmzContentChecksumMismatch error
+func NewXAmzContentChecksumMismatchError(algorithm string) *Error {
+	return &Error{
+		Code:    string(ErrCodeXAmzContentChecksumMismatch),
+		Message: fmt.Sprintf("The provided '%s' checksum does not match what was computed.", algorithm),
+	}
+}
+
+// NewEntityTooSmallError creates an EntityTooSmall error
+func NewEntityTooSmallError() *Error {
+	return &Error{
+		Code:    string(ErrCodeEntityTooSmall),
+		Message: "Your proposed upload is smaller than the minimum allowed object size.",
+	}
+}
+
+// NewInvalidPartError creates an InvalidPart error
+func NewInvalidPartError() *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidPart),
+		Message: "One or more of the specified parts could not be found. The part may not have been uploaded, or the specified entity tag may not match the part's entity tag.",
+	}
+}
+
+// NewInvalidPartOrderError creates an InvalidPartOrder error
+func NewInvalidPartOrderError() *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidPartOrder),
+		Message: "The list of parts was not in ascending order. Parts must be ordered by part number.",
+	}
+}
+
+// NewInvalidPartNumberError creates an InvalidPartNumber error
+func NewInvalidPartNumberError() *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidPartNumber),
+		Message: "The requested partnumber is not satisfiable",
+	}
+}
+
+// NewInvalidArgumentError creates an InvalidArgument error
+func NewInvalidArgumentError(message string) *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidArgument),
+		Message: message,
+	}
+}
+
+// NewInvalidRequestError creates an InvalidRequest error
+func NewInvalidRequestError(message string) *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidRequest),
+		Message: message,
+	}
+}
+
+// NewMethodNotAllowedError creates a MethodNotAllowed error with resource
+func NewMethodNotAllowedError(resource string) *Error {
+	return &Error{
+		Code:     string(ErrCodeMethodNotAllowed),
+		Message:  "The specified method is not allowed against this resource.",
+		Resource: resource,
+	}
+}
+
+// NewIncompleteBodyError creates an IncompleteBody error
+func NewIncompleteBodyError(message string) *Error {
+	if message == "" {
+		message = "You did not provide the number of bytes specified by the Content-Length HTTP header."
+	}
+	return &Error{
+		Code:    string(ErrCodeIncompleteBody),
+		Message: message,
+	}
+}
+
 // NewInternalError creates an InternalError with optional wrapped message
 func NewInternalError(err error) *Error {
 	msg := "We encountered an internal error. Please try again."
@@ -153,3 +336,63 @@ func NewMalformedPolicyError(message string) *Error {
 		Message: message,
 	}
 }
+
+// NewAccessDeniedError creates an AccessDenied error
+func NewAccessDeniedError(message string) *Error {
+	if message == "" {
+		message = "Access Denied"
+	}
+	return &Error{
+		Code:    string(ErrCodeAccessDenied),
+		Message: message,
+	}
+}
+
+// NewAuthorizationHeaderMalformedError creates an AuthorizationHeaderMalformed error
+func NewAuthorizationHeaderMalformedError(message string) *Error {
+	return &Error{
+		Code:    string(ErrCodeAuthorizationHeaderMalformed),
+		Message: message,
+	}
+}
+
+// NewInvalidAccessKeyIdError creates an InvalidAccessKeyId error
+func NewInvalidAccessKeyIdError() *Error {
+	return &Error{
+		Code:    string(ErrCodeInvalidAccessKeyId),
+		Message: "The AWS Access Key Id you provided does not exist in our records.",
+	}
+}
+
+// NewRequestTimeTooSkewedError creates a RequestTimeTooSkewed error
+func NewRequestTimeTooSkewedError() *Error {
+	return &Error{
+		Code:    string(ErrCodeRequestTimeTooSkewed),
+		Message: "The difference between the request time and the current time is too large.",
+	}
+}
+
+// NewSignatureDoesNotMatchError creates a SignatureDoesNotMatch error
+func NewSignatureDoesNotMatchError() *Error {
+	return &Error{
+		Code:    string(ErrCodeSignatureDoesNotMatch),
+		Message: "The request signature we calculated does not match the signature you provided. Check your key and signing method.",
+	}
+}
+
+// NewXAmzContentSHA256MismatchError creates a XAmzContentSHA256Mismatch error
+func NewXAmzContentSHA256MismatchError() *Error {
+	return &Error{
+		Code:    string(ErrCodeXAmzContentSHA256Mismatch),
+		Message: "The provided 'x-amz-content-sha256' header does not match what was computed.",
+	}
+}
+
+// FromError returns err as an S3 error, wrapping unknown errors as InternalError
+func FromError(err error) *Error {
+	var s3Err *Error
+	if errors.As(err, &s3Err) {
+		return s3Err
+	}
+	return NewInternalError(err)
+}
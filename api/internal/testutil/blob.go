@@ -0,0 +1,39 @@
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+// PutBlob writes data to the store's blob store and returns the blob ID to
+// reference it from an object row
+func PutBlob(t *testing.T, store *db.Store, data []byte) sql.NullString {
+	t.Helper()
+
+	id, _, err := store.PutBlob(context.Background(), bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("failed to put blob: %v", err)
+	}
+	return sql.NullString{String: id, Valid: true}
+}
+
+// ReadBlob returns the content of a blob
+func ReadBlob(t *testing.T, store *db.Store, blobID sql.NullString) []byte {
+	t.Helper()
+
+	rc, err := store.Blobs.Open(context.Background(), blobID.String)
+	if err != nil {
+		t.Fatalf("failed to open blob: %v", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("failed to read blob: %v", err)
+	}
+	return data
+}
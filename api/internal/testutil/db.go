@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/tkasuz/s3local/internal/blob"
 	"github.com/tkasuz/s3local/internal/db"
 	"github.com/tkasuz/s3local/internal/handlers/ctx"
 )
@@ -34,7 +35,7 @@ func initTemplateDB() {
 		templateDBPath = tmpfile.Name()
 
 		// Open the database
-		database, err := sql.Open("sqlite3", templateDBPath+"?_foreign_keys=on")
+		database, err := sql.Open("sqlite3", db.DataSourceName(templateDBPath))
 		if err != nil {
 			setupErr = fmt.Errorf("failed to open sqlite: %w", err)
 			return
@@ -78,13 +79,19 @@ func SetupTestDB(t *testing.T) context.Context {
 	}
 
 	// Open the database
-	database, err := sql.Open("sqlite3", dbPath+"?_foreign_keys=on")
+	database, err := sql.Open("sqlite3", db.DataSourceName(dbPath))
 	if err != nil {
 		t.Fatalf("failed to open database: %v", err)
 	}
 
+	// Payloads are written to the filesystem blob store, the server default
+	blobs, err := blob.NewFilesystemStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create blob store: %v", err)
+	}
+
 	queries := db.New(database)
-	store := db.NewStore(database, queries)
+	store := db.NewStore(database, queries, blobs)
 
 	t.Cleanup(func() {
 		database.Close()
@@ -0,0 +1,76 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+)
+
+// cloudEventTypePrefix prefixes the event name of a record in the CloudEvents
+// type, e.g. com.amazonaws.s3.ObjectCreated.Put
+const cloudEventTypePrefix = "com.amazonaws.s3."
+
+// CloudEvent is a CloudEvents 1.0 event in the structured content mode
+type CloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time"`
+	DataContentType string `json:"datacontenttype"`
+	Data            any    `json:"data"`
+}
+
+// cloudEvent returns the event of job as a CloudEvent carrying its S3 event
+// record, or its test message. Destinations that receive an HTTP POST get the
+// binary content mode unless d asks for the structured one: the record is the
+// body and the attributes are sent as ce-* headers.
+func cloudEvent(job db.GetNotificationJobRow, d destination) ([]byte, http.Header, error) {
+	ce := CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              fmt.Sprintf("%d", job.Event.ID),
+		Source:          fmt.Sprintf("arn:aws:s3:::%s", job.Event.BucketName),
+		Type:            cloudEventTypePrefix + strings.ReplaceAll(event.RecordName(job.Event.EventType), ":", "."),
+		Subject:         job.Event.ObjectKey,
+		Time:            job.Event.EventTime.UTC().Format(time.RFC3339),
+		DataContentType: "application/json",
+	}
+	if job.Event.EventType == event.TestEvent {
+		ce.Data = testEvent(job)
+	} else {
+		ce.Data = eventRecord(job)
+	}
+
+	if !d.posts() || d.cloudEventsMode == config.CloudEventsStructured {
+		body, err := json.Marshal(ce)
+		if err != nil {
+			return nil, nil, err
+		}
+		header := http.Header{}
+		header.Set("Content-Type", "application/cloudevents+json; charset=UTF-8")
+		return body, header, nil
+	}
+
+	body, err := json.Marshal(ce.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set("Content-Type", ce.DataContentType)
+	header.Set("ce-specversion", ce.SpecVersion)
+	header.Set("ce-id", ce.ID)
+	header.Set("ce-source", ce.Source)
+	header.Set("ce-type", ce.Type)
+	if ce.Subject != "" {
+		header.Set("ce-subject", ce.Subject)
+	}
+	header.Set("ce-time", ce.Time)
+	return body, header, nil
+}
@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+)
+
+func TestCloudEvent(t *testing.T) {
+	t.Parallel()
+
+	var header http.Header
+	var body []byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		header, body = r.Header, data
+	}))
+	defer ts.Close()
+
+	job := db.GetNotificationJobRow{
+		Event: db.Event{
+			ID:         7,
+			BucketName: "test-bucket",
+			EventType:  event.ObjectCreatedPut,
+			ObjectKey:  "images/cat.png",
+			ObjectSize: 3,
+			EventTime:  time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		Notification: db.Notification{DestinationType: config.DriverWebhook, DestinationArn: ts.URL},
+	}
+	w := NewNotificationWorker(nil, config.DeliveryConfig{Timeout: 10 * time.Second}, nil)
+	send := func(mode string) {
+		d := destination{arn: ts.URL, driver: config.DriverWebhook, endpoint: ts.URL, format: config.FormatCloudEvents, cloudEventsMode: mode}
+		payload, h, err := cloudEvent(job, d)
+		require.NoError(t, err)
+		require.NoError(t, w.deliver(context.Background(), d, payload, h))
+	}
+
+	// Binary mode sends the record as the body and the attributes as headers
+	send("")
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+	assert.Equal(t, "1.0", header.Get("ce-specversion"))
+	assert.Equal(t, "7", header.Get("ce-id"))
+	assert.Equal(t, "arn:aws:s3:::test-bucket", header.Get("ce-source"))
+	assert.Equal(t, "com.amazonaws.s3.ObjectCreated.Put", header.Get("ce-type"))
+	assert.Equal(t, "images/cat.png", header.Get("ce-subject"))
+	assert.Equal(t, "2024-01-02T03:04:05Z", header.Get("ce-time"))
+	var record S3EventRecord
+	require.NoError(t, json.Unmarshal(body, &record))
+	assert.Equal(t, "ObjectCreated:Put", record.EventName)
+	assert.Equal(t, "images/cat.png", record.S3.Object.Key)
+
+	// Structured mode sends the whole envelope
+	send(config.CloudEventsStructured)
+	assert.Equal(t, "application/cloudevents+json; charset=UTF-8", header.Get("Content-Type"))
+	assert.Empty(t, header.Get("ce-id"))
+	var envelope struct {
+		CloudEvent
+		Data S3EventRecord `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, "1.0", envelope.SpecVersion)
+	assert.Equal(t, "com.amazonaws.s3.ObjectCreated.Put", envelope.Type)
+	assert.Equal(t, "application/json", envelope.DataContentType)
+	assert.Equal(t, "images/cat.png", envelope.Data.S3.Object.Key)
+
+	// Test events carry the test message
+	job.Event.EventType, job.Event.ObjectKey = event.TestEvent, ""
+	send("")
+	assert.Equal(t, "com.amazonaws.s3.TestEvent", header.Get("ce-type"))
+	assert.Empty(t, header.Get("ce-subject"))
+	var test S3TestEvent
+	require.NoError(t, json.Unmarshal(body, &test))
+	assert.Equal(t, event.TestEvent, test.Event)
+}
@@ -0,0 +1,255 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+// maxErrorBody is how much of a response body is kept on the job and in its
+// error message
+const maxErrorBody = 256
+
+// destination is the destination of a notification resolved through the
+// destination registry
+type destination struct {
+	// arn is the destination as configured in the notification
+	arn      string
+	driver   string
+	endpoint string
+	protocol string
+	// format and cloudEventsMode select the CloudEvents envelope
+	format          string
+	cloudEventsMode string
+	// headers, bearerToken and secret are added to POSTed events
+	headers     map[string]string
+	bearerToken string
+	secret      string
+}
+
+// resolve returns the destination of notification n. Destinations missing
+// from the registry are sent with the driver of their destination type to the
+// endpoint configured for it.
+func (w *NotificationWorker) resolve(n db.Notification) destination {
+	d := destination{arn: n.DestinationArn, driver: n.DestinationType}
+	if entry, ok := w.registry.Resolve(n.DestinationArn); ok {
+		d.endpoint, d.protocol = entry.Endpoint, entry.Protocol
+		d.format, d.cloudEventsMode = entry.Format, entry.CloudEventsMode
+		d.headers, d.bearerToken, d.secret = entry.Headers, entry.BearerToken, entry.Secret
+		if entry.Driver != "" {
+			d.driver = entry.Driver
+		}
+	} else {
+		switch d.driver {
+		case config.DriverSQS:
+			d.endpoint = w.delivery.SQS.Endpoint
+		case config.DriverSNS:
+			d.endpoint = w.delivery.SNS.Endpoint
+		case config.DriverLambda:
+			d.endpoint = w.delivery.Lambda.Endpoint
+		case config.DriverEventBridge:
+			d.endpoint = w.delivery.EventBridge.Endpoint
+		}
+	}
+	if d.driver == config.DriverSQS && d.protocol == "" {
+		d.protocol = w.delivery.SQS.Protocol
+	}
+	if d.driver == config.DriverWebhook && d.endpoint == "" {
+		d.endpoint = d.arn
+	}
+	return d
+}
+
+// deliver sends payload to destination d with the protocol of its driver.
+// Topics given as an http(s) URL and destinations without a driver of their
+// own receive the payload as an HTTP POST, with header and the headers
+// configured for the destination added to the request.
+func (w *NotificationWorker) deliver(ctx context.Context, d destination, payload []byte, header http.Header) error {
+	if d.posts() {
+		header = d.webhookHeader(header, payload)
+	}
+	switch d.driver {
+	case config.DriverSQS:
+		return w.sendSQSMessage(ctx, d, payload)
+	case config.DriverSNS:
+		if isURL(d.arn) {
+			return w.postJSON(ctx, d.arn, payload, header)
+		}
+		return w.publishSNSMessage(ctx, d, payload)
+	case config.DriverLambda:
+		return w.invokeLambda(ctx, d, payload)
+	case config.DriverEventBridge:
+		return w.sendEventBridgeEvent(ctx, d, payload, header)
+	case config.DriverWebhook:
+		return w.postJSON(ctx, d.endpoint, payload, header)
+	default:
+		return w.postJSON(ctx, d.arn, payload, header)
+	}
+}
+
+// posts reports whether the driver of d POSTs payloads as they are, rather
+// than wrapping them in a service API request
+func (d destination) posts() bool {
+	switch d.driver {
+	case config.DriverSQS, config.DriverLambda, config.DriverEventBridge:
+		return false
+	case config.DriverSNS:
+		return isURL(d.arn)
+	default:
+		return true
+	}
+}
+
+// postJSON POSTs payload to url as application/json, or with the headers in
+// header
+func (w *NotificationWorker) postJSON(ctx context.Context, url string, payload []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	return w.send(req)
+}
+
+// send performs req and returns an error unless the response status is 2xx
+func (w *NotificationWorker) send(req *http.Request) error {
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+// checkResponse returns an error unless the response status is 2xx. The error
+// includes the beginning of the response body, which stays readable. The
+// status and body are recorded in the response of the request context.
+func checkResponse(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	snippet := strings.TrimSpace(string(body))
+	if resp.Request != nil {
+		if r, ok := resp.Request.Context().Value(responseKey{}).(*response); ok {
+			r.status, r.body = resp.StatusCode, snippet
+		}
+	}
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if snippet != "" {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, snippet)
+	}
+	return fmt.Errorf("HTTP %d", resp.StatusCode)
+}
+
+// response is the last response received while delivering a job
+type response struct {
+	status int
+	body   string
+}
+
+type responseKey struct{}
+
+// withResponse returns ctx recording the responses of the requests made with
+// it in r
+func withResponse(ctx context.Context, r *response) context.Context {
+	return context.WithValue(ctx, responseKey{}, r)
+}
+
+// params returns the response columns of the job, NULL without a response
+func (r *response) params() (sql.NullInt64, sql.NullString) {
+	if r == nil || r.status == 0 {
+		return sql.NullInt64{}, sql.NullString{}
+	}
+	return sql.NullInt64{Int64: int64(r.status), Valid: true}, sql.NullString{String: r.body, Valid: r.body != ""}
+}
+
+// targets are the targets of a job sent to several targets, and whether each
+// already received it
+type targets struct {
+	jobID     int64
+	delivered map[string]bool
+}
+
+type targetsKey struct{}
+
+// withTargets returns ctx recording the targets job is delivered to, starting
+// with those it was delivered to by earlier attempts
+func withTargets(ctx context.Context, job db.NotificationJob) context.Context {
+	t := &targets{jobID: job.ID, delivered: make(map[string]bool)}
+	if job.DeliveredTargets.Valid {
+		var delivered []string
+		json.Unmarshal([]byte(job.DeliveredTargets.String), &delivered)
+		for _, target := range delivered {
+			t.delivered[target] = true
+		}
+	}
+	return context.WithValue(ctx, targetsKey{}, t)
+}
+
+// sendTarget calls send unless the job of ctx was already delivered to
+// target, and records the delivery on the job, so that a retry skips it
+func (w *NotificationWorker) sendTarget(ctx context.Context, target string, send func() error) error {
+	t, _ := ctx.Value(targetsKey{}).(*targets)
+	if t != nil && t.delivered[target] {
+		return nil
+	}
+	if err := send(); err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+	t.delivered[target] = true
+	err := w.store.Queries.AddNotificationJobDeliveredTarget(ctx, db.AddNotificationJobDeliveredTargetParams{
+		Target: target,
+		ID:     t.jobID,
+	})
+	if err != nil {
+		log.Printf("Error recording delivery of job %d to %s: %v", t.jobID, target, err)
+	}
+	return nil
+}
+
+// isURL reports whether destination is an http(s) URL rather than an ARN
+func isURL(destination string) bool {
+	return strings.HasPrefix(destination, "http://") || strings.HasPrefix(destination, "https://")
+}
+
+// splitARN returns the account and resource of arn, an ARN of service
+func splitARN(arn, service string) (account, resource string, err error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != service || parts[4] == "" || parts[5] == "" {
+		return "", "", fmt.Errorf("invalid %s ARN %q", service, arn)
+	}
+	return parts[4], parts[5], nil
+}
+
+// serviceEndpoint parses the configured endpoint of service for destination
+func serviceEndpoint(endpoint, service, destination string) (*url.URL, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("no %s endpoint configured for %s", service, destination)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s endpoint %q: %w", service, endpoint, err)
+	}
+	return u, nil
+}
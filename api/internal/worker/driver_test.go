@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+
+	w := NewNotificationWorker(nil, config.DeliveryConfig{
+		SQS:    config.SQSConfig{Endpoint: "http://sqs:9324", Protocol: config.SQSProtocolJSON},
+		Lambda: config.LambdaConfig{Endpoint: "http://lambda:8080"},
+	}, config.Destinations{
+		{ARN: "arn:aws:sqs:*:*:orders-*", Endpoint: "http://orders:9324", Protocol: config.SQSProtocolQuery},
+		{ARN: "arn:aws:sqs:*:*:*", Endpoint: "http://elasticmq:9324"},
+		{ARN: "arn:aws:lambda:us-east-1:123456789012:function:thumbnails", Driver: config.DriverWebhook, Endpoint: "http://thumbnails/events"},
+	})
+
+	for _, tc := range []struct {
+		destinationType string
+		arn             string
+		expected        destination
+	}{
+		// The first matching entry wins
+		{"sqs", "arn:aws:sqs:us-east-1:123456789012:orders-eu", destination{driver: "sqs", endpoint: "http://orders:9324", protocol: "query"}},
+		{"sqs", "arn:aws:sqs:us-east-1:123456789012:images", destination{driver: "sqs", endpoint: "http://elasticmq:9324", protocol: "json"}},
+		// Entries can change the driver
+		{"lambda", "arn:aws:lambda:us-east-1:123456789012:function:thumbnails", destination{driver: "webhook", endpoint: "http://thumbnails/events"}},
+		// Destinations missing from the registry use the endpoint of their type
+		{"lambda", "arn:aws:lambda:us-east-1:123456789012:function:resize", destination{driver: "lambda", endpoint: "http://lambda:8080"}},
+	} {
+		tc.expected.arn = tc.arn
+		d := w.resolve(db.Notification{DestinationType: tc.destinationType, DestinationArn: tc.arn})
+		assert.Equal(t, tc.expected, d, tc.arn)
+	}
+}
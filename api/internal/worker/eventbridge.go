@@ -0,0 +1,254 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+)
+
+// EventBridgeEvent is an S3 event as delivered by EventBridge
+type EventBridgeEvent struct {
+	Version    string            `json:"version"`
+	ID         string            `json:"id"`
+	DetailType string            `json:"detail-type"`
+	Source     string            `json:"source"`
+	Account    string            `json:"account"`
+	Time       string            `json:"time"`
+	Region     string            `json:"region"`
+	Resources  []string          `json:"resources"`
+	Detail     EventBridgeDetail `json:"detail"`
+}
+
+type EventBridgeDetail struct {
+	Version         string            `json:"version"`
+	Bucket          EventBridgeBucket `json:"bucket"`
+	Object          EventBridgeObject `json:"object"`
+	RequestID       string            `json:"request-id"`
+	Requester       string            `json:"requester"`
+	SourceIPAddress string            `json:"source-ip-address"`
+	Reason          string            `json:"reason,omitempty"`
+	DeletionType    string            `json:"deletion-type,omitempty"`
+}
+
+type EventBridgeBucket struct {
+	Name string `json:"name"`
+}
+
+type EventBridgeObject struct {
+	Key       string `json:"key"`
+	Size      *int64 `json:"size,omitempty"`
+	ETag      string `json:"etag,omitempty"`
+	VersionID string `json:"version-id,omitempty"`
+	Sequencer string `json:"sequencer"`
+}
+
+// eventBridgeDetail describes the EventBridge event of an S3 event type
+type eventBridgeDetail struct {
+	detailType   string
+	reason       string
+	deletionType string
+}
+
+// eventBridgeDetails maps the event types S3Local records to the detail of
+// their EventBridge events
+var eventBridgeDetails = map[string]eventBridgeDetail{
+	event.ObjectCreatedPut:                     {detailType: "Object Created", reason: "PutObject"},
+	event.ObjectCreatedCopy:                    {detailType: "Object Created", reason: "CopyObject"},
+	event.ObjectCreatedCompleteMultipartUpload: {detailType: "Object Created", reason: "CompleteMultipartUpload"},
+	event.ObjectRemovedDelete:                  {detailType: "Object Deleted", reason: "DeleteObject", deletionType: "Permanently Deleted"},
+	event.ObjectRemovedDeleteMarkerCreated:     {detailType: "Object Deleted", reason: "DeleteObject", deletionType: "Delete Marker Created"},
+	event.ObjectTaggingPut:                     {detailType: "Object Tags Added"},
+	event.ObjectTaggingDelete:                  {detailType: "Object Tags Deleted"},
+}
+
+// eventBridgePayload returns the EventBridge event for the event of job
+func eventBridgePayload(job db.GetNotificationJobRow) ([]byte, error) {
+	detail, ok := eventBridgeDetails[job.Event.EventType]
+	if !ok {
+		return nil, fmt.Errorf("%s is not sent to EventBridge", job.Event.EventType)
+	}
+
+	e := EventBridgeEvent{
+		Version:    "0",
+		ID:         fmt.Sprintf("00000000-0000-0000-0000-%012x", job.Event.ID),
+		DetailType: detail.detailType,
+		Source:     "aws.s3",
+		Account:    "000000000000",
+		Time:       job.Event.EventTime.UTC().Format(time.RFC3339),
+		Region:     "us-east-1",
+		Resources:  []string{fmt.Sprintf("arn:aws:s3:::%s", job.Event.BucketName)},
+		Detail: EventBridgeDetail{
+			Version: "0",
+			Bucket:  EventBridgeBucket{Name: job.Event.BucketName},
+			Object: EventBridgeObject{
+				Key:       job.Event.ObjectKey,
+				ETag:      job.Event.ObjectETag,
+				VersionID: job.Event.ObjectVersionID.String,
+				Sequencer: fmt.Sprintf("%016x", job.Event.ID),
+			},
+			RequestID:       fmt.Sprintf("%d", job.Event.ID),
+			Requester:       "s3local",
+			SourceIPAddress: "127.0.0.1",
+			Reason:          detail.reason,
+			DeletionType:    detail.deletionType,
+		},
+	}
+	if detail.detailType == "Object Created" {
+		e.Detail.Object.Size = &job.Event.ObjectSize
+	}
+	return json.Marshal(e)
+}
+
+// sendEventBridgeEvent puts the EventBridge event payload on the configured
+// event bus and sends it to the target of every local rule it matches, with
+// header added to POSTs. The bus and rules that received the event in an
+// earlier attempt are skipped.
+func (w *NotificationWorker) sendEventBridgeEvent(ctx context.Context, d destination, payload []byte, header http.Header) error {
+	rules := w.delivery.EventBridge.Rules
+	if d.endpoint == "" && len(rules) == 0 {
+		return fmt.Errorf("no EventBridge endpoint or rules configured for %s", d.arn)
+	}
+
+	var errs []error
+	if d.endpoint != "" {
+		errs = append(errs, w.sendTarget(ctx, "event-bus/"+w.delivery.EventBridge.Bus, func() error {
+			return w.putEvents(ctx, d.endpoint, w.delivery.EventBridge.Bus, payload)
+		}))
+	}
+	for i, rule := range rules {
+		pattern, err := rule.Pattern()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+			continue
+		}
+		if matched, _ := pattern.Match(payload); !matched {
+			continue
+		}
+		err = w.sendTarget(ctx, ruleTarget(i, rule), func() error {
+			return w.sendToTarget(ctx, rule.Target, payload, header)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ruleTarget identifies the target of the i-th rule among the targets of a
+// job: by the name of the rule, or its position when it has none
+func ruleTarget(i int, rule config.EventBridgeRule) string {
+	if rule.Name != "" {
+		return "rule/" + rule.Name
+	}
+	return fmt.Sprintf("rule/%d", i)
+}
+
+// sendToTarget sends payload to the target of a rule: an event bus ARN gets
+// the event with PutEvents, other ARNs are delivered with the driver of their
+// service and URLs receive a POST
+func (w *NotificationWorker) sendToTarget(ctx context.Context, target string, payload []byte, header http.Header) error {
+	n := db.Notification{DestinationType: config.DriverWebhook, DestinationArn: target}
+	if parts := strings.SplitN(target, ":", 6); !isURL(target) && len(parts) == 6 {
+		if parts[2] == "events" && strings.HasPrefix(parts[5], "event-bus/") {
+			return w.putEventsOnBus(ctx, target, payload)
+		}
+		n.DestinationType = parts[2]
+	}
+	return w.deliver(ctx, w.resolve(n), payload, header)
+}
+
+// putEventsOnBus puts the EventBridge event payload on the event bus with ARN
+// busArn, at the endpoint registered for the ARN or the EventBridge endpoint
+func (w *NotificationWorker) putEventsOnBus(ctx context.Context, busArn string, payload []byte) error {
+	endpoint := w.delivery.EventBridge.Endpoint
+	if entry, ok := w.registry.Resolve(busArn); ok && entry.Endpoint != "" {
+		endpoint = entry.Endpoint
+	}
+	if endpoint == "" {
+		return fmt.Errorf("no EventBridge endpoint configured for %s", busArn)
+	}
+	return w.putEvents(ctx, endpoint, busArn, payload)
+}
+
+// putEventsEntry is an entry of a PutEvents request
+type putEventsEntry struct {
+	Source       string   `json:"Source"`
+	DetailType   string   `json:"DetailType"`
+	Detail       string   `json:"Detail"`
+	Resources    []string `json:"Resources"`
+	Time         string   `json:"Time"`
+	EventBusName string   `json:"EventBusName"`
+}
+
+// putEventsOutput is the response of a PutEvents request
+type putEventsOutput struct {
+	FailedEntryCount int `json:"FailedEntryCount"`
+	Entries          []struct {
+		ErrorCode    string `json:"ErrorCode"`
+		ErrorMessage string `json:"ErrorMessage"`
+	} `json:"Entries"`
+}
+
+// putEvents puts the EventBridge event payload on bus, given by name or ARN,
+// with the PutEvents API
+func (w *NotificationWorker) putEvents(ctx context.Context, endpoint, bus string, payload []byte) error {
+	var e EventBridgeEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return err
+	}
+	detail, err := json.Marshal(e.Detail)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string][]putEventsEntry{
+		"Entries": {{
+			Source:       e.Source,
+			DetailType:   e.DetailType,
+			Detail:       string(detail),
+			Resources:    e.Resources,
+			Time:         e.Time,
+			EventBusName: bus,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("Failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "AWSEvents.PutEvents")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	// PutEvents reports entries it could not put in a successful response
+	var output putEventsOutput
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&output); err != nil || output.FailedEntryCount == 0 {
+		return nil
+	}
+	for _, entry := range output.Entries {
+		if entry.ErrorCode != "" {
+			return fmt.Errorf("PutEvents failed: %s: %s", entry.ErrorCode, entry.ErrorMessage)
+		}
+	}
+	return fmt.Errorf("PutEvents failed for %d entries", output.FailedEntryCount)
+}
@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestSendEventBridgeEvent(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var entries []putEventsEntry
+	bus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "AWSEvents.PutEvents", r.Header.Get("X-Amz-Target"))
+		var input map[string][]putEventsEntry
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&input))
+		mu.Lock()
+		entries = append(entries, input["Entries"]...)
+		mu.Unlock()
+		w.Write([]byte(`{"FailedEntryCount":0,"Entries":[{"EventId":"1"}]}`))
+	}))
+	defer bus.Close()
+
+	var targeted []EventBridgeEvent
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e EventBridgeEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+		mu.Lock()
+		targeted = append(targeted, e)
+		mu.Unlock()
+	}))
+	defer target.Close()
+
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:*",
+		DestinationType: "eventbridge",
+		DestinationArn:  "arn:aws:events:us-east-1:000000000000:event-bus/default",
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+
+	for _, e := range []db.CreateEventParams{
+		{BucketName: "test-bucket", EventType: event.ObjectCreatedPut, ObjectKey: "images/cat.png", ObjectSize: 3},
+		{BucketName: "test-bucket", EventType: event.ObjectRemovedDeleteMarkerCreated, ObjectKey: "docs/readme.txt"},
+	} {
+		_, err = event.Record(context.Background(), store.Queries, e)
+		require.NoError(t, err)
+	}
+
+	w := NewNotificationWorker(store, config.DeliveryConfig{
+		Workers:                4,
+		DestinationConcurrency: 4,
+		Timeout:                10 * time.Second,
+		MaxAttempts:            1,
+		EventBridge: config.EventBridgeConfig{
+			Endpoint: bus.URL,
+			Bus:      "default",
+			Rules: []config.EventBridgeRule{{
+				Name: "images",
+				EventPattern: map[string]any{
+					"detail-type": []any{"Object Created"},
+					"detail": map[string]any{
+						"object": map[string]any{"key": []any{map[string]any{"prefix": "images/"}}},
+					},
+				},
+				Target: target.URL,
+			}, {
+				Name:         "deletions",
+				EventPattern: map[string]any{"detail-type": []any{"Object Deleted"}},
+				Target:       "arn:aws:events:us-east-1:000000000000:event-bus/deletions",
+			}},
+		},
+	}, nil)
+	w.processJobs(context.Background())
+	w.wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Every event is put on the bus, and the matching one on the bus of the
+	// rule target
+	require.Len(t, entries, 3)
+	detailTypes := map[string]EventBridgeDetail{}
+	for _, entry := range entries {
+		assert.Equal(t, "aws.s3", entry.Source)
+		if entry.EventBusName != "default" {
+			assert.Equal(t, "arn:aws:events:us-east-1:000000000000:event-bus/deletions", entry.EventBusName)
+			assert.Equal(t, "Object Deleted", entry.DetailType)
+			continue
+		}
+		assert.Equal(t, []string{"arn:aws:s3:::test-bucket"}, entry.Resources)
+		var detail EventBridgeDetail
+		require.NoError(t, json.Unmarshal([]byte(entry.Detail), &detail))
+		detailTypes[entry.DetailType] = detail
+	}
+	require.Contains(t, detailTypes, "Object Created")
+	assert.Equal(t, "PutObject", detailTypes["Object Created"].Reason)
+	assert.Equal(t, int64(3), *detailTypes["Object Created"].Object.Size)
+	require.Contains(t, detailTypes, "Object Deleted")
+	assert.Equal(t, "Delete Marker Created", detailTypes["Object Deleted"].DeletionType)
+
+	// Only the matching event reaches the target of the local rule
+	require.Len(t, targeted, 1)
+	assert.Equal(t, "Object Created", targeted[0].DetailType)
+	assert.Equal(t, "images/cat.png", targeted[0].Detail.Object.Key)
+}
+
+func TestSendEventBridgeEventRetry(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	received := map[string]int{}
+	count := func(name string, fail bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			received[name]++
+			if fail && received[name] == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	}
+	bus := count("bus", false)
+	defer bus.Close()
+	images := count("images", false)
+	defer images.Close()
+	archive := count("archive", true)
+	defer archive.Close()
+
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:*",
+		DestinationType: "eventbridge",
+		DestinationArn:  "arn:aws:events:us-east-1:000000000000:event-bus/default",
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	e, err := event.Record(context.Background(), store.Queries, db.CreateEventParams{
+		BucketName: "test-bucket",
+		EventType:  event.ObjectCreatedPut,
+		ObjectKey:  "images/cat.png",
+	})
+	require.NoError(t, err)
+
+	created := map[string]any{"detail-type": []any{"Object Created"}}
+	w := NewNotificationWorker(store, config.DeliveryConfig{
+		Workers:                1,
+		DestinationConcurrency: 1,
+		Timeout:                10 * time.Second,
+		MaxAttempts:            3,
+		EventBridge: config.EventBridgeConfig{
+			Endpoint: bus.URL,
+			Bus:      "default",
+			Rules: []config.EventBridgeRule{
+				{Name: "images", EventPattern: created, Target: images.URL},
+				{EventPattern: created, Target: archive.URL},
+			},
+		},
+	}, nil)
+
+	// The first attempt fails for the second target only
+	w.processJobs(context.Background())
+	w.wg.Wait()
+	jobs, err := store.Queries.ListNotificationJobsByEvent(context.Background(), e.ID)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	job := jobs[0].NotificationJob
+	assert.Equal(t, "pending", job.Status)
+	assert.JSONEq(t, `["event-bus/default", "rule/images"]`, job.DeliveredTargets.String)
+
+	// The retry sends the event to that target alone
+	w.processJobs(context.Background())
+	w.wg.Wait()
+	jobs, err = store.Queries.ListNotificationJobsByEvent(context.Background(), e.ID)
+	require.NoError(t, err)
+	job = jobs[0].NotificationJob
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, int64(2), job.Attempts)
+	assert.JSONEq(t, `["event-bus/default", "rule/images", "rule/1"]`, job.DeliveredTargets.String)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]int{"bus": 1, "images": 1, "archive": 2}, received)
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// invokeLambda invokes the function d asynchronously with payload as its
+// event, like S3 does
+func (w *NotificationWorker) invokeLambda(ctx context.Context, d destination, payload []byte) error {
+	invocationURL, err := lambdaInvocationURL(d.endpoint, d.arn)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, invocationURL.String(), bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("Failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Amz-Invocation-Type", "Event")
+	return w.send(req)
+}
+
+// lambdaInvocationURL returns the Invoke URL of the function destination. A
+// function ARN arn:aws:lambda:{region}:{account}:function:{name}[:{qualifier}]
+// maps to {endpoint}/2015-03-31/functions/{name}/invocations, with the
+// qualifier as the Qualifier parameter; an http(s) URL is the invocation URL
+// itself.
+func lambdaInvocationURL(endpoint, destination string) (*url.URL, error) {
+	if isURL(destination) {
+		return url.Parse(destination)
+	}
+
+	_, resource, err := splitARN(destination, "lambda")
+	if err != nil {
+		return nil, err
+	}
+	function, ok := strings.CutPrefix(resource, "function:")
+	if !ok || function == "" {
+		return nil, fmt.Errorf("invalid lambda ARN %q", destination)
+	}
+	name, qualifier, _ := strings.Cut(function, ":")
+
+	base, err := serviceEndpoint(endpoint, "Lambda", destination)
+	if err != nil {
+		return nil, err
+	}
+	u := base.JoinPath("2015-03-31", "functions", name, "invocations")
+	if qualifier != "" {
+		u.RawQuery = url.Values{"Qualifier": {qualifier}}.Encode()
+	}
+	return u, nil
+}
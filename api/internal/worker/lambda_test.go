@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+)
+
+func TestInvokeLambda(t *testing.T) {
+	t.Parallel()
+
+	var requestURI, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Event", r.Header.Get("X-Amz-Invocation-Type"))
+		data, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		requestURI, body = r.RequestURI, string(data)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	w := NewNotificationWorker(nil, config.DeliveryConfig{Timeout: 10 * time.Second}, nil)
+	invoke := func(arn string) error {
+		d := destination{arn: arn, driver: config.DriverLambda, endpoint: ts.URL}
+		return w.invokeLambda(context.Background(), d, []byte(`{"Records":[]}`))
+	}
+
+	require.NoError(t, invoke("arn:aws:lambda:us-east-1:000000000000:function:my-function"))
+	assert.Equal(t, "/2015-03-31/functions/my-function/invocations", requestURI)
+	assert.Equal(t, `{"Records":[]}`, body)
+
+	require.NoError(t, invoke("arn:aws:lambda:us-east-1:000000000000:function:my-function:live"))
+	assert.Equal(t, "/2015-03-31/functions/my-function/invocations?Qualifier=live", requestURI)
+
+	// Invocation URLs are used as they are
+	require.NoError(t, invoke(ts.URL+"/2015-03-31/functions/function/invocations"))
+	assert.Equal(t, "/2015-03-31/functions/function/invocations", requestURI)
+
+	err := invoke("arn:aws:lambda:us-east-1:000000000000:layer:my-layer")
+	assert.ErrorContains(t, err, "invalid lambda ARN")
+}
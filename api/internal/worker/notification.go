@@ -1,16 +1,18 @@
 package worker
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/tkasuz/s3local/internal/config"
 	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
 )
 
 // S3 Event Message Structures
@@ -31,6 +33,17 @@ type S3EventRecord struct {
 	GlacierEventData  *S3GlacierEventData          `json:"glacierEventData,omitempty"`
 }
 
+// S3TestEvent is the message sent to a destination when a notification
+// configuration is saved
+type S3TestEvent struct {
+	Service   string `json:"Service"`
+	Event     string `json:"Event"`
+	Time      string `json:"Time"`
+	Bucket    string `json:"Bucket"`
+	RequestID string `json:"RequestId"`
+	HostID    string `json:"HostId"`
+}
+
 type S3UserIdentity struct {
 	PrincipalID string `json:"principalId"`
 }
@@ -80,19 +93,46 @@ type S3RestoreEventData struct {
 
 type NotificationWorker struct {
 	store      *db.Store
+	delivery   config.DeliveryConfig
+	registry   config.Destinations
 	httpClient *http.Client
 	ticker     *time.Ticker
-	done       chan bool
+	// done is closed by Stop
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// wake runs processJobs before the next tick
+	wake chan struct{}
+	// tick runs processJobs and receives the number of jobs being sent once
+	// they are sent
+	tick chan chan int
+	// slots holds a token for every job being sent
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	mu sync.Mutex
+	// destinations counts the jobs being sent to each destination
+	destinations map[string]int
+	// sending holds a channel closed once the job is sent for every job
+	// being sent
+	sending map[int64]chan struct{}
 }
 
-func NewNotificationWorker(store *db.Store) *NotificationWorker {
+func NewNotificationWorker(store *db.Store, delivery config.DeliveryConfig, destinations config.Destinations) *NotificationWorker {
 	return &NotificationWorker{
-		store: store,
+		store:    store,
+		delivery: delivery,
+		registry: destinations,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: delivery.Timeout,
 		},
-		ticker: time.NewTicker(1 * time.Second),
-		done:   make(chan bool),
+		ticker:       time.NewTicker(1 * time.Second),
+		done:         make(chan struct{}),
+		wake:         make(chan struct{}, 1),
+		tick:         make(chan chan int),
+		slots:        make(chan struct{}, delivery.Workers),
+		destinations: make(map[string]int),
+		sending:      make(map[int64]chan struct{}),
 	}
 }
 
@@ -109,52 +149,158 @@ func (w *NotificationWorker) Start(ctx context.Context) {
 			return
 		case <-w.ticker.C:
 			w.processJobs(ctx)
+		case <-w.wake:
+			w.processJobs(ctx)
+		case sent := <-w.tick:
+			w.processJobs(ctx)
+			go w.awaitSending(sent)
 		}
 	}
 }
 
+// Stop stops claiming jobs and waits for the jobs being sent. It returns even
+// if Start already returned, and may be called more than once.
 func (w *NotificationWorker) Stop() {
-	w.ticker.Stop()
-	w.done <- true
+	w.stopOnce.Do(func() {
+		w.ticker.Stop()
+		close(w.done)
+	})
+	w.wg.Wait()
 }
 
+// processJobs claims as many due jobs as there are free workers and sends
+// each of them in its own goroutine. Jobs whose destination already receives
+// DestinationConcurrency jobs are left for a later run.
 func (w *NotificationWorker) processJobs(ctx context.Context) {
-	// List all pending notification jobs
-	jobs, err := w.store.Queries.ListPendingNotificationJobs(ctx)
+	free := cap(w.slots) - len(w.slots)
+	if free == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	ids, err := w.store.Queries.ClaimNotificationJobs(ctx, db.ClaimNotificationJobsParams{
+		ClaimedUntil: sql.NullTime{Time: now.Add(w.delivery.Lease()), Valid: true},
+		Now:          sql.NullTime{Time: now, Valid: true},
+		MaxJobs:      int64(free),
+	})
 	if err != nil {
-		if err != sql.ErrNoRows {
-			log.Printf("Error listing pending notification jobs: %v", err)
-		}
+		log.Printf("Error claiming notification jobs: %v", err)
 		return
 	}
 
-	if len(jobs) == 0 {
+	if len(ids) == 0 {
 		return
 	}
 
-	log.Printf("Processing %d pending notification jobs", len(jobs))
+	log.Printf("Processing %d pending notification jobs", len(ids))
+
+	for _, id := range ids {
+		job, err := w.store.Queries.GetNotificationJob(ctx, id)
+		if err != nil {
+			// The job is claimed again once the lease expires
+			log.Printf("Error loading notification job %d: %v", id, err)
+			continue
+		}
 
-	for _, job := range jobs {
-		w.processJob(ctx, job)
+		destination := job.Notification.DestinationArn
+		if !w.acquireDestination(destination) {
+			w.releaseJob(ctx, id)
+			continue
+		}
+
+		w.slots <- struct{}{}
+		w.wg.Add(1)
+		done := w.startSending(id)
+		go func() {
+			defer func() {
+				w.finishSending(id, done)
+				w.releaseDestination(destination)
+				<-w.slots
+				w.wg.Done()
+				// A later job for the same object may be waiting for this one
+				w.Wake()
+			}()
+			w.processJob(ctx, job)
+		}()
 	}
 }
 
-func (w *NotificationWorker) processJob(ctx context.Context, job db.ListPendingNotificationJobsRow) {
-	// Fetch object details
-	object, err := w.store.Queries.GetObjectByID(ctx, job.Event.ObjectID)
+func (w *NotificationWorker) processJob(ctx context.Context, job db.GetNotificationJobRow) {
+	d := w.resolve(job.Notification)
+
+	var payloadBytes []byte
+	var header http.Header
+	var err error
+	if d.format == config.FormatCloudEvents && d.driver != config.DriverEventBridge {
+		payloadBytes, header, err = cloudEvent(job, d)
+	} else {
+		payloadBytes, err = eventPayload(job)
+	}
 	if err != nil {
-		log.Printf("Error fetching object details for job %d: %v", job.NotificationJob.ID, err)
-		w.updateJobStatus(ctx, job.NotificationJob.ID, "failed", job.NotificationJob.Attempts+1, fmt.Sprintf("Failed to fetch object: %v", err))
+		log.Printf("Error marshalling payload for job %d: %v", job.NotificationJob.ID, err)
+		w.retryOrDeadLetter(ctx, job.NotificationJob, fmt.Sprintf("Failed to marshal payload: %v", err), nil)
+		return
+	}
+
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set(DeliveryIDHeader, fmt.Sprintf("%d", job.NotificationJob.ID))
+
+	// Send the payload with the protocol of the destination
+	resp := &response{}
+	deliveryCtx := withTargets(withResponse(ctx, resp), job.NotificationJob)
+	if err := w.deliver(deliveryCtx, d, payloadBytes, header); err != nil {
+		log.Printf("Failed to send notification for job %d to %s: %v", job.NotificationJob.ID, job.Notification.DestinationArn, err)
+		w.retryOrDeadLetter(ctx, job.NotificationJob, err.Error(), resp)
 		return
 	}
 
+	log.Printf("Successfully sent notification for job %d to %s", job.NotificationJob.ID, job.Notification.DestinationArn)
+	w.updateJobStatus(ctx, job.NotificationJob, jobStatusCompleted, job.NotificationJob.Attempts+1, "", resp)
+}
+
+// eventPayload returns the message sent for the event of job: an S3 event
+// notification, the test message for s3:TestEvent, or an EventBridge event
+func eventPayload(job db.GetNotificationJobRow) ([]byte, error) {
+	if job.Event.EventType == event.TestEvent {
+		return json.Marshal(testEvent(job))
+	}
+
+	if job.Notification.DestinationType == config.DriverEventBridge {
+		return eventBridgePayload(job)
+	}
+
+	// Wrap in notification structure
+	notification := S3EventNotification{
+		Records: []S3EventRecord{eventRecord(job)},
+	}
+
+	return json.Marshal(notification)
+}
+
+// testEvent returns the test message for the s3:TestEvent of job
+func testEvent(job db.GetNotificationJobRow) S3TestEvent {
+	return S3TestEvent{
+		Service:   "Amazon S3",
+		Event:     event.TestEvent,
+		Time:      job.Event.EventTime.UTC().Format(time.RFC3339),
+		Bucket:    job.Event.BucketName,
+		RequestID: fmt.Sprintf("%d", job.Event.ID),
+		HostID:    fmt.Sprintf("%016x", job.Event.ID),
+	}
+}
+
+// eventRecord returns the S3 event record for the event of job
+func eventRecord(job db.GetNotificationJobRow) S3EventRecord {
+	// The event carries a snapshot of the object, which may since have been deleted
 	// Build the S3 event record
-	eventRecord := S3EventRecord{
+	record := S3EventRecord{
 		EventVersion: "2.1",
 		EventSource:  "aws:s3",
 		AWSRegion:    "us-east-1", // Default region, could be fetched from bucket config
 		EventTime:    job.Event.EventTime.Format(time.RFC3339),
-		EventName:    job.Event.EventType,
+		EventName:    event.RecordName(job.Event.EventType),
 		UserIdentity: S3UserIdentity{
 			PrincipalID: "s3local",
 		},
@@ -176,72 +322,41 @@ func (w *NotificationWorker) processJob(ctx context.Context, job db.ListPendingN
 				ARN: fmt.Sprintf("arn:aws:s3:::%s", job.Event.BucketName),
 			},
 			Object: S3Object{
-				Key:       object.Object.Key,
-				Size:      object.Object.Size,
-				ETag:      object.Object.ETag,
+				Key:       event.RecordKey(job.Event.ObjectKey),
+				Size:      job.Event.ObjectSize,
+				ETag:      job.Event.ObjectETag,
 				Sequencer: fmt.Sprintf("%016x", job.Event.ID),
 			},
 		},
 	}
 
 	// Add versionId if present
-	if object.Object.VersionID.Valid {
-		eventRecord.S3.Object.VersionID = object.Object.VersionID.String
-	}
-
-	// Wrap in notification structure
-	notification := S3EventNotification{
-		Records: []S3EventRecord{eventRecord},
-	}
-
-	payloadBytes, err := json.Marshal(notification)
-	if err != nil {
-		log.Printf("Error marshalling payload for job %d: %v", job.NotificationJob.ID, err)
-		w.updateJobStatus(ctx, job.NotificationJob.ID, "failed", job.NotificationJob.Attempts+1, fmt.Sprintf("Failed to marshal payload: %v", err))
-		return
-	}
-
-	// Send HTTP POST request to destination ARN
-	req, err := http.NewRequestWithContext(ctx, "POST", job.Notification.DestinationArn, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		log.Printf("Error creating request for job %d: %v", job.NotificationJob.ID, err)
-		w.updateJobStatus(ctx, job.NotificationJob.ID, "failed", job.NotificationJob.Attempts+1, fmt.Sprintf("Failed to create request: %v", err))
-		return
+	if job.Event.ObjectVersionID.Valid {
+		record.S3.Object.VersionID = job.Event.ObjectVersionID.String
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending notification for job %d to %s: %v", job.NotificationJob.ID, job.Notification.DestinationArn, err)
-		w.updateJobStatus(ctx, job.NotificationJob.ID, "failed", job.NotificationJob.Attempts+1, fmt.Sprintf("HTTP request failed: %v", err))
-		return
-	}
-	defer resp.Body.Close()
-
-	// Check response status
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		log.Printf("Successfully sent notification for job %d to %s", job.NotificationJob.ID, job.Notification.DestinationArn)
-		w.updateJobStatus(ctx, job.NotificationJob.ID, "completed", job.NotificationJob.Attempts+1, "")
-	} else {
-		log.Printf("Failed to send notification for job %d to %s: HTTP %d", job.NotificationJob.ID, job.Notification.DestinationArn, resp.StatusCode)
-		w.updateJobStatus(ctx, job.NotificationJob.ID, "failed", job.NotificationJob.Attempts+1, fmt.Sprintf("HTTP %d", resp.StatusCode))
-	}
+	return record
 }
 
-func (w *NotificationWorker) updateJobStatus(ctx context.Context, jobID int64, status string, attempts int64, errorMessage string) {
+// updateJobStatus records the outcome of an attempt of job, unless the job
+// was cancelled or retried meanwhile
+func (w *NotificationWorker) updateJobStatus(ctx context.Context, job db.NotificationJob, status string, attempts int64, errorMessage string, resp *response) {
 	var errorMsg sql.NullString
 	if errorMessage != "" {
 		errorMsg = sql.NullString{String: errorMessage, Valid: true}
 	}
 
+	responseStatus, responseBody := resp.params()
 	err := w.store.Queries.UpdateNotificationJobStatus(ctx, db.UpdateNotificationJobStatusParams{
-		Status:       status,
-		Attempts:     attempts,
-		ErrorMessage: errorMsg,
-		ID:           jobID,
+		Status:         status,
+		Attempts:       attempts,
+		ErrorMessage:   errorMsg,
+		ResponseStatus: responseStatus,
+		ResponseBody:   responseBody,
+		ID:             job.ID,
+		ClaimedUntil:   job.ClaimedUntil,
 	})
 	if err != nil {
-		log.Printf("Error updating job status for job %d: %v", jobID, err)
+		log.Printf("Error updating job status for job %d: %v", job.ID, err)
 	}
 }
@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+// ErrStopped is returned by Tick once the worker is stopped
+var ErrStopped = errors.New("the notification worker is stopped")
+
+// Wake makes the worker look for due jobs now instead of at the next tick
+func (w *NotificationWorker) Wake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Tick claims the jobs that are due now and waits until they and the jobs
+// already being sent are sent, successfully or not. It returns the number of
+// jobs it waited for.
+func (w *NotificationWorker) Tick(ctx context.Context) (int, error) {
+	sent := make(chan int, 1)
+	select {
+	case w.tick <- sent:
+	case <-w.done:
+		return 0, ErrStopped
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+	select {
+	case n := <-sent:
+		return n, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// startSending marks job id as being sent and returns the channel closed by
+// finishSending
+func (w *NotificationWorker) startSending(id int64) chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	done := make(chan struct{})
+	w.sending[id] = done
+	return done
+}
+
+// finishSending marks job id as sent
+func (w *NotificationWorker) finishSending(id int64, done chan struct{}) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.sending, id)
+	close(done)
+}
+
+// awaitSending sends the number of jobs being sent to sent once they are sent
+func (w *NotificationWorker) awaitSending(sent chan<- int) {
+	w.mu.Lock()
+	jobs := make([]chan struct{}, 0, len(w.sending))
+	for _, done := range w.sending {
+		jobs = append(jobs, done)
+	}
+	w.mu.Unlock()
+
+	for _, done := range jobs {
+		<-done
+	}
+	sent <- len(jobs)
+}
+
+// acquireDestination reserves one of the concurrent deliveries to destination.
+// It reports false when DestinationConcurrency deliveries are in progress.
+func (w *NotificationWorker) acquireDestination(destination string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.destinations[destination] >= w.delivery.DestinationConcurrency {
+		return false
+	}
+	w.destinations[destination]++
+	return true
+}
+
+// releaseDestination frees a delivery reserved by acquireDestination
+func (w *NotificationWorker) releaseDestination(destination string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.destinations[destination]--
+	if w.destinations[destination] == 0 {
+		delete(w.destinations, destination)
+	}
+}
+
+// releaseJob gives up the lease of a claimed job that was not sent, so that it
+// can be claimed again right away
+func (w *NotificationWorker) releaseJob(ctx context.Context, jobID int64) {
+	if err := w.store.Queries.ReleaseNotificationJob(ctx, jobID); err != nil {
+		log.Printf("Error releasing notification job %d: %v", jobID, err)
+	}
+}
@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+// blockingDestination records the events it receives and holds every request
+// until release is closed
+type blockingDestination struct {
+	*httptest.Server
+	release chan struct{}
+
+	mu       sync.Mutex
+	received []string
+}
+
+func newBlockingDestination(t *testing.T) *blockingDestination {
+	d := &blockingDestination{release: make(chan struct{})}
+	d.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification S3EventNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err == nil {
+			record := notification.Records[0]
+			d.mu.Lock()
+			d.received = append(d.received, record.S3.Object.Key+" "+record.EventName)
+			d.mu.Unlock()
+		}
+		<-d.release
+	}))
+	t.Cleanup(d.Close)
+	return d
+}
+
+func (d *blockingDestination) events() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.received...)
+}
+
+func TestProcessJobs(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T, destination string, delivery config.DeliveryConfig) (*db.Store, *NotificationWorker) {
+		store := ctx.GetStore(testutil.SetupTestDB(t))
+		err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+			Name:   "test-bucket",
+			Region: "us-east-1",
+		})
+		require.NoError(t, err)
+		_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+			BucketName:      "test-bucket",
+			EventType:       "s3:ObjectCreated:*",
+			DestinationType: "lambda",
+			DestinationArn:  destination,
+			Enabled:         true,
+		})
+		require.NoError(t, err)
+
+		delivery.Timeout = 10 * time.Second
+		delivery.MaxAttempts = 3
+		return store, NewNotificationWorker(store, delivery, nil)
+	}
+
+	record := func(t *testing.T, store *db.Store, eventType, key string) {
+		_, err := event.Record(context.Background(), store.Queries, db.CreateEventParams{
+			BucketName: "test-bucket",
+			EventType:  eventType,
+			ObjectKey:  key,
+		})
+		require.NoError(t, err)
+	}
+
+	claimed := func(t *testing.T, store *db.Store) int {
+		var n int
+		err := store.DB.QueryRow("SELECT COUNT(*) FROM notification_jobs WHERE claimed_until IS NOT NULL").Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+
+	t.Run("Events of an object are sent in order", func(t *testing.T) {
+		t.Parallel()
+		destination := newBlockingDestination(t)
+		store, w := setup(t, destination.URL, config.DeliveryConfig{Workers: 4, DestinationConcurrency: 4})
+
+		record(t, store, event.ObjectCreatedPut, "a")
+		record(t, store, event.ObjectCreatedCopy, "a")
+		record(t, store, event.ObjectCreatedPut, "b")
+
+		// The copy of "a" waits for the put of "a"
+		w.processJobs(context.Background())
+		assert.Equal(t, 2, claimed(t, store))
+
+		// Another worker sharing the database does not claim the leased jobs
+		now := time.Now().UTC()
+		ids, err := store.Queries.ClaimNotificationJobs(context.Background(), db.ClaimNotificationJobsParams{
+			ClaimedUntil: sql.NullTime{Time: now.Add(time.Minute), Valid: true},
+			Now:          sql.NullTime{Time: now, Valid: true},
+			MaxJobs:      10,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, ids)
+
+		close(destination.release)
+		w.wg.Wait()
+		w.processJobs(context.Background())
+		w.wg.Wait()
+
+		received := destination.events()
+		require.Len(t, received, 3)
+		assert.ElementsMatch(t, []string{"a ObjectCreated:Put", "b ObjectCreated:Put"}, received[:2])
+		assert.Equal(t, "a ObjectCreated:Copy", received[2])
+		assert.Equal(t, 0, claimed(t, store))
+	})
+
+	t.Run("Destinations are limited to their concurrency", func(t *testing.T) {
+		t.Parallel()
+		destination := newBlockingDestination(t)
+		store, w := setup(t, destination.URL, config.DeliveryConfig{Workers: 4, DestinationConcurrency: 1})
+
+		record(t, store, event.ObjectCreatedPut, "a")
+		record(t, store, event.ObjectCreatedPut, "b")
+		record(t, store, event.ObjectCreatedPut, "c")
+
+		// Jobs over the limit are released right away
+		w.processJobs(context.Background())
+		assert.Equal(t, 1, claimed(t, store))
+
+		close(destination.release)
+		for range 3 {
+			w.wg.Wait()
+			w.processJobs(context.Background())
+		}
+		w.wg.Wait()
+		assert.Len(t, destination.events(), 3)
+	})
+
+	t.Run("A tick waits until the due jobs are sent", func(t *testing.T) {
+		t.Parallel()
+		destination := newBlockingDestination(t)
+		store, w := setup(t, destination.URL, config.DeliveryConfig{Workers: 4, DestinationConcurrency: 4})
+
+		workerCtx, cancel := context.WithCancel(context.Background())
+		defer func() {
+			cancel()
+			w.wg.Wait()
+		}()
+		go w.Start(workerCtx)
+
+		record(t, store, event.ObjectCreatedPut, "a")
+		record(t, store, event.ObjectCreatedPut, "b")
+
+		ticked := make(chan int)
+		go func() {
+			sent, err := w.Tick(context.Background())
+			assert.NoError(t, err)
+			ticked <- sent
+		}()
+		select {
+		case <-ticked:
+			t.Fatal("Tick returned while the jobs were being sent")
+		case <-time.After(200 * time.Millisecond):
+		}
+
+		close(destination.release)
+		assert.Equal(t, 2, <-ticked)
+		assert.Len(t, destination.events(), 2)
+		assert.Equal(t, 0, claimed(t, store))
+	})
+	t.Run("Stop after the context is cancelled", func(t *testing.T) {
+		t.Parallel()
+		_, w := setup(t, "http://127.0.0.1:1", config.DeliveryConfig{Workers: 1, DestinationConcurrency: 1})
+
+		workerCtx, cancel := context.WithCancel(context.Background())
+		returned := make(chan struct{})
+		go func() {
+			w.Start(workerCtx)
+			close(returned)
+		}()
+		cancel()
+		<-returned
+
+		stopped := make(chan struct{})
+		go func() {
+			w.Stop()
+			w.Stop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("Stop blocked after Start returned")
+		}
+
+		_, err := w.Tick(context.Background())
+		assert.ErrorIs(t, err, ErrStopped)
+	})
+}
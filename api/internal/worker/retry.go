@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+)
+
+// Job statuses besides pending
+const (
+	jobStatusCompleted  = "completed"
+	jobStatusDeadLetter = "dead_letter"
+)
+
+// backoff returns the delay before the attempt following the given number of
+// failed attempts. The delay doubles from InitialBackoff up to MaxBackoff and
+// is randomized to between half and all of it, so jobs failing together do not
+// retry in lockstep.
+func backoff(delivery config.DeliveryConfig, attempts int64) time.Duration {
+	delay := delivery.InitialBackoff
+	for i := int64(1); i < attempts && delay < delivery.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, delivery.MaxBackoff)
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// retryOrDeadLetter records a failed attempt of job and the last response it
+// got, if any. The job is retried after a backoff until it has been attempted
+// MaxAttempts times, then dead-lettered. Nothing is recorded for a job that
+// was cancelled or retried meanwhile.
+func (w *NotificationWorker) retryOrDeadLetter(ctx context.Context, job db.NotificationJob, errorMessage string, resp *response) {
+	attempts := job.Attempts + 1
+	if attempts >= int64(w.delivery.MaxAttempts) {
+		log.Printf("Notification job %d failed %d times, moving it to the dead letter state", job.ID, attempts)
+		w.updateJobStatus(ctx, job, jobStatusDeadLetter, attempts, errorMessage, resp)
+		return
+	}
+
+	nextAttemptAt := time.Now().UTC().Add(backoff(w.delivery, attempts))
+	responseStatus, responseBody := resp.params()
+	err := w.store.Queries.ScheduleNotificationJobRetry(ctx, db.ScheduleNotificationJobRetryParams{
+		Attempts:       attempts,
+		ErrorMessage:   sql.NullString{String: errorMessage, Valid: true},
+		ResponseStatus: responseStatus,
+		ResponseBody:   responseBody,
+		NextAttemptAt:  sql.NullTime{Time: nextAttemptAt, Valid: true},
+		ID:             job.ID,
+		ClaimedUntil:   job.ClaimedUntil,
+	})
+	if err != nil {
+		log.Printf("Error scheduling retry for job %d: %v", job.ID, err)
+	}
+}
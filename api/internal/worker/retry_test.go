@@ -0,0 +1,216 @@
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	delivery := config.DeliveryConfig{
+		InitialBackoff: time.Second,
+		MaxBackoff:     10 * time.Second,
+	}
+	for attempts, expected := range map[int64]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 8 * time.Second,
+		5: 10 * time.Second,
+		9: 10 * time.Second,
+	} {
+		for range 20 {
+			delay := backoff(delivery, attempts)
+			assert.GreaterOrEqual(t, delay, expected/2, attempts)
+			assert.LessOrEqual(t, delay, expected, attempts)
+		}
+	}
+}
+
+func TestRetryOrDeadLetter(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	var failing atomic.Bool
+	failing.Store(true)
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "lambda",
+		DestinationArn:  ts.URL,
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+		BucketName: "test-bucket",
+		EventType:  event.ObjectCreatedPut,
+		ObjectKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	jobStatus := func(t *testing.T) db.NotificationJob {
+		var job db.NotificationJob
+		err := store.DB.QueryRow("SELECT id, status, attempts, error_message, next_attempt_at FROM notification_jobs").
+			Scan(&job.ID, &job.Status, &job.Attempts, &job.ErrorMessage, &job.NextAttemptAt)
+		require.NoError(t, err)
+		return job
+	}
+
+	w := NewNotificationWorker(store, config.DeliveryConfig{
+		Workers:                1,
+		DestinationConcurrency: 1,
+		Timeout:                time.Second,
+		MaxAttempts:            3,
+		InitialBackoff:         time.Hour,
+		MaxBackoff:             time.Hour,
+	}, nil)
+	processJobs := func() {
+		w.processJobs(context.Background())
+		w.wg.Wait()
+	}
+
+	// A failed attempt schedules a retry and the job is not due before then
+	processJobs()
+	job := jobStatus(t)
+	assert.Equal(t, "pending", job.Status)
+	assert.Equal(t, int64(1), job.Attempts)
+	assert.Equal(t, "HTTP 503", job.ErrorMessage.String)
+	var responseStatus int
+	require.NoError(t, store.DB.QueryRow("SELECT response_status FROM notification_jobs").Scan(&responseStatus))
+	assert.Equal(t, http.StatusServiceUnavailable, responseStatus)
+	require.True(t, job.NextAttemptAt.Valid)
+	assert.True(t, job.NextAttemptAt.Time.After(time.Now().Add(29*time.Minute)))
+
+	processJobs()
+	assert.Equal(t, int32(1), requests.Load())
+
+	// Without a backoff the job is retried until it is dead-lettered
+	w.delivery.InitialBackoff = 0
+	_, err = store.DB.Exec("UPDATE notification_jobs SET next_attempt_at = NULL")
+	require.NoError(t, err)
+	processJobs()
+	processJobs()
+	processJobs()
+	assert.Equal(t, int32(3), requests.Load())
+	job = jobStatus(t)
+	assert.Equal(t, "dead_letter", job.Status)
+	assert.Equal(t, int64(3), job.Attempts)
+
+	// Requeued jobs are delivered again
+	requeued, err := store.Queries.RequeueDeadLetterNotificationJobs(context.Background(), sql.NullString{String: "other-bucket", Valid: true})
+	require.NoError(t, err)
+	assert.Zero(t, requeued)
+	requeued, err = store.Queries.RequeueDeadLetterNotificationJobs(context.Background(), sql.NullString{String: "test-bucket", Valid: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), requeued)
+
+	failing.Store(false)
+	processJobs()
+	job = jobStatus(t)
+	assert.Equal(t, "completed", job.Status)
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestRetryDuringAttempt(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+			<-release
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "lambda",
+		DestinationArn:  ts.URL,
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	e, err := event.Record(context.Background(), store.Queries, db.CreateEventParams{
+		BucketName: "test-bucket",
+		EventType:  event.ObjectCreatedPut,
+		ObjectKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	w := NewNotificationWorker(store, config.DeliveryConfig{
+		Workers:                1,
+		DestinationConcurrency: 1,
+		Timeout:                5 * time.Second,
+		MaxAttempts:            3,
+		InitialBackoff:         time.Hour,
+		MaxBackoff:             time.Hour,
+	}, nil)
+	job := func(t *testing.T) db.NotificationJob {
+		jobs, err := store.Queries.ListNotificationJobsByEvent(context.Background(), e.ID)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		return jobs[0].NotificationJob
+	}
+
+	// The job is retried while its first attempt is in progress
+	w.processJobs(context.Background())
+	<-started
+	retried, err := store.Queries.RetryNotificationJob(context.Background(), job(t).ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), retried)
+	close(release)
+	w.wg.Wait()
+
+	// The failure of that attempt is not recorded, and the job is due at once
+	j := job(t)
+	assert.Equal(t, "pending", j.Status)
+	assert.Zero(t, j.Attempts)
+	assert.False(t, j.ErrorMessage.Valid)
+	assert.False(t, j.ClaimedUntil.Valid)
+	assert.False(t, j.NextAttemptAt.Valid)
+
+	w.processJobs(context.Background())
+	w.wg.Wait()
+	j = job(t)
+	assert.Equal(t, "completed", j.Status)
+	assert.Equal(t, int64(1), j.Attempts)
+	assert.Equal(t, int32(2), requests.Load())
+}
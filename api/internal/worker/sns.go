@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// snsAPIVersion is the API version of Publish requests
+	snsAPIVersion = "2010-03-31"
+	// snsSubject is the subject S3 gives the messages it publishes
+	snsSubject = "Amazon S3 Notification"
+)
+
+// publishSNSMessage publishes payload as the message of a notification to the
+// topic d
+func (w *NotificationWorker) publishSNSMessage(ctx context.Context, d destination, payload []byte) error {
+	if _, _, err := splitARN(d.arn, "sns"); err != nil {
+		return err
+	}
+	endpoint, err := serviceEndpoint(d.endpoint, "SNS", d.arn)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"Action":   {"Publish"},
+		"Version":  {snsAPIVersion},
+		"TopicArn": {d.arn},
+		"Subject":  {snsSubject},
+		"Message":  {string(payload)},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("Failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return w.send(req)
+}
@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+)
+
+func TestPublishSNSMessage(t *testing.T) {
+	t.Parallel()
+
+	var form url.Values
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-www-form-urlencoded", r.Header.Get("Content-Type"))
+		assert.NoError(t, r.ParseForm())
+		form = r.PostForm
+	}))
+	defer ts.Close()
+
+	w := NewNotificationWorker(nil, config.DeliveryConfig{Timeout: 10 * time.Second}, nil)
+	topic := "arn:aws:sns:us-east-1:000000000000:test-topic"
+	d := destination{arn: topic, driver: config.DriverSNS, endpoint: ts.URL}
+	require.NoError(t, w.publishSNSMessage(context.Background(), d, []byte(`{"Records":[]}`)))
+
+	assert.Equal(t, "Publish", form.Get("Action"))
+	assert.Equal(t, topic, form.Get("TopicArn"))
+	assert.Equal(t, "Amazon S3 Notification", form.Get("Subject"))
+	assert.Equal(t, `{"Records":[]}`, form.Get("Message"))
+
+	d.arn = "arn:aws:sqs:us-east-1:000000000000:test-queue"
+	err := w.publishSNSMessage(context.Background(), d, nil)
+	assert.ErrorContains(t, err, "invalid sns ARN")
+}
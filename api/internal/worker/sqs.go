@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/tkasuz/s3local/internal/config"
+)
+
+// sqsAPIVersion is the API version sent with query protocol requests
+const sqsAPIVersion = "2012-11-05"
+
+// sqsSendMessageInput is the body of a JSON protocol SendMessage request
+type sqsSendMessageInput struct {
+	QueueUrl    string `json:"QueueUrl"`
+	MessageBody string `json:"MessageBody"`
+}
+
+// sendSQSMessage sends payload as the body of a message to the queue d, a
+// queue ARN or queue URL
+func (w *NotificationWorker) sendSQSMessage(ctx context.Context, d destination, payload []byte) error {
+	queueURL, err := sqsQueueURL(d.endpoint, d.arn)
+	if err != nil {
+		return err
+	}
+
+	var req *http.Request
+	switch d.protocol {
+	case config.SQSProtocolQuery:
+		form := url.Values{
+			"Action":      {"SendMessage"},
+			"Version":     {sqsAPIVersion},
+			"QueueUrl":    {queueURL.String()},
+			"MessageBody": {string(payload)},
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, queueURL.String(), strings.NewReader(form.Encode()))
+		if err != nil {
+			return fmt.Errorf("Failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	default:
+		body, err := json.Marshal(sqsSendMessageInput{
+			QueueUrl:    queueURL.String(),
+			MessageBody: string(payload),
+		})
+		if err != nil {
+			return err
+		}
+		// JSON protocol requests go to the service endpoint, not the queue
+		endpoint := url.URL{Scheme: queueURL.Scheme, Host: queueURL.Host, Path: "/"}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("Failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/x-amz-json-1.0")
+		req.Header.Set("X-Amz-Target", "AmazonSQS.SendMessage")
+	}
+	return w.send(req)
+}
+
+// sqsQueueURL returns the URL of the queue destination. A queue ARN
+// arn:aws:sqs:{region}:{account}:{name} maps to {endpoint}/{account}/{name};
+// an http(s) URL is the queue URL itself.
+func sqsQueueURL(endpoint, destination string) (*url.URL, error) {
+	if isURL(destination) {
+		return url.Parse(destination)
+	}
+
+	account, name, err := splitARN(destination, "sqs")
+	if err != nil {
+		return nil, err
+	}
+	base, err := serviceEndpoint(endpoint, "SQS", destination)
+	if err != nil {
+		return nil, err
+	}
+	return base.JoinPath(account, name), nil
+}
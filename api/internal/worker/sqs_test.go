@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+const testQueueArn = "arn:aws:sqs:us-east-1:000000000000:test-queue"
+
+// sqsMessage is a SendMessage request received by a fake SQS endpoint
+type sqsMessage struct {
+	Path        string
+	QueueUrl    string
+	MessageBody string
+}
+
+func TestSendSQSMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, protocol := range []string{config.SQSProtocolJSON, config.SQSProtocolQuery} {
+		t.Run(protocol, func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			var messages []sqsMessage
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				message := sqsMessage{Path: r.URL.Path}
+				switch protocol {
+				case config.SQSProtocolJSON:
+					assert.Equal(t, "application/x-amz-json-1.0", r.Header.Get("Content-Type"))
+					assert.Equal(t, "AmazonSQS.SendMessage", r.Header.Get("X-Amz-Target"))
+					assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+				case config.SQSProtocolQuery:
+					assert.NoError(t, r.ParseForm())
+					assert.Equal(t, "SendMessage", r.PostForm.Get("Action"))
+					message.QueueUrl = r.PostForm.Get("QueueUrl")
+					message.MessageBody = r.PostForm.Get("MessageBody")
+				}
+				mu.Lock()
+				messages = append(messages, message)
+				mu.Unlock()
+			}))
+			defer ts.Close()
+
+			store := ctx.GetStore(testutil.SetupTestDB(t))
+			err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+				Name:   "test-bucket",
+				Region: "us-east-1",
+			})
+			require.NoError(t, err)
+			notification, err := store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+				BucketName:      "test-bucket",
+				EventType:       "s3:ObjectCreated:*",
+				DestinationType: "sqs",
+				DestinationArn:  testQueueArn,
+				Enabled:         true,
+			})
+			require.NoError(t, err)
+
+			require.NoError(t, event.RecordTest(context.Background(), store.Queries, notification))
+			_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+				BucketName: "test-bucket",
+				EventType:  event.ObjectCreatedPut,
+				ObjectKey:  "test-key",
+			})
+			require.NoError(t, err)
+
+			w := NewNotificationWorker(store, config.DeliveryConfig{
+				Workers:                4,
+				DestinationConcurrency: 4,
+				Timeout:                10 * time.Second,
+				MaxAttempts:            1,
+				SQS:                    config.SQSConfig{Endpoint: ts.URL, Protocol: protocol},
+			}, nil)
+			w.processJobs(context.Background())
+			w.wg.Wait()
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Len(t, messages, 2)
+
+			queueURL := ts.URL + "/000000000000/test-queue"
+			var testEvent S3TestEvent
+			var notificationEvent S3EventNotification
+			for _, message := range messages {
+				assert.Equal(t, queueURL, message.QueueUrl)
+				if protocol == config.SQSProtocolJSON {
+					assert.Equal(t, "/", message.Path)
+				} else {
+					assert.Equal(t, "/000000000000/test-queue", message.Path)
+				}
+				if strings.Contains(message.MessageBody, event.TestEvent) {
+					require.NoError(t, json.Unmarshal([]byte(message.MessageBody), &testEvent))
+					continue
+				}
+				require.NoError(t, json.Unmarshal([]byte(message.MessageBody), &notificationEvent))
+			}
+
+			assert.Equal(t, "Amazon S3", testEvent.Service)
+			assert.Equal(t, event.TestEvent, testEvent.Event)
+			assert.Equal(t, "test-bucket", testEvent.Bucket)
+			require.Len(t, notificationEvent.Records, 1)
+			assert.Equal(t, "ObjectCreated:Put", notificationEvent.Records[0].EventName)
+			assert.Equal(t, "test-key", notificationEvent.Records[0].S3.Object.Key)
+
+			var pending int
+			err = store.DB.QueryRow("SELECT COUNT(*) FROM notification_jobs WHERE status != 'completed'").Scan(&pending)
+			require.NoError(t, err)
+			assert.Zero(t, pending)
+		})
+	}
+}
+
+func TestSQSQueueURL(t *testing.T) {
+	t.Parallel()
+
+	u, err := sqsQueueURL("http://elasticmq:9324", "arn:aws:sqs:us-east-1:000000000000:my-queue")
+	require.NoError(t, err)
+	assert.Equal(t, "http://elasticmq:9324/000000000000/my-queue", u.String())
+
+	// Queue URLs are used as they are
+	u, err = sqsQueueURL("", "http://localhost:9324/queue/my-queue")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9324/queue/my-queue", u.String())
+
+	_, err = sqsQueueURL("", "arn:aws:sqs:us-east-1:000000000000:my-queue")
+	assert.ErrorContains(t, err, "no SQS endpoint configured")
+
+	_, err = sqsQueueURL("http://elasticmq:9324", "arn:aws:sns:us-east-1:000000000000:my-topic")
+	assert.ErrorContains(t, err, "invalid sqs ARN")
+}
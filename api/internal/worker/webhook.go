@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// Headers of events POSTed to a destination
+const (
+	// DeliveryIDHeader identifies the notification job an event is delivered
+	// for. It stays the same when a delivery is retried.
+	DeliveryIDHeader = "X-S3local-Delivery-Id"
+	// SignatureHeader carries the HMAC-SHA256 of the payload keyed with the
+	// secret of the destination, as sha256={hex digest}
+	SignatureHeader = "X-S3local-Signature"
+)
+
+// webhookHeader returns header with the static headers, bearer token and
+// payload signature configured for destination d added
+func (d destination) webhookHeader(header http.Header, payload []byte) http.Header {
+	header = header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for name, value := range d.headers {
+		header.Set(name, value)
+	}
+	if d.bearerToken != "" {
+		header.Set("Authorization", "Bearer "+d.bearerToken)
+	}
+	if d.secret != "" {
+		header.Set(SignatureHeader, Signature(d.secret, payload))
+	}
+	return header
+}
+
+// Signature returns the value of the signature header sent with payload to a
+// destination configured with secret. Receivers compare it with the header
+// using hmac.Equal.
+func Signature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
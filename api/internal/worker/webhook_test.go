@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+)
+
+func TestWebhookHeaders(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	var mu sync.Mutex
+	var headers []http.Header
+	var bodies [][]byte
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		headers, bodies = append(headers, r.Header), append(bodies, body)
+		// Fail the first attempt so that the delivery is retried
+		if len(headers) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "test-bucket",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "test-bucket",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "lambda",
+		DestinationArn:  "arn:aws:lambda:us-east-1:000000000000:function:hook",
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+		BucketName: "test-bucket",
+		EventType:  event.ObjectCreatedPut,
+		ObjectKey:  "test-key",
+	})
+	require.NoError(t, err)
+
+	w := NewNotificationWorker(store, config.DeliveryConfig{
+		Workers:                1,
+		DestinationConcurrency: 1,
+		Timeout:                time.Second,
+		MaxAttempts:            3,
+	}, config.Destinations{{
+		ARN:         "arn:aws:lambda:*:*:function:hook",
+		Driver:      config.DriverWebhook,
+		Endpoint:    ts.URL,
+		Headers:     map[string]string{"X-Environment": "test"},
+		BearerToken: "token",
+		Secret:      "secret",
+	}})
+	for range 2 {
+		w.processJobs(context.Background())
+		w.wg.Wait()
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, headers, 2)
+	for i, header := range headers {
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+		assert.Equal(t, "test", header.Get("X-Environment"))
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+		assert.True(t, hmac.Equal([]byte(Signature("secret", bodies[i])), []byte(header.Get(SignatureHeader))))
+		assert.NotEmpty(t, header.Get(DeliveryIDHeader))
+	}
+	assert.Equal(t, "sha256=", Signature("secret", bodies[0])[:7])
+
+	// Retries are delivered with the same delivery ID
+	assert.Equal(t, headers[0].Get(DeliveryIDHeader), headers[1].Get(DeliveryIDHeader))
+}
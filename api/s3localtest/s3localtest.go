@@ -0,0 +1,179 @@
+// Package s3localtest lets integration tests wait for the events of an
+// S3Local server and their delivery instead of sleeping:
+//
+//	client := s3localtest.NewClient("http://localhost:8080")
+//	e, err := client.AwaitEvent(ctx, s3localtest.AwaitInput{
+//		Bucket:    "photos",
+//		Key:       "user/*.jpg",
+//		EventType: "s3:ObjectCreated:*",
+//		Delivered: true,
+//	})
+package s3localtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is an event recorded by the server, with the jobs delivering it, as
+// returned by the admin API
+type Event struct {
+	ID              int64     `json:"id"`
+	Bucket          string    `json:"bucket"`
+	EventType       string    `json:"event_type"`
+	EventTime       time.Time `json:"event_time"`
+	ObjectKey       string    `json:"object_key,omitempty"`
+	ObjectSize      int64     `json:"object_size"`
+	ObjectETag      string    `json:"object_etag,omitempty"`
+	ObjectVersionID string    `json:"object_version_id,omitempty"`
+	Jobs            []Job     `json:"jobs"`
+}
+
+// Job is a notification job delivering an event to a destination. Status is
+// pending, completed, dead_letter or cancelled.
+type Job struct {
+	ID               int64      `json:"id"`
+	EventID          int64      `json:"event_id"`
+	Bucket           string     `json:"bucket"`
+	EventType        string     `json:"event_type"`
+	ObjectKey        string     `json:"object_key,omitempty"`
+	DestinationType  string     `json:"destination_type"`
+	DestinationArn   string     `json:"destination_arn"`
+	Status           string     `json:"status"`
+	Attempts         int64      `json:"attempts"`
+	ErrorMessage     string     `json:"error_message,omitempty"`
+	ResponseStatus   int64      `json:"response_status,omitempty"`
+	ResponseBody     string     `json:"response_body,omitempty"`
+	DeliveredTargets []string   `json:"delivered_targets,omitempty"`
+	NextAttemptAt    *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// ErrTimeout is returned by AwaitEvent when no matching event was recorded,
+// or delivered, in time
+var ErrTimeout = errors.New("s3localtest: timed out awaiting event")
+
+// Client calls the admin API of the S3Local server at Endpoint. When
+// AccessKeyID is set the requests are signed with AWS Signature Version 4, as
+// the server requires once authentication is enabled.
+type Client struct {
+	Endpoint        string
+	HTTPClient      *http.Client
+	AccessKeyID     string
+	SecretAccessKey string
+	// Region defaults to us-east-1
+	Region string
+}
+
+// NewClient returns a client of the S3Local server at endpoint
+func NewClient(endpoint string) *Client {
+	return &Client{
+		Endpoint:   strings.TrimSuffix(endpoint, "/"),
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// AwaitInput selects the event AwaitEvent waits for. Empty fields match any
+// event.
+type AwaitInput struct {
+	Bucket string
+	// Key is a path.Match pattern of the object key, e.g. user/*.jpg
+	Key string
+	// EventType is an event type or a family such as s3:ObjectCreated:*
+	EventType string
+	// After skips the events up to the event with this ID, e.g. one returned
+	// by an earlier call
+	After int64
+	// Delivered waits until every job of the event is completed,
+	// dead-lettered or cancelled
+	Delivered bool
+	// Timeout defaults to 10 seconds
+	Timeout time.Duration
+}
+
+// AwaitEvent returns the oldest matching event once it is recorded, or
+// delivered with in.Delivered, and ErrTimeout if that does not happen within
+// in.Timeout
+func (c *Client) AwaitEvent(ctx context.Context, in AwaitInput) (*Event, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"bucket":     in.Bucket,
+		"key":        in.Key,
+		"event_type": in.EventType,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	if in.After != 0 {
+		query.Set("after", strconv.FormatInt(in.After, 10))
+	}
+	if in.Delivered {
+		query.Set("delivered", "true")
+	}
+	if in.Timeout != 0 {
+		query.Set("timeout", in.Timeout.String())
+	}
+
+	var e Event
+	if err := c.do(ctx, http.MethodGet, "/_s3local/events/await?"+query.Encode(), &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Tick makes the notification worker send the jobs that are due now instead
+// of at its next tick, and returns the number of jobs it sent once they are
+// sent. Jobs scheduled for a retry are not due until their next attempt.
+func (c *Client) Tick(ctx context.Context) (int, error) {
+	var result struct {
+		Sent int `json:"sent"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/_s3local/worker/tick", &result); err != nil {
+		return 0, err
+	}
+	return result.Sent, nil
+}
+
+// do sends a request to the admin API and decodes the JSON response into v
+func (c *Client) do(ctx context.Context, method, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.Endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.AccessKeyID != "" {
+		c.sign(req, time.Now())
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusRequestTimeout {
+		return ErrTimeout
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var body struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		if json.Unmarshal(data, &body) != nil || body.Error == "" {
+			body.Error = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("s3localtest: %s %s: HTTP %d: %s", method, path, resp.StatusCode, body.Error)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,160 @@
+package s3localtest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tkasuz/s3local/internal/auth"
+	"github.com/tkasuz/s3local/internal/config"
+	"github.com/tkasuz/s3local/internal/db"
+	"github.com/tkasuz/s3local/internal/event"
+	"github.com/tkasuz/s3local/internal/handlers/admin"
+	"github.com/tkasuz/s3local/internal/handlers/ctx"
+	"github.com/tkasuz/s3local/internal/testutil"
+	"github.com/tkasuz/s3local/internal/worker"
+	"github.com/tkasuz/s3local/s3localtest"
+)
+
+func TestAwaitEvent(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	var received atomic.Int32
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+	}))
+	defer destination.Close()
+
+	// The worker polls every second, so quick delivery relies on the wake
+	notificationWorker := worker.NewNotificationWorker(store, config.DeliveryConfig{
+		Workers:                1,
+		DestinationConcurrency: 1,
+		Timeout:                time.Second,
+		MaxAttempts:            1,
+	}, nil)
+	workerCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notificationWorker.Start(workerCtx)
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Use(ctx.WithWorker(notificationWorker))
+	r.Get("/_s3local/events/await", admin.AwaitEvent)
+	r.Post("/_s3local/worker/tick", admin.Tick)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	err := store.Queries.CreateBucket(context.Background(), db.CreateBucketParams{
+		Name:   "photos",
+		Region: "us-east-1",
+	})
+	require.NoError(t, err)
+	_, err = store.Queries.CreateNotification(context.Background(), db.CreateNotificationParams{
+		BucketName:      "photos",
+		EventType:       "s3:ObjectCreated:*",
+		DestinationType: "lambda",
+		DestinationArn:  destination.URL,
+		Enabled:         true,
+	})
+	require.NoError(t, err)
+	_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+		BucketName: "photos",
+		EventType:  event.ObjectCreatedPut,
+		ObjectKey:  "user/cat.jpg",
+	})
+	require.NoError(t, err)
+
+	client := s3localtest.NewClient(ts.URL)
+	start := time.Now()
+	e, err := client.AwaitEvent(context.Background(), s3localtest.AwaitInput{
+		Bucket:    "photos",
+		Key:       "user/*.jpg",
+		EventType: "s3:ObjectCreated:*",
+		Delivered: true,
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "user/cat.jpg", e.ObjectKey)
+	require.Len(t, e.Jobs, 1)
+	assert.Equal(t, "completed", e.Jobs[0].Status)
+	assert.Equal(t, int32(1), received.Load())
+
+	// Later events only
+	_, err = client.AwaitEvent(context.Background(), s3localtest.AwaitInput{
+		After:   e.ID,
+		Timeout: 300 * time.Millisecond,
+	})
+	assert.ErrorIs(t, err, s3localtest.ErrTimeout)
+
+	_, err = client.AwaitEvent(context.Background(), s3localtest.AwaitInput{Key: "["})
+	assert.ErrorContains(t, err, "invalid key pattern")
+
+	// A tick returns once the jobs due are delivered
+	_, err = event.Record(context.Background(), store.Queries, db.CreateEventParams{
+		BucketName: "photos",
+		EventType:  event.ObjectCreatedPut,
+		ObjectKey:  "user/dog.jpg",
+	})
+	require.NoError(t, err)
+	_, err = client.Tick(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), received.Load())
+}
+
+// The types of the package decode every field of the admin API responses
+func TestTypes(t *testing.T) {
+	t.Parallel()
+
+	tags := func(v any) []string {
+		var tags []string
+		typ := reflect.TypeOf(v)
+		for i := range typ.NumField() {
+			tags = append(tags, typ.Field(i).Name+" "+typ.Field(i).Tag.Get("json"))
+		}
+		return tags
+	}
+	assert.Equal(t, tags(admin.Event{}), tags(s3localtest.Event{}))
+	assert.Equal(t, tags(admin.Job{}), tags(s3localtest.Job{}))
+}
+
+func TestSignedClient(t *testing.T) {
+	t.Parallel()
+	store := ctx.GetStore(testutil.SetupTestDB(t))
+
+	r := chi.NewRouter()
+	r.Use(ctx.WithStore(store))
+	r.Use(auth.WithSigV4(config.AuthConfig{
+		Credentials: []config.Credential{{AccessKeyID: "AKID", SecretAccessKey: "secret"}},
+	}))
+	r.Get("/_s3local/events/await", admin.AwaitEvent)
+	ts := httptest.NewServer(r)
+	defer ts.Close()
+
+	// The query string needs encoding to be signed
+	in := s3localtest.AwaitInput{
+		Key:       "user/my cat*.jpg",
+		EventType: "s3:ObjectCreated:*",
+		Timeout:   100 * time.Millisecond,
+	}
+
+	client := s3localtest.NewClient(ts.URL)
+	client.AccessKeyID = "AKID"
+	client.SecretAccessKey = "secret"
+	_, err := client.AwaitEvent(context.Background(), in)
+	assert.ErrorIs(t, err, s3localtest.ErrTimeout)
+
+	client.SecretAccessKey = "wrong"
+	_, err = client.AwaitEvent(context.Background(), in)
+	assert.ErrorContains(t, err, "HTTP 403")
+
+	_, err = s3localtest.NewClient(ts.URL).AwaitEvent(context.Background(), in)
+	assert.ErrorContains(t, err, "HTTP 403")
+}
@@ -23,9 +23,14 @@ resource "aws_s3_bucket_notification" "bucket_notification" {
   bucket = aws_s3_bucket.example.id
 
   lambda_function {
-    lambda_function_arn = "http://lambda:8081/2015-03-31/functions/function/invocations"
+    lambda_function_arn = "arn:aws:lambda:ap-northeast-1:000000000000:function:function"
     events              = ["s3:ObjectCreated:*"]
     filter_prefix       = "AWSLogs/"
     filter_suffix       = ".log"
   }
+
+  queue {
+    queue_arn = "arn:aws:sqs:ap-northeast-1:000000000000:s3-notifications"
+    events    = ["s3:ObjectRemoved:*"]
+  }
 }